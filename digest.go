@@ -0,0 +1,13 @@
+package function
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/functions/digest"
+)
+
+// Digest is the Cloud Function entry point for the Pub/Sub triggered monthly digest
+func Digest(ctx context.Context, e event.Event) error {
+	return digest.HandleDigest(ctx, e)
+}
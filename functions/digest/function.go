@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/pubsub"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+var globalConfig *config.Config
+
+func init() {
+	functions.CloudEvent("Digest", handleDigest)
+
+	cfg, err := config.LoadConfig(os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
+	globalConfig = cfg
+}
+
+// DigestRequest is the JSON payload accepted in the Pub/Sub message
+type DigestRequest struct {
+	// Period is the month to summarize as YYYY-MM, defaults to the previous month
+	Period string `json:"period"`
+}
+
+func handleDigest(ctx context.Context, e event.Event) error {
+	defer tracing.Flush(ctx)
+	ctx, span := tracing.Start(ctx, "Digest")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+	ctx = logging.WithAttrs(ctx, slog.String("event_id", e.ID()))
+	defer metrics.Flush(ctx)
+	slog.InfoContext(ctx, "Digest function triggered", "event_time", e.Time())
+
+	// Invalid messages are dropped, Pub/Sub would redeliver them forever
+	var request DigestRequest
+	if err := pubsub.DecodeMessage(e, &request); err != nil {
+		slog.ErrorContext(ctx, "Error decoding digest request", "error", err)
+		return nil
+	}
+
+	from, to, err := services.DigestPeriod(request.Period, time.Now(), globalConfig.Location)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing digest period", "period", request.Period, "error", err)
+		return nil
+	}
+
+	// Admins may have moved the channels with /elogie admin
+	cfg, settings := services.ApplySettings(ctx, globalConfig)
+	if settings.DigestChannelID != "" {
+		cfg.SlackChannelID = settings.DigestChannelID
+	}
+
+	digest, err := services.BuildDigest(ctx, from, to, cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error building digest", "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "Digest built", "period", services.FormatMonth(from), "total", digest.Total, "newcomers", len(digest.Newcomers))
+
+	err = services.PostDigest(ctx, digest, cfg)
+	if errors.Is(err, services.ErrDigestPosted) {
+		slog.InfoContext(ctx, "Skipping digest already posted", "period", services.FormatMonth(from), "error", err)
+		return nil
+	}
+	return err
+}
+
+// HandleDigest is the exported function for the Cloud Function entry point
+func HandleDigest(ctx context.Context, e event.Event) error {
+	return handleDigest(ctx, e)
+}
@@ -0,0 +1,107 @@
+package gapalert
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/pubsub"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+var globalConfig *config.Config
+
+func init() {
+	functions.CloudEvent("GapAlert", handleGapAlert)
+
+	cfg, err := config.LoadConfig(os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
+	globalConfig = cfg
+}
+
+// GapAlertRequest is the JSON payload accepted in the Pub/Sub message
+type GapAlertRequest struct {
+	// Weeks without kudos before a person is listed, defaults to KUDOS_GAP_WEEKS
+	Weeks int `json:"weeks"`
+}
+
+func handleGapAlert(ctx context.Context, e event.Event) error {
+	defer tracing.Flush(ctx)
+	ctx, span := tracing.Start(ctx, "GapAlert")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+	ctx = logging.WithAttrs(ctx, slog.String("event_id", e.ID()))
+	defer metrics.Flush(ctx)
+	slog.InfoContext(ctx, "Gap alert function triggered", "event_time", e.Time())
+
+	// Invalid messages are dropped, Pub/Sub would redeliver them forever
+	var request GapAlertRequest
+	if err := pubsub.DecodeMessage(e, &request); err != nil {
+		slog.ErrorContext(ctx, "Error decoding gap alert request", "error", err)
+		return nil
+	}
+
+	weeks := globalConfig.GapWeeks
+	if request.Weeks < 0 {
+		slog.ErrorContext(ctx, "Invalid weeks in gap alert request", "weeks", request.Weeks)
+		return nil
+	}
+	if request.Weeks > 0 {
+		weeks = request.Weeks
+	}
+
+	if len(globalConfig.Teams) == 0 {
+		slog.InfoContext(ctx, "No teams configured, skipping gap alerts")
+		return nil
+	}
+
+	since := time.Now().AddDate(0, 0, -7*weeks)
+	gaps, err := services.FindRecognitionGaps(ctx, since, globalConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error finding recognition gaps", "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "Found managers with unrecognized team members", "managers", len(gaps), "weeks", weeks)
+
+	successCount := 0
+	errorCount := 0
+
+	for _, gap := range gaps {
+		err := services.SendGapAlert(ctx, globalConfig.SlackAPI, gap, weeks)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to send gap alert", "manager_id", gap.ManagerID, "error", err)
+			errorCount++
+		} else {
+			slog.DebugContext(ctx, "Sent gap alert", "manager_id", gap.ManagerID, "people", len(gap.UserIDs))
+			successCount++
+		}
+	}
+
+	slog.InfoContext(ctx, "Gap alerts complete", "success", successCount, "errors", errorCount)
+
+	// Return nil even if some DMs failed - we don't want to retry for partial failures
+	return nil
+}
+
+// HandleGapAlert is the exported function for the Cloud Function entry point
+func HandleGapAlert(ctx context.Context, e event.Event) error {
+	return handleGapAlert(ctx, e)
+}
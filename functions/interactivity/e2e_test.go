@@ -0,0 +1,365 @@
+package interactivity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/slacktest"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+// useFakeSlack points the function at a fake Slack API for the duration of the test
+func useFakeSlack(t *testing.T) *slacktest.Server {
+	t.Helper()
+
+	server := slacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	cfg, err := config.LoadConfig(server.Getenv)
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+
+	previous := globalConfig
+	globalConfig = cfg
+	t.Cleanup(func() { globalConfig = previous })
+	return server
+}
+
+// signedCallback builds the request Slack sends for an interaction
+func signedCallback(t *testing.T, callback slack.InteractionCallback) *http.Request {
+	t.Helper()
+
+	payload, err := json.Marshal(callback)
+	if err != nil {
+		t.Fatalf("error encoding callback: %v", err)
+	}
+	return slacktest.NewSignedRequest(slacktest.SigningSecret, url.Values{"payload": {string(payload)}})
+}
+
+// submission is a filled kudos modal
+func submission(recipients ...string) slack.InteractionCallback {
+	return slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U111111"},
+		Team: slack.Team{ID: "T123456"},
+		View: slack.View{
+			ID: "V123456",
+			State: &slack.ViewState{
+				Values: map[string]map[string]slack.BlockAction{
+					"kudo_users": {"kudo_users": {SelectedUsers: recipients}},
+					"kudo_type": {"kudo_type": {SelectedOption: slack.OptionBlockObject{
+						Value: "espirito-de-equipe",
+						Text:  &slack.TextBlockObject{Type: "plain_text", Text: ":handshake: Espírito de Equipe"},
+					}}},
+					"kudo_message": {"kudo_message": {Value: "Obrigado pela ajuda no deploy!"}},
+				},
+			},
+		},
+	}
+}
+
+func TestE2E_InteractivityKudoTypeUpdatesModal(t *testing.T) {
+	server := useFakeSlack(t)
+
+	req := signedCallback(t, slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U111111"},
+		View: slack.View{
+			ID:    "V123456",
+			Hash:  "hash-1",
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{}},
+		},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: "kudo_type", SelectedOption: slack.OptionBlockObject{Value: "espirito-de-equipe"}},
+		}},
+	})
+	w := httptest.NewRecorder()
+
+	HandleInteractivity(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	calls := server.Calls("views.update")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d views.update calls, want 1", len(calls))
+	}
+	if calls[0].JSON["view_id"] != "V123456" || calls[0].JSON["hash"] != "hash-1" {
+		t.Errorf("views.update view_id = %v, hash = %v, want the callback's view", calls[0].JSON["view_id"], calls[0].JSON["hash"])
+	}
+	body, _ := json.Marshal(calls[0].JSON["view"])
+	if !strings.Contains(string(body), "kudo_description") {
+		t.Errorf("updated view = %s, want the type description block", body)
+	}
+}
+
+func TestE2E_InteractivityEmojiSuggestions(t *testing.T) {
+	server := useFakeSlack(t)
+	server.AddEmoji("partyparrot", "https://emoji.example.com/partyparrot.gif")
+
+	req := signedCallback(t, slack.InteractionCallback{
+		Type:     slack.InteractionTypeBlockSuggestion,
+		User:     slack.User{ID: "U111111"},
+		ActionID: "kudo_emoji",
+		Value:    "parrot",
+	})
+	w := httptest.NewRecorder()
+
+	HandleInteractivity(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if len(server.Calls("emoji.list")) != 1 {
+		t.Errorf("recorded %d emoji.list calls, want 1", len(server.Calls("emoji.list")))
+	}
+	if !strings.Contains(w.Body.String(), `"value":":partyparrot:"`) {
+		t.Errorf("response = %s, want the workspace emoji", w.Body.String())
+	}
+}
+
+func TestE2E_InteractivityCustomTypeOfferedToTeam(t *testing.T) {
+	server := useFakeSlack(t)
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+	globalConfig.Store = storage.NewMemoryStore()
+
+	custom := submission("U222222")
+	custom.View.State.Values["kudo_type"] = map[string]slack.BlockAction{"kudo_type": {SelectedOption: slack.OptionBlockObject{
+		Value: "custom",
+		Text:  &slack.TextBlockObject{Type: "plain_text", Text: "✏️ Outro..."},
+	}}}
+	custom.View.State.Values["kudo_description"] = map[string]slack.BlockAction{"kudo_description": {Value: "Rockstar do Deploy"}}
+	custom.View.State.Values["kudo_emoji"] = map[string]slack.BlockAction{"kudo_emoji": {SelectedOption: slack.OptionBlockObject{Value: ":guitar:"}}}
+	HandleInteractivity(httptest.NewRecorder(), signedCallback(t, custom))
+
+	if len(server.Calls("chat.postMessage")) != 1 {
+		t.Fatalf("recorded %d chat.postMessage calls, want 1", len(server.Calls("chat.postMessage")))
+	}
+
+	// A teammate opening the type select sees the saved type
+	req := signedCallback(t, slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U333333"},
+		Team: slack.Team{ID: "T123456"},
+		View: slack.View{ID: "V654321", Hash: "hash-1"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: "kudo_type", SelectedOption: slack.OptionBlockObject{Value: "espirito-de-equipe"}},
+		}},
+	})
+	HandleInteractivity(httptest.NewRecorder(), req)
+
+	calls := server.Calls("views.update")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d views.update calls, want 1", len(calls))
+	}
+	body, _ := json.Marshal(calls[0].JSON["view"])
+	if !strings.Contains(string(body), ":guitar: Rockstar do Deploy") {
+		t.Errorf("updated view = %s, want the saved custom type", body)
+	}
+}
+
+func TestE2E_InteractivityKudoTypeSlackError(t *testing.T) {
+	server := useFakeSlack(t)
+	server.FailNext("views.update", "hash_conflict")
+
+	req := signedCallback(t, slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		User: slack.User{ID: "U111111"},
+		View: slack.View{ID: "V123456", Hash: "stale"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: "kudo_type", SelectedOption: slack.OptionBlockObject{Value: "espirito-de-equipe"}},
+		}},
+	})
+	w := httptest.NewRecorder()
+
+	HandleInteractivity(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestE2E_InteractivitySubmissionPostsKudos(t *testing.T) {
+	server := useFakeSlack(t)
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+
+	w := httptest.NewRecorder()
+	HandleInteractivity(w, signedCallback(t, submission("U222222", "U333333")))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Recipients outside the channel are invited, members get already_in_channel
+	if got := len(server.Calls("conversations.invite")); got != 2 {
+		t.Errorf("recorded %d conversations.invite calls, want 2", got)
+	}
+	if !slices.Contains(server.Members(slacktest.ChannelID), "U333333") {
+		t.Errorf("members = %v, want U333333 invited", server.Members(slacktest.ChannelID))
+	}
+
+	posts := server.Calls("chat.postMessage")
+	if len(posts) != 1 {
+		t.Fatalf("recorded %d chat.postMessage calls, want 1", len(posts))
+	}
+	if posts[0].Form.Get("channel") != slacktest.ChannelID {
+		t.Errorf("channel = %s, want %s", posts[0].Form.Get("channel"), slacktest.ChannelID)
+	}
+	blocks, err := posts[0].Blocks()
+	if err != nil || len(blocks) == 0 {
+		t.Fatalf("Blocks() = %v, %v, want the kudos blocks", blocks, err)
+	}
+	if text := posts[0].Form.Get("text"); !strings.Contains(text, "<@U111111>") || !strings.Contains(text, "Espírito de Equipe") {
+		t.Errorf("text = %q, want the sender and the kudo type", text)
+	}
+
+	kudos, err := globalConfig.Store.ListKudos(context.Background(), storage.KudoQuery{})
+	if err != nil || len(kudos) != 1 {
+		t.Fatalf("ListKudos() = %v, %v, want the posted kudo", kudos, err)
+	}
+	if kudos[0].MessageTS == "" || kudos[0].ChannelID != slacktest.ChannelID {
+		t.Errorf("stored kudo = %+v, want the message reference", kudos[0])
+	}
+}
+
+func TestE2E_InteractivitySubmissionRichText(t *testing.T) {
+	server := useFakeSlack(t)
+
+	var message slack.RichTextBlock
+	if err := json.Unmarshal([]byte(`{"type": "rich_text", "elements": [
+		{"type": "rich_text_section", "elements": [{"type": "text", "text": "Valeu, "}, {"type": "text", "text": "time", "style": {"bold": true}}, {"type": "text", "text": "!\n"}]},
+		{"type": "rich_text_preformatted", "elements": [{"type": "text", "text": "make deploy"}]}
+	]}`), &message); err != nil {
+		t.Fatalf("error decoding rich text: %v", err)
+	}
+	callback := submission("U222222")
+	callback.View.State.Values["kudo_message"] = map[string]slack.BlockAction{"kudo_message": {RichTextValue: message}}
+
+	w := httptest.NewRecorder()
+	HandleInteractivity(w, signedCallback(t, callback))
+
+	posts := server.Calls("chat.postMessage")
+	if len(posts) != 1 {
+		t.Fatalf("recorded %d chat.postMessage calls, want 1", len(posts))
+	}
+	blocks, err := posts[0].Blocks()
+	if err != nil {
+		t.Fatalf("Blocks() unexpected error = %v", err)
+	}
+	found := false
+	for _, block := range blocks {
+		if elements, ok := block["elements"].([]interface{}); ok && block["type"] == "rich_text" && len(elements) == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("blocks = %v, want the message as rich text with the code block", blocks)
+	}
+
+	// The stored message is mrkdwn, with the formatting kept
+	kudos, err := globalConfig.Store.ListKudos(context.Background(), storage.KudoQuery{})
+	if err != nil || len(kudos) != 1 || kudos[0].Message != "Valeu, *time*!\n```\nmake deploy\n```" {
+		t.Errorf("ListKudos() = %v, %v, want the message as mrkdwn", kudos, err)
+	}
+}
+
+func TestE2E_InteractivitySubmissionSlackError(t *testing.T) {
+	server := useFakeSlack(t)
+	server.FailNext("chat.postMessage", "channel_not_found")
+
+	w := httptest.NewRecorder()
+	HandleInteractivity(w, signedCallback(t, submission("U222222")))
+
+	// The modal closes anyway, nothing is recorded
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := len(server.Calls("chat.postMessage")); got != 1 {
+		t.Errorf("recorded %d chat.postMessage calls, want 1", got)
+	}
+
+	kudos, err := globalConfig.Store.ListKudos(context.Background(), storage.KudoQuery{})
+	if err != nil || len(kudos) != 0 {
+		t.Errorf("ListKudos() = %v, %v, want nothing recorded", kudos, err)
+	}
+}
+
+func TestE2E_InteractivitySubmissionRetriedOnce(t *testing.T) {
+	server := useFakeSlack(t)
+
+	// Slack retries and double clicks deliver the same view again
+	for range 2 {
+		w := httptest.NewRecorder()
+		HandleInteractivity(w, signedCallback(t, submission("U222222")))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+	}
+
+	if got := len(server.Calls("chat.postMessage")); got != 1 {
+		t.Errorf("recorded %d chat.postMessage calls, want 1", got)
+	}
+	kudos, err := globalConfig.Store.ListKudos(context.Background(), storage.KudoQuery{})
+	if err != nil || len(kudos) != 1 {
+		t.Errorf("ListKudos() = %v, %v, want a single kudo", kudos, err)
+	}
+}
+
+func TestE2E_InteractivityReminderOptOut(t *testing.T) {
+	server := useFakeSlack(t)
+
+	w := httptest.NewRecorder()
+	HandleInteractivity(w, signedCallback(t, slack.InteractionCallback{
+		Type:      slack.InteractionTypeBlockActions,
+		User:      slack.User{ID: "U111111"},
+		Container: slack.Container{Type: "message", ChannelID: "D123456", MessageTs: "1700000000.000001"},
+		ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+			{ActionID: "reminder_opt_out", BlockID: "reminder_actions", Value: "opt_out"},
+		}},
+	}))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// The reminder DM is replaced with the confirmation
+	calls := server.Calls("chat.update")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d chat.update calls, want 1", len(calls))
+	}
+	if calls[0].Form.Get("channel") != "D123456" || calls[0].Form.Get("ts") != "1700000000.000001" {
+		t.Errorf("updated %s/%s, want the reminder DM", calls[0].Form.Get("channel"), calls[0].Form.Get("ts"))
+	}
+
+	preferences, err := globalConfig.Store.ListReminderPreferences(context.Background())
+	if err != nil || len(preferences) != 1 || !preferences[0].OptedOut {
+		t.Errorf("ListReminderPreferences() = %v, %v, want U111111 opted out", preferences, err)
+	}
+}
+
+func TestE2E_InteractivityRejectsBadSignature(t *testing.T) {
+	server := useFakeSlack(t)
+
+	req := signedCallback(t, submission("U222222"))
+	req.Header.Set("X-Slack-Signature", slacktest.Sign("another-secret", req.Header.Get("X-Slack-Request-Timestamp"), "payload=%7B%7D"))
+	w := httptest.NewRecorder()
+
+	HandleInteractivity(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if calls := server.Calls(""); len(calls) != 0 {
+		t.Errorf("recorded %d Slack calls, want none", len(calls))
+	}
+}
@@ -1,8 +1,10 @@
 package interactivity
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 
@@ -10,7 +12,10 @@ import (
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
 	"github.com/vyper/my-matter/internal/handlers"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
 	"github.com/vyper/my-matter/internal/templates"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 var globalConfig *config.Config
@@ -22,21 +27,31 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
 	globalConfig = cfg
 }
 
 func handleInteractivity(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(tracing.Extract(r), "Interactivity")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+
 	// Verify Slack signing secret
-	_, err := slack.NewSecretsVerifier(r.Header, globalConfig.SigningSecret)
-	if err != nil {
-		log.Printf("Invalid Slack Signing Secret: %v", err)
+	if err := handlers.VerifySignature(r, globalConfig.SigningSecret); err != nil {
+		slog.WarnContext(ctx, "Invalid Slack Signing Secret", "error", err)
 		http.Error(w, "Invalid Slack Signing Secret", http.StatusUnauthorized)
 		return
 	}
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		log.Printf("Error parsing form: %v", err)
+		slog.WarnContext(ctx, "Error parsing form", "error", err)
 		http.Error(w, "Bad Request", http.StatusBadRequest)
 		return
 	}
@@ -44,7 +59,7 @@ func handleInteractivity(w http.ResponseWriter, r *http.Request) {
 	// Get payload
 	payloadStr := r.FormValue("payload")
 	if payloadStr == "" {
-		log.Printf("Missing payload in interactivity request")
+		slog.WarnContext(ctx, "Missing payload in interactivity request")
 		http.Error(w, "Bad Request", http.StatusBadRequest)
 		return
 	}
@@ -52,23 +67,43 @@ func handleInteractivity(w http.ResponseWriter, r *http.Request) {
 	// Parse interaction callback
 	var callback slack.InteractionCallback
 	if err := json.Unmarshal([]byte(payloadStr), &callback); err != nil {
-		log.Printf("Invalid Slack Interaction Callback: %v", err)
+		slog.WarnContext(ctx, "Invalid Slack Interaction Callback", "error", err)
 		http.Error(w, "Invalid Slack Interaction Callback", http.StatusBadRequest)
 		return
 	}
 
+	ctx = logging.WithAttrs(ctx, callbackAttrs(&callback)...)
+
 	// Route to appropriate handler based on interaction type
 	switch callback.Type {
 	case slack.InteractionTypeBlockActions:
-		handlers.HandleBlockActions(w, &callback, templates.GiveKudosViewTemplate, globalConfig)
+		handlers.HandleBlockActions(ctx, w, &callback, templates.GiveKudosViewTemplate, globalConfig)
 	case slack.InteractionTypeViewSubmission:
-		handlers.HandleViewSubmission(w, &callback, globalConfig)
+		handlers.HandleViewSubmission(ctx, w, &callback, globalConfig)
+	case slack.InteractionTypeBlockSuggestion:
+		handlers.HandleBlockSuggestion(ctx, w, &callback, globalConfig)
 	default:
-		log.Printf("Unknown interaction type: %s", callback.Type)
+		slog.WarnContext(ctx, "Unknown interaction type", "interaction_type", callback.Type)
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+// callbackAttrs returns the request-scoped log fields of an interaction
+func callbackAttrs(callback *slack.InteractionCallback) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("interaction_type", string(callback.Type)),
+		slog.String("team_id", callback.Team.ID),
+		slog.String("user_id", callback.User.ID),
+	}
+	if callback.View.ID != "" {
+		attrs = append(attrs, slog.String("view_id", callback.View.ID))
+	}
+	if len(callback.ActionCallback.BlockActions) > 0 {
+		attrs = append(attrs, slog.String("action_id", callback.ActionCallback.BlockActions[0].ActionID))
+	}
+	return attrs
+}
+
 // HandleInteractivity is the exported function for the Cloud Function entry point
 func HandleInteractivity(w http.ResponseWriter, r *http.Request) {
 	handleInteractivity(w, r)
@@ -1,6 +1,7 @@
 package interactivity
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
@@ -503,30 +504,36 @@ type MockSlackClient struct {
 	InviteUsersToConversationFunc func(channelID string, users ...string) (*slack.Channel, error)
 	GetUsersInConversationFunc    func(params *slack.GetUsersInConversationParameters) ([]string, string, error)
 	GetUserInfoFunc               func(user string) (*slack.User, error)
+	UploadFileV2Func              func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	OpenConversationFunc          func(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	GetUserProfileFunc            func(params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
+	UpdateMessageFunc             func(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetUserGroupMembersFunc       func(userGroup string) ([]string, error)
+	GetEmojiFunc                  func() (map[string]string, error)
 }
 
-func (m *MockSlackClient) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+func (m *MockSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
 	if m.PostMessageFunc != nil {
 		return m.PostMessageFunc(channelID, options...)
 	}
 	return "C123456", "1234567890.123456", nil
 }
 
-func (m *MockSlackClient) InviteUsersToConversation(channelID string, users ...string) (*slack.Channel, error) {
+func (m *MockSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
 	if m.InviteUsersToConversationFunc != nil {
 		return m.InviteUsersToConversationFunc(channelID, users...)
 	}
 	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}}}, nil
 }
 
-func (m *MockSlackClient) GetUsersInConversation(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+func (m *MockSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
 	if m.GetUsersInConversationFunc != nil {
 		return m.GetUsersInConversationFunc(params)
 	}
 	return []string{"U123456", "U789012"}, "", nil
 }
 
-func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
+func (m *MockSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	if m.GetUserInfoFunc != nil {
 		return m.GetUserInfoFunc(user)
 	}
@@ -536,3 +543,75 @@ func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
 		Deleted: false,
 	}, nil
 }
+
+func (m *MockSlackClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	if m.UploadFileV2Func != nil {
+		return m.UploadFileV2Func(params)
+	}
+	return &slack.FileSummary{ID: "F123456", Title: params.Title}, nil
+}
+
+func (m *MockSlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	if m.OpenConversationFunc != nil {
+		return m.OpenConversationFunc(params)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D123456"}}}, false, false, nil
+}
+
+func (m *MockSlackClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	if m.GetUserProfileFunc != nil {
+		return m.GetUserProfileFunc(params)
+	}
+	return &slack.UserProfile{}, nil
+}
+
+func (m *MockSlackClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	if m.UpdateMessageFunc != nil {
+		return m.UpdateMessageFunc(channelID, timestamp, options...)
+	}
+	return channelID, timestamp, "", nil
+}
+
+func (m *MockSlackClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	if m.GetUserGroupMembersFunc != nil {
+		return m.GetUserGroupMembersFunc(userGroup)
+	}
+	return []string{}, nil
+}
+
+func (m *MockSlackClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	if m.GetEmojiFunc != nil {
+		return m.GetEmojiFunc()
+	}
+	return map[string]string{}, nil
+}
+
+func TestCallbackAttrs(t *testing.T) {
+	callback := &slack.InteractionCallback{
+		Type: slack.InteractionTypeBlockActions,
+		Team: slack.Team{ID: "T123"},
+		User: slack.User{ID: "U123"},
+		View: slack.View{ID: "V123"},
+		ActionCallback: slack.ActionCallbacks{
+			BlockActions: []*slack.BlockAction{{ActionID: "kudo_type"}},
+		},
+	}
+
+	attrs := make(map[string]string)
+	for _, attr := range callbackAttrs(callback) {
+		attrs[attr.Key] = attr.Value.String()
+	}
+
+	expected := map[string]string{
+		"interaction_type": "block_actions",
+		"team_id":          "T123",
+		"user_id":          "U123",
+		"view_id":          "V123",
+		"action_id":        "kudo_type",
+	}
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Errorf("callbackAttrs()[%s] = %q, want %q", key, attrs[key], value)
+		}
+	}
+}
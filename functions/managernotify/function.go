@@ -0,0 +1,108 @@
+package managernotify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/pubsub"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+var globalConfig *config.Config
+
+func init() {
+	functions.CloudEvent("ManagerNotify", handleManagerNotify)
+
+	cfg, err := config.LoadConfig(os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
+	globalConfig = cfg
+}
+
+// ManagerNotifyRequest is the JSON payload accepted in the Pub/Sub message
+type ManagerNotifyRequest struct {
+	// Date is the day to summarize as YYYY-MM-DD, defaults to the previous day
+	Date string `json:"date"`
+}
+
+func handleManagerNotify(ctx context.Context, e event.Event) error {
+	defer tracing.Flush(ctx)
+	ctx, span := tracing.Start(ctx, "ManagerNotify")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+	ctx = logging.WithAttrs(ctx, slog.String("event_id", e.ID()))
+	defer metrics.Flush(ctx)
+	slog.InfoContext(ctx, "Manager notify function triggered", "event_time", e.Time())
+
+	// Invalid messages are dropped, Pub/Sub would redeliver them forever
+	var request ManagerNotifyRequest
+	if err := pubsub.DecodeMessage(e, &request); err != nil {
+		slog.ErrorContext(ctx, "Error decoding manager notify request", "error", err)
+		return nil
+	}
+
+	from, to, err := services.DayPeriod(request.Date, time.Now(), globalConfig.Location)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error parsing manager notify date", "date", request.Date, "error", err)
+		return nil
+	}
+
+	if globalConfig.ManagerFieldID == "" && len(globalConfig.Teams) == 0 {
+		slog.InfoContext(ctx, "No manager field or teams configured, skipping manager notifications")
+		return nil
+	}
+
+	notifications, err := services.BuildManagerNotifications(ctx, from, to, globalConfig)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error building manager notifications", "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "Found managers to notify", "managers", len(notifications), "date", from.Format("2006-01-02"))
+
+	successCount := 0
+	errorCount := 0
+	skippedCount := 0
+
+	for _, notification := range notifications {
+		err := services.SendManagerNotification(ctx, notification, from, globalConfig)
+		if errors.Is(err, services.ErrManagerNotified) {
+			slog.InfoContext(ctx, "Manager already notified, skipping", "manager_id", notification.ManagerID)
+			skippedCount++
+		} else if err != nil {
+			slog.ErrorContext(ctx, "Failed to notify manager", "manager_id", notification.ManagerID, "error", err)
+			errorCount++
+		} else {
+			slog.DebugContext(ctx, "Notified manager", "manager_id", notification.ManagerID, "kudos", len(notification.Kudos))
+			successCount++
+		}
+	}
+
+	slog.InfoContext(ctx, "Manager notifications complete", "success", successCount, "errors", errorCount, "skipped", skippedCount)
+
+	// Return nil even if some DMs failed - we don't want to retry for partial failures
+	return nil
+}
+
+// HandleManagerNotify is the exported function for the Cloud Function entry point
+func HandleManagerNotify(ctx context.Context, e event.Event) error {
+	return handleManagerNotify(ctx, e)
+}
@@ -0,0 +1,401 @@
+package reminder
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/slacktest"
+)
+
+// useFakeSlack points the function at a fake Slack API for the duration of the test
+func useFakeSlack(t *testing.T) *slacktest.Server {
+	t.Helper()
+
+	server := slacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	cfg, err := config.LoadConfig(server.Getenv)
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+
+	previous := globalConfig
+	globalConfig = cfg
+	t.Cleanup(func() { globalConfig = previous })
+	return server
+}
+
+// publishedEvent builds the CloudEvent Pub/Sub delivers for the scheduler message
+func publishedEvent(t *testing.T) event.Event {
+	return publishedPayload(t, "")
+}
+
+// publishedPayload builds the CloudEvent for a scheduler message carrying payload
+func publishedPayload(t *testing.T, payload string) event.Event {
+	t.Helper()
+
+	e := event.New()
+	e.SetID("e2e-event-id")
+	e.SetSource("//pubsub.googleapis.com/projects/test/topics/reminder")
+	e.SetType("google.cloud.pubsub.topic.v1.messagePublished")
+	e.SetTime(time.Now())
+	if err := e.SetData("application/json", map[string]interface{}{
+		"message": map[string]interface{}{"data": []byte(payload)},
+	}); err != nil {
+		t.Fatalf("error setting event data: %v", err)
+	}
+	return e
+}
+
+func TestE2E_ReminderSendsDMs(t *testing.T) {
+	server := useFakeSlack(t)
+
+	server.SetMembers(slacktest.ChannelID, "U111111", "UBOT", "UGONE", "U222222", "UNKNOWN")
+	server.AddUser(slack.User{ID: "U111111"})
+	server.AddUser(slack.User{ID: "U222222"})
+	server.AddUser(slack.User{ID: "UBOT", IsBot: true})
+	server.AddUser(slack.User{ID: "UGONE", Deleted: true})
+
+	if err := HandleReminder(context.Background(), publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+
+	if got := len(server.Calls("conversations.members")); got != 1 {
+		t.Errorf("recorded %d conversations.members calls, want 1", got)
+	}
+
+	var recipients []string
+	for _, call := range server.Calls("chat.postMessage") {
+		recipients = append(recipients, call.Form.Get("channel"))
+
+		blocks, err := call.Blocks()
+		if err != nil || len(blocks) == 0 {
+			t.Errorf("Blocks() = %v, %v, want the reminder blocks", blocks, err)
+		}
+	}
+
+	// Bots, deleted users and users Slack can't find are skipped
+	slices.Sort(recipients)
+	if !slices.Equal(recipients, []string{"U111111", "U222222"}) {
+		t.Errorf("DMs sent to %v, want U111111 and U222222", recipients)
+	}
+}
+
+func TestE2E_ReminderPersonalized(t *testing.T) {
+	server := useFakeSlack(t)
+
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+	server.AddUser(slack.User{ID: "U111111"})
+	server.AddUser(slack.User{ID: "U222222"})
+
+	ctx := context.Background()
+	if err := globalConfig.Store.SaveKudo(ctx, &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U333333"},
+		CreatedAt:    time.Now().Add(-time.Hour),
+	}); err != nil {
+		t.Fatalf("SaveKudo() unexpected error = %v", err)
+	}
+
+	if err := HandleReminder(ctx, publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+
+	for _, call := range server.Calls("chat.postMessage") {
+		body := call.Form.Get("blocks")
+		switch call.Form.Get("channel") {
+		case "U111111":
+			if !strings.Contains(body, "enviou *1 elogio(s)* e recebeu *0*") || !strings.Contains(body, "open_kudos_modal_for_user") {
+				t.Errorf("blocks = %s, want the sender's stats and a suggestion", body)
+			}
+		case "U222222":
+			if !strings.Contains(body, "enviou *0 elogio(s)* e recebeu *0*") || strings.Contains(body, "open_kudos_modal_for_user") {
+				t.Errorf("blocks = %s, want empty stats and no suggestions", body)
+			}
+		}
+	}
+}
+
+func TestE2E_ReminderHonorsPreferences(t *testing.T) {
+	server := useFakeSlack(t)
+
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222", "U333333")
+	server.AddUser(slack.User{ID: "U111111"})
+	server.AddUser(slack.User{ID: "U222222"})
+	server.AddUser(slack.User{ID: "U333333"})
+
+	ctx := context.Background()
+	for _, preference := range []*models.ReminderPreference{
+		{UserID: "U222222", OptedOut: true},
+		{UserID: "U333333", SnoozedUntil: time.Now().AddDate(0, 1, 0)},
+	} {
+		if err := globalConfig.Store.SaveReminderPreference(ctx, preference); err != nil {
+			t.Fatalf("SaveReminderPreference() unexpected error = %v", err)
+		}
+	}
+
+	if err := HandleReminder(ctx, publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+
+	var recipients []string
+	for _, call := range server.Calls("chat.postMessage") {
+		recipients = append(recipients, call.Form.Get("channel"))
+	}
+	if !slices.Equal(recipients, []string{"U111111"}) {
+		t.Errorf("DMs sent to %v, want only U111111", recipients)
+	}
+	// Skipped users aren't looked up either
+	if got := len(server.Calls("users.info")); got != 1 {
+		t.Errorf("recorded %d users.info calls, want 1", got)
+	}
+}
+
+func TestE2E_ReminderRedelivery(t *testing.T) {
+	server := useFakeSlack(t)
+
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+	server.AddUser(slack.User{ID: "U111111"})
+	server.AddUser(slack.User{ID: "U222222"})
+
+	recipients := func() []string {
+		var channels []string
+		for _, call := range server.Calls("chat.postMessage") {
+			channels = append(channels, call.Form.Get("channel"))
+		}
+		return channels
+	}
+
+	// The first DM fails, the first run reminds U222222 only
+	server.FailNext("chat.postMessage", "internal_error")
+	if err := HandleReminder(context.Background(), publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+
+	// The redelivered event only retries U111111
+	if err := HandleReminder(context.Background(), publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+	if got := recipients(); !slices.Equal(got, []string{"U111111", "U222222", "U111111"}) {
+		t.Errorf("DMs sent to %v, want U111111 retried once", got)
+	}
+
+	// Everyone was reminded this week, a manual re-run sends nothing
+	if err := HandleReminder(context.Background(), publishedEvent(t)); err != nil {
+		t.Fatalf("HandleReminder() unexpected error = %v", err)
+	}
+	if got := len(recipients()); got != 3 {
+		t.Errorf("attempted %d DMs, want no new ones", got)
+	}
+
+	deliveries, err := globalConfig.Store.ListReminderDeliveries(context.Background(), services.ReminderPeriod(time.Now(), globalConfig.Location))
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("ListReminderDeliveries() = %v, %v, want both users", deliveries, err)
+	}
+	for _, delivery := range deliveries {
+		if delivery.EventID != "e2e-event-id" {
+			t.Errorf("EventID = %s, want e2e-event-id", delivery.EventID)
+		}
+	}
+}
+
+func TestE2E_ReminderRequestParameters(t *testing.T) {
+	server := useFakeSlack(t)
+
+	server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+	server.SetMembers("C0OTHER", "U333333")
+	server.SetMembers("S0DEVS", "U111111", "U222222", "U333333", "U444444")
+	for _, userID := range []string{"U111111", "U222222", "U333333", "U444444"} {
+		server.AddUser(slack.User{ID: userID})
+	}
+
+	// U111111 sent kudos recently
+	ctx := context.Background()
+	if err := globalConfig.Store.SaveKudo(ctx, &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222"},
+		CreatedAt:    time.Now().AddDate(0, 0, -3),
+	}); err != nil {
+		t.Fatalf("SaveKudo() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		payload        string
+		wantRecipients []string
+		wantHeader     string
+	}{
+		{
+			name:           "other channel",
+			payload:        `{"job": "other-channel", "channel_id": "C0OTHER"}`,
+			wantRecipients: []string{"U333333"},
+			wantHeader:     "Lembrete Semanal",
+		},
+		{
+			name:           "user group with audience filters",
+			payload:        `{"job": "devs", "usergroup_id": "S0DEVS", "audience": {"exclude_user_ids": ["U444444"], "inactive_days": 7}}`,
+			wantRecipients: []string{"U222222", "U333333"},
+			wantHeader:     "Lembrete Semanal",
+		},
+		{
+			// Another job the same week reaches the channel again
+			name:           "month end in English",
+			payload:        `{"job": "month-end", "variant": "month_end", "locale": "en"}`,
+			wantRecipients: []string{"U111111", "U222222"},
+			wantHeader:     "The month is almost over",
+		},
+		{
+			name:           "same job again",
+			payload:        `{"job": "month-end", "variant": "month_end", "locale": "en"}`,
+			wantRecipients: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(server.Calls("chat.postMessage"))
+
+			if err := HandleReminder(ctx, publishedPayload(t, tt.payload)); err != nil {
+				t.Fatalf("HandleReminder() unexpected error = %v", err)
+			}
+
+			var recipients []string
+			for _, call := range server.Calls("chat.postMessage")[before:] {
+				recipients = append(recipients, call.Form.Get("channel"))
+				if !strings.Contains(call.Form.Get("blocks"), tt.wantHeader) {
+					t.Errorf("blocks = %s, want header %q", call.Form.Get("blocks"), tt.wantHeader)
+				}
+			}
+			slices.Sort(recipients)
+			if !slices.Equal(recipients, tt.wantRecipients) {
+				t.Errorf("DMs sent to %v, want %v", recipients, tt.wantRecipients)
+			}
+		})
+	}
+}
+
+func TestE2E_ReminderSlackErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		wantErr bool
+		wantDMs int
+	}{
+		{name: "members unavailable", method: "conversations.members", wantErr: true, wantDMs: 0},
+		// A failed DM doesn't stop the others or trigger a retry
+		{name: "one DM fails", method: "chat.postMessage", wantDMs: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := useFakeSlack(t)
+			server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+			server.AddUser(slack.User{ID: "U111111"})
+			server.AddUser(slack.User{ID: "U222222"})
+			server.FailNext(tt.method, "internal_error")
+
+			err := HandleReminder(context.Background(), publishedEvent(t))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HandleReminder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := len(server.Calls("chat.postMessage")); got != tt.wantDMs {
+				t.Errorf("attempted %d DMs, want %d", got, tt.wantDMs)
+			}
+		})
+	}
+}
+
+func TestE2E_ReminderDryRunAndRedirect(t *testing.T) {
+	tests := []struct {
+		name           string
+		env            map[string]string
+		payload        string
+		wantRecipients []string
+	}{
+		{
+			name:           "dry run from config",
+			env:            map[string]string{"REMINDER_DRY_RUN": "true"},
+			wantRecipients: nil,
+		},
+		{
+			name:           "dry run from payload",
+			payload:        `{"dry_run": true}`,
+			wantRecipients: nil,
+		},
+		{
+			name:           "payload disables configured dry run",
+			env:            map[string]string{"REMINDER_DRY_RUN": "true"},
+			payload:        `{"dry_run": false}`,
+			wantRecipients: []string{"U111111", "U222222"},
+		},
+		{
+			name:           "redirect from config",
+			env:            map[string]string{"REMINDER_REDIRECT_TO": "U0TESTER"},
+			wantRecipients: []string{"U0TESTER", "U0TESTER"},
+		},
+		{
+			name:           "redirect from payload overrides config",
+			env:            map[string]string{"REMINDER_REDIRECT_TO": "U0TESTER"},
+			payload:        `{"redirect_to": "C0TESTS"}`,
+			wantRecipients: []string{"C0TESTS", "C0TESTS"},
+		},
+		{
+			name:           "dry run wins over redirect",
+			payload:        `{"dry_run": true, "redirect_to": "C0TESTS"}`,
+			wantRecipients: nil,
+		},
+		{
+			name:    "invalid redirect target is dropped",
+			payload: `{"redirect_to": "#testes"}`,
+		},
+		{
+			name:    "invalid payload is dropped",
+			payload: `{"dry_run": "sim"}`,
+		},
+		{
+			name:    "unknown variant is dropped",
+			payload: `{"variant": "daily"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := useFakeSlack(t)
+			server.SetMembers(slacktest.ChannelID, "U111111", "U222222")
+			server.AddUser(slack.User{ID: "U111111"})
+			server.AddUser(slack.User{ID: "U222222"})
+
+			cfg, err := config.LoadConfig(func(key string) string {
+				if value, ok := tt.env[key]; ok {
+					return value
+				}
+				return server.Getenv(key)
+			})
+			if err != nil {
+				t.Fatalf("LoadConfig() unexpected error = %v", err)
+			}
+			globalConfig = cfg
+
+			if err := HandleReminder(context.Background(), publishedPayload(t, tt.payload)); err != nil {
+				t.Fatalf("HandleReminder() unexpected error = %v", err)
+			}
+
+			var recipients []string
+			for _, call := range server.Calls("chat.postMessage") {
+				recipients = append(recipients, call.Form.Get("channel"))
+			}
+			slices.Sort(recipients)
+			if !slices.Equal(recipients, tt.wantRecipients) {
+				t.Errorf("DMs sent to %v, want %v", recipients, tt.wantRecipients)
+			}
+		})
+	}
+}
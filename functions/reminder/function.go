@@ -2,13 +2,23 @@ package reminder
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"regexp"
+	"slices"
+	"time"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/pubsub"
 	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 var globalConfig *config.Config
@@ -20,42 +30,257 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
 	globalConfig = cfg
 }
 
-// PubSubMessage represents a Pub/Sub message
-type PubSubMessage struct {
-	Data []byte `json:"data"`
+var jobPattern = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+
+// ReminderRequest is the optional JSON payload of the scheduler message
+// Omitted fields fall back to the settings saved with /elogie admin, then to the function configuration,
+// so one deployment can serve several jobs:
+//
+//	{
+//	  "job": "engineering-month-end",
+//	  "usergroup_id": "S0123ABCD",
+//	  "variant": "month_end",
+//	  "locale": "en",
+//	  "audience": {"exclude_user_ids": ["U0123ABCD"], "inactive_days": 14},
+//	  "dry_run": true
+//	}
+type ReminderRequest struct {
+	// Job names the scheduler job, each job tracks its deliveries separately
+	Job string `json:"job"`
+	// ChannelID reminds the members of this channel instead of SLACK_CHANNEL_ID
+	ChannelID string `json:"channel_id"`
+	// UserGroupID reminds the members of this user group instead of a channel
+	UserGroupID string `json:"usergroup_id"`
+	// Variant is the wording of the message: weekly (default) or month_end
+	Variant string `json:"variant"`
+	// Locale of the message: pt-BR (default) or en
+	Locale string `json:"locale"`
+	// Audience narrows down who is reminded
+	Audience ReminderAudience `json:"audience"`
+	// DryRun resolves the audience without sending, overrides REMINDER_DRY_RUN
+	DryRun *bool `json:"dry_run"`
+	// RedirectTo receives every DM instead of the members, overrides REMINDER_REDIRECT_TO
+	RedirectTo string `json:"redirect_to"`
+	// Scheduled runs only send once the weekday and hour saved with /elogie admin are reached
+	Scheduled bool `json:"scheduled"`
+}
+
+// ReminderAudience filters the members of the target
+type ReminderAudience struct {
+	// ExcludeUserIDs are never reminded by the job
+	ExcludeUserIDs []string `json:"exclude_user_ids"`
+	// InactiveDays only reminds who hasn't sent kudos in that many days, 0 reminds everyone
+	InactiveDays int `json:"inactive_days"`
+}
+
+// Validate checks the request before anything is sent
+func (r ReminderRequest) Validate() error {
+	if r.Job != "" && !jobPattern.MatchString(r.Job) {
+		return fmt.Errorf("job must have up to 64 lowercase letters, digits, - or _, got %q", r.Job)
+	}
+	if r.ChannelID != "" && r.UserGroupID != "" {
+		return fmt.Errorf("channel_id and usergroup_id can't be combined")
+	}
+	if r.ChannelID != "" && !config.IsChannelID(r.ChannelID) {
+		return fmt.Errorf("channel_id must be a channel ID, got %q", r.ChannelID)
+	}
+	if r.UserGroupID != "" && !config.IsUserGroupID(r.UserGroupID) {
+		return fmt.Errorf("usergroup_id must be a user group ID starting with S, got %q", r.UserGroupID)
+	}
+	if err := r.Style().Validate(); err != nil {
+		return err
+	}
+	for _, userID := range r.Audience.ExcludeUserIDs {
+		if !config.IsConversationID(userID) || config.IsChannelID(userID) {
+			return fmt.Errorf("audience.exclude_user_ids must hold user IDs, got %q", userID)
+		}
+	}
+	if r.Audience.InactiveDays < 0 || r.Audience.InactiveDays > services.MaxInactiveDays {
+		return fmt.Errorf("audience.inactive_days must be between 0 and %d, got %d", services.MaxInactiveDays, r.Audience.InactiveDays)
+	}
+	if r.RedirectTo != "" && !config.IsConversationID(r.RedirectTo) {
+		return fmt.Errorf("redirect_to must be a user or channel ID, got %q", r.RedirectTo)
+	}
+	return nil
+}
+
+// WithSettings fills the target and audience the request omits with the admins' settings
+// Excluded users add up
+func (r ReminderRequest) WithSettings(settings models.ReminderSettings) ReminderRequest {
+	if r.ChannelID == "" && r.UserGroupID == "" {
+		r.ChannelID = settings.ChannelID
+		r.UserGroupID = settings.UserGroupID
+	}
+	r.Audience.ExcludeUserIDs = append(slices.Clone(r.Audience.ExcludeUserIDs), settings.ExcludeUserIDs...)
+	if r.Audience.InactiveDays == 0 {
+		r.Audience.InactiveDays = settings.InactiveDays
+	}
+	return r
+}
+
+// Style returns the wording requested for the message
+func (r ReminderRequest) Style() services.ReminderStyle {
+	return services.ReminderStyle{Variant: r.Variant, Locale: r.Locale}
+}
+
+// DeliveryPeriod keys the deliveries of the job in the period, e.g. 2026-W11:month-end
+// It's part of Firestore document IDs, so it can't contain /
+func (r ReminderRequest) DeliveryPeriod(period string) string {
+	if r.Job == "" {
+		return period
+	}
+	return period + ":" + r.Job
 }
 
 func handleReminder(ctx context.Context, e event.Event) error {
-	log.Printf("Reminder function triggered at %s", e.Time())
+	defer tracing.Flush(ctx)
+	ctx, span := tracing.Start(ctx, "Reminder")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+	ctx = logging.WithAttrs(ctx, slog.String("event_id", e.ID()))
+	defer metrics.Flush(ctx)
+	slog.InfoContext(ctx, "Reminder function triggered", "event_time", e.Time())
+
+	// Invalid messages are dropped, Pub/Sub would redeliver them forever
+	var request ReminderRequest
+	if err := pubsub.DecodeMessage(e, &request); err != nil {
+		slog.ErrorContext(ctx, "Error decoding reminder request", "error", err)
+		return nil
+	}
+	if err := request.Validate(); err != nil {
+		slog.ErrorContext(ctx, "Invalid reminder request", "error", err)
+		return nil
+	}
 
-	// Get channel members
-	members, err := services.GetChannelMembers(globalConfig.SlackAPI, globalConfig.SlackChannelID)
+	dryRun := globalConfig.ReminderDryRun
+	if request.DryRun != nil {
+		dryRun = *request.DryRun
+	}
+	redirectTo := globalConfig.RedirectTo
+	if request.RedirectTo != "" {
+		redirectTo = request.RedirectTo
+	}
+
+	now := time.Now()
+	cfg, settings := services.ApplySettings(ctx, globalConfig)
+	location := cfg.Location
+	if location == nil {
+		location = time.UTC
+	}
+	if request.Scheduled && !settings.Reminder.Due(now.In(location)) {
+		slog.InfoContext(ctx, "Reminder not due yet", "weekday", settings.Reminder.Weekday, "hour", settings.Reminder.Hour)
+		return nil
+	}
+	request = request.WithSettings(settings.Reminder)
+
+	period := request.DeliveryPeriod(services.ReminderPeriod(now, cfg.Location))
+	ctx = logging.WithAttrs(ctx, slog.String("period", period))
+
+	// Leave out who snoozed or opted out
+	skipped, err := services.SkippedReminderUsers(ctx, now, cfg)
 	if err != nil {
-		log.Printf("Error getting channel members: %v", err)
+		slog.ErrorContext(ctx, "Error loading reminder preferences", "error", err)
 		return err
 	}
 
-	log.Printf("Found %d active members to send reminders to", len(members))
+	// Redeliveries and re-runs only reach who wasn't reminded this period yet
+	delivered, err := services.DeliveredReminders(ctx, period, cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading reminder deliveries", "error", err)
+		return err
+	}
+	for userID := range delivered {
+		skipped[userID] = true
+	}
+
+	for _, userID := range request.Audience.ExcludeUserIDs {
+		skipped[userID] = true
+	}
+	if request.Audience.InactiveDays > 0 {
+		senders, err := services.RecentKudoSenders(ctx, now.AddDate(0, 0, -request.Audience.InactiveDays), cfg)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error loading recent kudos", "error", err)
+			return err
+		}
+		for userID := range senders {
+			skipped[userID] = true
+		}
+	}
+
+	// Get the members of the target
+	var members []string
+	if request.UserGroupID != "" {
+		members, err = services.GetUserGroupMembers(ctx, cfg.SlackAPI, request.UserGroupID, skipped)
+	} else {
+		channelID := cfg.SlackChannelID
+		if request.ChannelID != "" {
+			channelID = request.ChannelID
+		}
+		members, err = services.GetChannelMembers(ctx, cfg.SlackAPI, channelID, skipped)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error getting channel members", "error", err)
+		return err
+	}
+
+	slog.InfoContext(ctx, "Found active members to send reminders to", "count", len(members), "skipped", len(skipped), "already_delivered", len(delivered))
+
+	if dryRun {
+		slog.InfoContext(ctx, "Dry run, no reminders sent", "count", len(members), "user_ids", members)
+		return nil
+	}
+
+	// Stats and suggestions are optional, the generic reminder is sent without them
+	planner, err := services.NewReminderPlanner(ctx, now, cfg)
+	if err != nil {
+		slog.WarnContext(ctx, "Sending generic reminders", "error", err)
+	}
 
 	// Send DM to each member
 	successCount := 0
 	errorCount := 0
 
 	for _, userID := range members {
-		err := services.SendReminderDM(globalConfig.SlackAPI, userID)
+		var content *services.ReminderContent
+		if planner != nil {
+			content = planner.ContentFor(userID)
+		}
+
+		var err error
+		if redirectTo != "" {
+			err = services.RedirectReminderDM(ctx, cfg.SlackAPI, userID, redirectTo, request.Style(), content)
+		} else {
+			err = services.SendReminderDM(ctx, cfg.SlackAPI, userID, request.Style(), content)
+		}
+		metrics.ReminderSent(ctx, err)
 		if err != nil {
-			log.Printf("Failed to send reminder to user %s: %v", userID, err)
+			slog.ErrorContext(ctx, "Failed to send reminder", "user_id", userID, "error", err)
 			errorCount++
-		} else {
-			log.Printf("Successfully sent reminder to user %s", userID)
-			successCount++
+			continue
+		}
+		slog.DebugContext(ctx, "Sent reminder", "user_id", userID)
+		successCount++
+
+		// Test runs don't count as the user's reminder
+		if redirectTo != "" {
+			continue
+		}
+		if err := services.RecordReminderDelivery(ctx, period, userID, e.ID(), time.Now(), cfg); err != nil {
+			slog.ErrorContext(ctx, "Failed to record reminder delivery", "user_id", userID, "error", err)
 		}
 	}
 
-	log.Printf("Reminder sending complete. Success: %d, Errors: %d", successCount, errorCount)
+	slog.InfoContext(ctx, "Reminder sending complete", "success", successCount, "errors", errorCount)
 
 	// Return nil even if some DMs failed - we don't want to retry for partial failures
 	return nil
@@ -0,0 +1,173 @@
+package slashcommand
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/slacktest"
+)
+
+// useFakeSlack points the function at a fake Slack API for the duration of the test
+func useFakeSlack(t *testing.T) *slacktest.Server {
+	t.Helper()
+
+	server := slacktest.NewServer()
+	t.Cleanup(server.Close)
+
+	cfg, err := config.LoadConfig(server.Getenv)
+	if err != nil {
+		t.Fatalf("LoadConfig() unexpected error = %v", err)
+	}
+
+	previous := globalConfig
+	globalConfig = cfg
+	t.Cleanup(func() { globalConfig = previous })
+	return server
+}
+
+func TestE2E_SlashCommandOpensModal(t *testing.T) {
+	server := useFakeSlack(t)
+
+	req := slacktest.NewSignedRequest(slacktest.SigningSecret, url.Values{
+		"command":    {"/elogie"},
+		"trigger_id": {"12345.67890.abcdef"},
+		"user_id":    {"U123456"},
+		"team_id":    {"T123456"},
+	})
+	w := httptest.NewRecorder()
+
+	HandleSlashCommand(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	calls := server.Calls("views.open")
+	if len(calls) != 1 {
+		t.Fatalf("recorded %d views.open calls, want 1", len(calls))
+	}
+	if calls[0].Token != slacktest.BotToken {
+		t.Errorf("Token = %q, want the bot token", calls[0].Token)
+	}
+	if calls[0].JSON["trigger_id"] != "12345.67890.abcdef" {
+		t.Errorf("trigger_id = %v, want 12345.67890.abcdef", calls[0].JSON["trigger_id"])
+	}
+	view, _ := calls[0].JSON["view"].(map[string]interface{})
+	if view["type"] != "modal" {
+		t.Errorf("view type = %v, want modal", view["type"])
+	}
+}
+
+func TestE2E_SlashCommandSlackErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		slackError string
+	}{
+		// Slack rejections are logged, the command is still acknowledged
+		{name: "expired trigger", slackError: "expired_trigger_id"},
+		{name: "invalid view", slackError: "invalid_arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := useFakeSlack(t)
+			server.FailNext("views.open", tt.slackError)
+
+			req := slacktest.NewSignedRequest(slacktest.SigningSecret, url.Values{
+				"command":    {"/elogie"},
+				"trigger_id": {"12345.67890.abcdef"},
+				"user_id":    {"U123456"},
+			})
+			w := httptest.NewRecorder()
+
+			HandleSlashCommand(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if got := len(server.Calls("views.open")); got != 1 {
+				t.Errorf("recorded %d views.open calls, want 1", got)
+			}
+		})
+	}
+}
+
+func TestE2E_SlashCommandSubcommand(t *testing.T) {
+	server := useFakeSlack(t)
+
+	req := slacktest.NewSignedRequest(slacktest.SigningSecret, url.Values{
+		"command":    {"/elogie"},
+		"text":       {"saldo"},
+		"trigger_id": {"12345.67890.abcdef"},
+		"user_id":    {"U123456"},
+	})
+	w := httptest.NewRecorder()
+
+	HandleSlashCommand(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), "ephemeral") {
+		t.Errorf("body = %s, want an ephemeral reply", w.Body.String())
+	}
+	if calls := server.Calls(""); len(calls) != 0 {
+		t.Errorf("recorded %d Slack calls, want none", len(calls))
+	}
+}
+
+func TestE2E_SlashCommandRejectsBadSignatures(t *testing.T) {
+	form := url.Values{
+		"command":    {"/elogie"},
+		"trigger_id": {"12345.67890.abcdef"},
+		"user_id":    {"U123456"},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(req *http.Request)
+	}{
+		{
+			name:   "unsigned",
+			modify: func(req *http.Request) { req.Header.Del("X-Slack-Signature") },
+		},
+		{
+			name: "signed with another secret",
+			modify: func(req *http.Request) {
+				signed := slacktest.NewSignedRequest("another-secret", form)
+				req.Header.Set("X-Slack-Signature", signed.Header.Get("X-Slack-Signature"))
+			},
+		},
+		{
+			name: "tampered body",
+			modify: func(req *http.Request) {
+				tampered := url.Values{"command": {"/elogie"}, "trigger_id": {"12345.67890.abcdef"}, "user_id": {"U999999"}}
+				req.Body = io.NopCloser(strings.NewReader(tampered.Encode()))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := useFakeSlack(t)
+
+			req := slacktest.NewSignedRequest(slacktest.SigningSecret, form)
+			tt.modify(req)
+			w := httptest.NewRecorder()
+
+			HandleSlashCommand(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+			if calls := server.Calls(""); len(calls) != 0 {
+				t.Errorf("recorded %d Slack calls, want none", len(calls))
+			}
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package slashcommand
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 
 	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
-	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
 	"github.com/vyper/my-matter/internal/handlers"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/metrics"
 	"github.com/vyper/my-matter/internal/templates"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 var globalConfig *config.Config
@@ -21,25 +25,42 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	logging.Setup(os.Stderr, cfg.Debug)
+	if err := metrics.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Metrics disabled", "error", err)
+	}
+	if err := tracing.Setup(context.Background(), os.Getenv); err != nil {
+		slog.Error("Tracing disabled", "error", err)
+	}
 	globalConfig = cfg
 }
 
 func handleSlashCommand(w http.ResponseWriter, r *http.Request) {
+	ctx, span := tracing.Start(tracing.Extract(r), "SlashCommand")
+	defer span.End()
+	ctx = logging.WithSpan(ctx, globalConfig.ProjectID)
+
 	// Verify Slack signing secret
-	_, err := slack.NewSecretsVerifier(r.Header, globalConfig.SigningSecret)
-	if err != nil {
-		log.Printf("Invalid Slack Signing Secret: %v", err)
+	if err := handlers.VerifySignature(r, globalConfig.SigningSecret); err != nil {
+		slog.WarnContext(ctx, "Invalid Slack Signing Secret", "error", err)
 		http.Error(w, "Invalid Slack Signing Secret", http.StatusUnauthorized)
 		return
 	}
 
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
-		log.Printf("Error parsing form: %v", err)
+		slog.WarnContext(ctx, "Error parsing form", "error", err)
 		http.Error(w, "Bad Request", http.StatusBadRequest)
 		return
 	}
 
+	ctx = logging.WithAttrs(ctx,
+		slog.String("team_id", r.FormValue("team_id")),
+		slog.String("user_id", r.FormValue("user_id")),
+		slog.String("command", r.FormValue("command")),
+	)
+	r = r.WithContext(ctx)
+
 	// Handle slash command
 	handlers.HandleSlashCommand(w, r, templates.GiveKudosViewTemplate, globalConfig)
 }
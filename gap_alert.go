@@ -0,0 +1,13 @@
+package function
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/functions/gapalert"
+)
+
+// GapAlert is the Cloud Function entry point for the Pub/Sub triggered recognition gap alerts
+func GapAlert(ctx context.Context, e event.Event) error {
+	return gapalert.HandleGapAlert(ctx, e)
+}
@@ -1,24 +1,55 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
+	_ "time/tzdata"
+	"unicode"
 
 	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/logging"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/templates"
 )
 
+// DefaultTimezone is used to compute calendar periods when TIMEZONE is not set
+const DefaultTimezone = "America/Sao_Paulo"
+
+// DefaultGapWeeks is used when KUDOS_GAP_WEEKS is not set
+const DefaultGapWeeks = 4
+
+// DefaultPromoteAfter is used when KUDOS_PROMOTE_AFTER is not set
+const DefaultPromoteAfter = 5
+
 // HTTPClient interface for mocking HTTP calls
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
 // SlackClient interface for mocking Slack API calls
+// The context carries the request's span and deadline to the Slack API
 type SlackClient interface {
-	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
-	InviteUsersToConversation(channelID string, users ...string) (*slack.Channel, error)
-	GetUsersInConversation(params *slack.GetUsersInConversationParameters) ([]string, string, error)
-	GetUserInfo(user string) (*slack.User, error)
+	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error)
+	GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error)
+	GetUserInfoContext(ctx context.Context, user string) (*slack.User, error)
+	OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
+	GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error)
+	GetEmojiContext(ctx context.Context) (map[string]string, error)
 }
 
 // Config holds the configuration for the function
@@ -26,32 +57,315 @@ type Config struct {
 	SlackBotToken  string
 	SlackChannelID string
 	SigningSecret  string
+	MonthlyPoints  int                // 0 disables the points system
+	ExportUserIDs  []string           // users allowed to run /elogie exportar
+	AdminUserIDs   []string           // users who manage the kudo types and settings
+	AdminGroupID   string             // user group whose members are admins too
+	PromoteAfter   int                // kudos of a custom type before admins are asked to promote it, 0 disables it
+	GapWeeks       int                // weeks without kudos before alerting managers
+	ManagerFieldID string             // Slack profile custom field holding the user's manager
+	ReminderDryRun bool               // resolves the reminder audience without sending DMs
+	RedirectTo     string             // user or channel receiving every reminder DM instead of the members
+	DedupeWindow   time.Duration      // identical kudos from a sender within it are posted once
+	KudoTemplate   *template.Template // renders the kudos message as Block Kit JSON
+	KudoFooters    []string           // phrases rotated in the kudos message footer
+	Teams          []models.Team
+	Location       *time.Location
+	ProjectID      string        // Google Cloud project, used to correlate logs with traces
+	Debug          bool          // enables debug logs, including the Slack API traffic
+	HTTPTimeout    time.Duration // timeout of Slack API and direct HTTP calls
+	SlackAPIURL    string        // base URL of the Slack Web API, overridden in tests
 	SlackAPI       SlackClient
 	HTTPClient     HTTPClient
+	Store          storage.Store
+}
+
+// PointsEnabled reports whether the monthly points budget is active
+func (c *Config) PointsEnabled() bool {
+	return c.MonthlyPoints > 0
+}
+
+// IsAdmin reports whether the user is listed in KUDOS_ADMINS
+// Members of KUDOS_ADMIN_GROUP are checked with services.IsAdmin
+func (c *Config) IsAdmin(userID string) bool {
+	return slices.Contains(c.AdminUserIDs, userID)
+}
+
+// CanExport reports whether the user is allowed to export kudos
+func (c *Config) CanExport(userID string) bool {
+	return slices.Contains(c.ExportUserIDs, userID)
+}
+
+// SlackURL returns the URL of a Slack Web API method such as views.open
+func (c *Config) SlackURL(method string) string {
+	if c.SlackAPIURL == "" {
+		return slack.APIURL + method
+	}
+	return c.SlackAPIURL + method
+}
+
+// ManagerOf returns the manager of the user according to the org file
+func (c *Config) ManagerOf(userID string) string {
+	for _, team := range c.Teams {
+		if team.ManagerID != userID && slices.Contains(team.MemberIDs, userID) {
+			return team.ManagerID
+		}
+	}
+	return ""
+}
+
+// channelIDPattern matches Slack channel IDs such as C09CDF3RQJG
+var channelIDPattern = regexp.MustCompile(`^C[A-Z0-9]+$`)
+
+// conversationIDPattern matches the user and channel IDs messages can be posted to
+var conversationIDPattern = regexp.MustCompile(`^[CUW][A-Z0-9]+$`)
+
+// userGroupIDPattern matches Slack user group IDs such as S0123ABCD
+var userGroupIDPattern = regexp.MustCompile(`^S[A-Z0-9]+$`)
+
+// IsChannelID reports whether id is a Slack channel ID
+func IsChannelID(id string) bool {
+	return channelIDPattern.MatchString(id)
+}
+
+// IsUserGroupID reports whether id is a Slack user group ID
+func IsUserGroupID(id string) bool {
+	return userGroupIDPattern.MatchString(id)
+}
+
+// IsConversationID reports whether id is a Slack user or channel ID
+func IsConversationID(id string) bool {
+	return conversationIDPattern.MatchString(id)
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from the defaults, the optional KUDOS_CONFIG_FILE and environment variables
+// Environment variables override the file, and the Slack credentials come from SECRETS_PROVIDER
 func LoadConfig(getenv func(string) string) (*Config, error) {
-	slackBotToken := getenv("SLACK_BOT_TOKEN")
+	ctx := context.Background()
+
+	lookup, err := newLookup(getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := newSecretProvider(ctx, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	slackBotToken, err := secrets.Secret(ctx, "SLACK_BOT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
 	if slackBotToken == "" {
 		return nil, fmt.Errorf("SLACK_BOT_TOKEN environment variable is required")
 	}
+	if !strings.HasPrefix(slackBotToken, "xoxb-") {
+		return nil, fmt.Errorf("SLACK_BOT_TOKEN must be a bot token starting with xoxb-")
+	}
 
-	slackChannelID := getenv("SLACK_CHANNEL_ID")
+	slackChannelID := lookup("SLACK_CHANNEL_ID")
 	if slackChannelID == "" {
 		return nil, fmt.Errorf("SLACK_CHANNEL_ID environment variable is required")
 	}
+	if !channelIDPattern.MatchString(slackChannelID) {
+		return nil, fmt.Errorf("SLACK_CHANNEL_ID must be a channel ID starting with C, got %q", slackChannelID)
+	}
 
-	signingSecret := getenv("SLACK_SIGNING_SECRET")
+	slackAPIURL := lookup("SLACK_API_URL")
+	if !strings.HasSuffix(slackAPIURL, "/") {
+		slackAPIURL += "/"
+	}
+
+	signingSecret, err := secrets.Secret(ctx, "SLACK_SIGNING_SECRET")
+	if err != nil {
+		return nil, err
+	}
 	if signingSecret == "" {
 		return nil, fmt.Errorf("SLACK_SIGNING_SECRET environment variable is required")
 	}
 
+	monthlyPoints, err := strconv.Atoi(lookup("KUDOS_MONTHLY_POINTS"))
+	if err != nil || monthlyPoints < 0 {
+		return nil, fmt.Errorf("KUDOS_MONTHLY_POINTS must be a non-negative integer")
+	}
+
+	gapWeeks, err := strconv.Atoi(lookup("KUDOS_GAP_WEEKS"))
+	if err != nil || gapWeeks <= 0 {
+		return nil, fmt.Errorf("KUDOS_GAP_WEEKS must be a positive integer")
+	}
+
+	promoteAfter, err := strconv.Atoi(lookup("KUDOS_PROMOTE_AFTER"))
+	if err != nil || promoteAfter < 0 {
+		return nil, fmt.Errorf("KUDOS_PROMOTE_AFTER must be a non-negative integer")
+	}
+
+	adminGroupID := lookup("KUDOS_ADMIN_GROUP")
+	if adminGroupID != "" && !IsUserGroupID(adminGroupID) {
+		return nil, fmt.Errorf("KUDOS_ADMIN_GROUP must be a user group ID starting with S, got %q", adminGroupID)
+	}
+
+	reminderDryRun, err := strconv.ParseBool(lookup("REMINDER_DRY_RUN"))
+	if err != nil {
+		return nil, fmt.Errorf("REMINDER_DRY_RUN must be true or false")
+	}
+
+	redirectTo := lookup("REMINDER_REDIRECT_TO")
+	if redirectTo != "" && !IsConversationID(redirectTo) {
+		return nil, fmt.Errorf("REMINDER_REDIRECT_TO must be a user or channel ID, got %q", redirectTo)
+	}
+
+	var teams []models.Team
+	if path := lookup("KUDOS_TEAMS_FILE"); path != "" {
+		orgChart, err := loadOrgChart(path)
+		if err != nil {
+			return nil, err
+		}
+		teams = orgChart.Teams
+	}
+
+	timezone := lookup("TIMEZONE")
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TIMEZONE %q: %w", timezone, err)
+	}
+
+	httpTimeout, err := time.ParseDuration(lookup("HTTP_TIMEOUT"))
+	if err != nil || httpTimeout <= 0 {
+		return nil, fmt.Errorf("HTTP_TIMEOUT must be a positive duration such as 10s")
+	}
+
+	dedupeWindow, err := time.ParseDuration(lookup("KUDOS_DEDUPE_WINDOW"))
+	if err != nil || dedupeWindow < 0 {
+		return nil, fmt.Errorf("KUDOS_DEDUPE_WINDOW must be a duration such as 2m, or 0 to disable")
+	}
+
+	kudoTemplate, err := loadKudoTemplate(lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	debug, err := strconv.ParseBool(lookup("DEBUG"))
+	if err != nil {
+		return nil, fmt.Errorf("DEBUG must be true or false")
+	}
+
+	store, err := newStore(lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	slackAPI := slack.New(slackBotToken,
+		slack.OptionAPIURL(slackAPIURL),
+		slack.OptionHTTPClient(&http.Client{Timeout: httpTimeout}),
+		slack.OptionDebug(debug),
+		slack.OptionLog(logging.SlackLogger{}),
+	)
+
 	return &Config{
 		SlackBotToken:  slackBotToken,
 		SlackChannelID: slackChannelID,
 		SigningSecret:  signingSecret,
-		SlackAPI:       slack.New(slackBotToken, slack.OptionDebug(true)),
-		HTTPClient:     &http.Client{Timeout: time.Second * 10},
+		MonthlyPoints:  monthlyPoints,
+		ExportUserIDs:  parseList(lookup("KUDOS_EXPORT_USERS")),
+		AdminUserIDs:   parseList(lookup("KUDOS_ADMINS")),
+		AdminGroupID:   adminGroupID,
+		PromoteAfter:   promoteAfter,
+		GapWeeks:       gapWeeks,
+		ManagerFieldID: lookup("KUDOS_MANAGER_FIELD"),
+		ReminderDryRun: reminderDryRun,
+		RedirectTo:     redirectTo,
+		DedupeWindow:   dedupeWindow,
+		KudoTemplate:   kudoTemplate,
+		KudoFooters:    parseFooters(lookup("KUDOS_FOOTERS")),
+		Teams:          teams,
+		Location:       location,
+		ProjectID:      lookup("GOOGLE_CLOUD_PROJECT"),
+		Debug:          debug,
+		HTTPTimeout:    httpTimeout,
+		SlackAPIURL:    slackAPIURL,
+		SlackAPI:       InstrumentSlackClient(slackAPI),
+		HTTPClient:     InstrumentHTTPClient(&http.Client{Timeout: httpTimeout}),
+		Store:          store,
 	}, nil
 }
+
+// newStore creates the storage backend selected by STORAGE_BACKEND
+func newStore(getenv func(string) string) (storage.Store, error) {
+	switch backend := getenv("STORAGE_BACKEND"); backend {
+	case "", "memory":
+		return storage.NewMemoryStore(), nil
+	case "firestore":
+		projectID := getenv("FIRESTORE_PROJECT_ID")
+		if projectID == "" {
+			projectID = getenv("GOOGLE_CLOUD_PROJECT")
+		}
+		if projectID == "" {
+			return nil, fmt.Errorf("FIRESTORE_PROJECT_ID environment variable is required for the firestore backend")
+		}
+		return storage.NewFirestoreStore(context.Background(), projectID, getenv("FIRESTORE_DATABASE"))
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+// parseList splits a list separated by commas or spaces
+// Spaces allow lists to be passed through gcloud --set-env-vars, which reserves commas
+func parseList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// parseFooters splits the footer phrases separated by "|"
+func parseFooters(value string) []string {
+	var footers []string
+	for _, footer := range strings.Split(value, "|") {
+		if footer = strings.TrimSpace(footer); footer != "" {
+			footers = append(footers, footer)
+		}
+	}
+	return footers
+}
+
+// loadKudoTemplate parses KUDOS_MESSAGE_TEMPLATE_FILE, or the built-in template named by KUDOS_MESSAGE_TEMPLATE
+func loadKudoTemplate(getenv func(string) string) (*template.Template, error) {
+	path := getenv("KUDOS_MESSAGE_TEMPLATE_FILE")
+	if path == "" {
+		tmpl, err := templates.KudoTemplate(getenv("KUDOS_MESSAGE_TEMPLATE"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid KUDOS_MESSAGE_TEMPLATE: %w", err)
+		}
+		return tmpl, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading KUDOS_MESSAGE_TEMPLATE_FILE: %w", err)
+	}
+	tmpl, err := templates.ParseKudoTemplate(filepath.Base(path), string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing KUDOS_MESSAGE_TEMPLATE_FILE: %w", err)
+	}
+	return tmpl, nil
+}
+
+// loadOrgChart reads the team mapping from a JSON file
+func loadOrgChart(path string) (*models.OrgChart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading KUDOS_TEAMS_FILE: %w", err)
+	}
+
+	var orgChart models.OrgChart
+	if err := json.Unmarshal(data, &orgChart); err != nil {
+		return nil, fmt.Errorf("error parsing KUDOS_TEAMS_FILE: %w", err)
+	}
+
+	for i, team := range orgChart.Teams {
+		if team.ManagerID == "" {
+			return nil, fmt.Errorf("team %d (%q) in KUDOS_TEAMS_FILE has no manager_id", i, team.Name)
+		}
+	}
+	return &orgChart, nil
+}
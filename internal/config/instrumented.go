@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentSlackClient wraps the client to trace and record the latency of every Slack API call
+func InstrumentSlackClient(client SlackClient) SlackClient {
+	return &instrumentedSlackClient{client: client}
+}
+
+type instrumentedSlackClient struct {
+	client SlackClient
+}
+
+// start begins the span of a Slack API call
+func (c *instrumentedSlackClient) start(ctx context.Context, method string) (context.Context, trace.Span, time.Time) {
+	ctx, span := tracing.StartClient(ctx, "slack "+method, attribute.String("slack.method", method))
+	return ctx, span, time.Now()
+}
+
+// end closes the span and records the call latency
+func (c *instrumentedSlackClient) end(ctx context.Context, span trace.Span, method string, start time.Time, err error) {
+	metrics.SlackCall(ctx, method, start, err)
+	tracing.End(span, err)
+}
+
+func (c *instrumentedSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	ctx, span, start := c.start(ctx, "chat.postMessage")
+	respChannel, timestamp, err := c.client.PostMessageContext(ctx, channelID, options...)
+	c.end(ctx, span, "chat.postMessage", start, err)
+	return respChannel, timestamp, err
+}
+
+func (c *instrumentedSlackClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	ctx, span, start := c.start(ctx, "chat.update")
+	respChannel, respTimestamp, text, err := c.client.UpdateMessageContext(ctx, channelID, timestamp, options...)
+	c.end(ctx, span, "chat.update", start, err)
+	return respChannel, respTimestamp, text, err
+}
+
+func (c *instrumentedSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
+	ctx, span, start := c.start(ctx, "conversations.invite")
+	channel, err := c.client.InviteUsersToConversationContext(ctx, channelID, users...)
+	c.end(ctx, span, "conversations.invite", start, err)
+	return channel, err
+}
+
+func (c *instrumentedSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+	ctx, span, start := c.start(ctx, "conversations.members")
+	members, cursor, err := c.client.GetUsersInConversationContext(ctx, params)
+	c.end(ctx, span, "conversations.members", start, err)
+	return members, cursor, err
+}
+
+func (c *instrumentedSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
+	ctx, span, start := c.start(ctx, "users.info")
+	info, err := c.client.GetUserInfoContext(ctx, user)
+	c.end(ctx, span, "users.info", start, err)
+	return info, err
+}
+
+func (c *instrumentedSlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	ctx, span, start := c.start(ctx, "conversations.open")
+	channel, noOp, alreadyOpen, err := c.client.OpenConversationContext(ctx, params)
+	c.end(ctx, span, "conversations.open", start, err)
+	return channel, noOp, alreadyOpen, err
+}
+
+func (c *instrumentedSlackClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	ctx, span, start := c.start(ctx, "files.uploadV2")
+	file, err := c.client.UploadFileV2Context(ctx, params)
+	c.end(ctx, span, "files.uploadV2", start, err)
+	return file, err
+}
+
+func (c *instrumentedSlackClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	ctx, span, start := c.start(ctx, "users.profile.get")
+	profile, err := c.client.GetUserProfileContext(ctx, params)
+	c.end(ctx, span, "users.profile.get", start, err)
+	return profile, err
+}
+
+func (c *instrumentedSlackClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	ctx, span, start := c.start(ctx, "usergroups.users.list")
+	members, err := c.client.GetUserGroupMembersContext(ctx, userGroup, options...)
+	c.end(ctx, span, "usergroups.users.list", start, err)
+	return members, err
+}
+
+func (c *instrumentedSlackClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	ctx, span, start := c.start(ctx, "emoji.list")
+	emoji, err := c.client.GetEmojiContext(ctx)
+	c.end(ctx, span, "emoji.list", start, err)
+	return emoji, err
+}
+
+// InstrumentHTTPClient wraps the client to trace and record the latency of every request
+func InstrumentHTTPClient(client HTTPClient) HTTPClient {
+	return &instrumentedHTTPClient{client: client}
+}
+
+type instrumentedHTTPClient struct {
+	client HTTPClient
+}
+
+func (c *instrumentedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartClient(req.Context(), "HTTP "+req.Method+" "+req.URL.Path,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.path", req.URL.Path),
+	)
+	start := time.Now()
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+		span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+	}
+	metrics.HTTPCall(ctx, req.Method, req.URL.Path, statusCode, start)
+	tracing.End(span, err)
+	return resp, err
+}
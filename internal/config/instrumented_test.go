@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// stubSlackClient implements PostMessageContext, other methods panic if called
+type stubSlackClient struct {
+	SlackClient
+	err error
+}
+
+func (s *stubSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
+	return channelID, "1234567890.123456", s.err
+}
+
+type stubHTTPClient struct {
+	resp *http.Response
+	err  error
+}
+
+func (s *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return s.resp, s.err
+}
+
+func TestInstrumentSlackClient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "success"},
+		{name: "error", err: errors.New("channel_not_found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := InstrumentSlackClient(&stubSlackClient{err: tt.err})
+
+			channelID, timestamp, err := client.PostMessageContext(context.Background(), "C123456")
+			if channelID != "C123456" || timestamp != "1234567890.123456" {
+				t.Errorf("PostMessageContext() = %s, %s, want the wrapped client's values", channelID, timestamp)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("PostMessageContext() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestInstrumentHTTPClient(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+		err  error
+	}{
+		{name: "response", resp: &http.Response{StatusCode: http.StatusOK}},
+		{name: "transport error", err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := InstrumentHTTPClient(&stubHTTPClient{resp: tt.resp, err: tt.err})
+
+			req, _ := http.NewRequest("POST", "https://slack.com/api/views.open", nil)
+			resp, err := client.Do(req)
+			if resp != tt.resp {
+				t.Errorf("Do() response = %v, want the wrapped client's response", resp)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Do() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
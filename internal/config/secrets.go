@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/secretmanager/v1"
+)
+
+// Supported values of SECRETS_PROVIDER
+const (
+	SecretsFromEnv           = "env"
+	SecretsFromFile          = "file"
+	SecretsFromSecretManager = "secretmanager"
+)
+
+// DefaultSecretsDir is where the file provider looks for secrets when SECRETS_DIR is not set
+const DefaultSecretsDir = "/etc/secrets"
+
+// secretProvider resolves the Slack credentials, which are kept out of the plain settings
+type secretProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// newSecretProvider creates the provider selected by SECRETS_PROVIDER
+func newSecretProvider(ctx context.Context, lookup func(string) string, opts ...option.ClientOption) (secretProvider, error) {
+	switch provider := lookup("SECRETS_PROVIDER"); provider {
+	case SecretsFromEnv:
+		return envSecrets(lookup), nil
+	case SecretsFromFile:
+		return fileSecrets{dir: lookup("SECRETS_DIR")}, nil
+	case SecretsFromSecretManager:
+		projectID := lookup("SECRETS_PROJECT_ID")
+		if projectID == "" {
+			projectID = lookup("GOOGLE_CLOUD_PROJECT")
+		}
+		if projectID == "" {
+			return nil, fmt.Errorf("SECRETS_PROJECT_ID environment variable is required for the secretmanager provider")
+		}
+		service, err := secretmanager.NewService(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Secret Manager client: %w", err)
+		}
+		return &secretManagerSecrets{service: service, projectID: projectID}, nil
+	default:
+		return nil, fmt.Errorf("unknown SECRETS_PROVIDER %q", provider)
+	}
+}
+
+// secretID is the name of the file or Secret Manager secret holding a setting
+// e.g. SLACK_BOT_TOKEN is stored as slack-bot-token
+func secretID(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+// envSecrets reads secrets from the same layers as the other settings
+type envSecrets func(string) string
+
+func (e envSecrets) Secret(ctx context.Context, name string) (string, error) {
+	return e(name), nil
+}
+
+// fileSecrets reads secrets from files mounted in a directory, as done by gcloud --set-secrets
+type fileSecrets struct {
+	dir string
+}
+
+func (f fileSecrets) Secret(ctx context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, secretID(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading secret %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// secretManagerSecrets reads the latest version of each secret from Google Secret Manager
+type secretManagerSecrets struct {
+	service   *secretmanager.Service
+	projectID string
+}
+
+func (s *secretManagerSecrets) Secret(ctx context.Context, name string) (string, error) {
+	version := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", s.projectID, secretID(name))
+	resp, err := s.service.Projects.Secrets.Versions.Access(version).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("error accessing secret %s: %w", name, err)
+	}
+	if resp.Payload == nil {
+		return "", nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding secret %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/templates"
+)
+
+// DefaultHTTPTimeout is used when HTTP_TIMEOUT is not set
+const DefaultHTTPTimeout = "10s"
+
+// DefaultDedupeWindow is used when KUDOS_DEDUPE_WINDOW is not set
+const DefaultDedupeWindow = "2m"
+
+// DefaultKudoFooters are the phrases rotated in the kudos message footer when KUDOS_FOOTERS is not set
+var DefaultKudoFooters = []string{
+	"Continue fazendo a diferença!",
+	"Reconhecer faz bem para todo mundo!",
+	"Pequenos gestos, grandes resultados!",
+	"Juntos vamos mais longe!",
+	"Quem faz acontecer merece ser lembrado!",
+}
+
+// defaults is the lowest configuration layer, below the config file and the environment
+var defaults = map[string]string{
+	"TIMEZONE":               DefaultTimezone,
+	"HTTP_TIMEOUT":           DefaultHTTPTimeout,
+	"SLACK_API_URL":          slack.APIURL,
+	"DEBUG":                  "false",
+	"STORAGE_BACKEND":        "memory",
+	"SECRETS_PROVIDER":       SecretsFromEnv,
+	"SECRETS_DIR":            DefaultSecretsDir,
+	"KUDOS_MONTHLY_POINTS":   "0",
+	"KUDOS_GAP_WEEKS":        strconv.Itoa(DefaultGapWeeks),
+	"KUDOS_PROMOTE_AFTER":    strconv.Itoa(DefaultPromoteAfter),
+	"REMINDER_DRY_RUN":       "false",
+	"KUDOS_DEDUPE_WINDOW":    DefaultDedupeWindow,
+	"KUDOS_MESSAGE_TEMPLATE": templates.DefaultKudoVariant,
+	"KUDOS_FOOTERS":          strings.Join(DefaultKudoFooters, "|"),
+}
+
+// settings are the keys accepted in KUDOS_CONFIG_FILE
+var settings = []string{
+	"SLACK_BOT_TOKEN",
+	"SLACK_CHANNEL_ID",
+	"SLACK_SIGNING_SECRET",
+	"SLACK_API_URL",
+	"STORAGE_BACKEND",
+	"FIRESTORE_PROJECT_ID",
+	"FIRESTORE_DATABASE",
+	"GOOGLE_CLOUD_PROJECT",
+	"DEBUG",
+	"HTTP_TIMEOUT",
+	"TIMEZONE",
+	"SECRETS_PROVIDER",
+	"SECRETS_DIR",
+	"SECRETS_PROJECT_ID",
+	"KUDOS_MONTHLY_POINTS",
+	"KUDOS_EXPORT_USERS",
+	"KUDOS_ADMINS",
+	"KUDOS_ADMIN_GROUP",
+	"KUDOS_PROMOTE_AFTER",
+	"KUDOS_TEAMS_FILE",
+	"KUDOS_MANAGER_FIELD",
+	"KUDOS_GAP_WEEKS",
+	"KUDOS_DEDUPE_WINDOW",
+	"KUDOS_MESSAGE_TEMPLATE",
+	"KUDOS_MESSAGE_TEMPLATE_FILE",
+	"KUDOS_FOOTERS",
+	"REMINDER_DRY_RUN",
+	"REMINDER_REDIRECT_TO",
+}
+
+// newLookup layers the environment over KUDOS_CONFIG_FILE and the defaults
+// Values are trimmed and empty values fall through to the next layer
+func newLookup(getenv func(string) string) (func(string) string, error) {
+	file := map[string]string{}
+	if path := strings.TrimSpace(getenv("KUDOS_CONFIG_FILE")); path != "" {
+		var err error
+		if file, err = loadConfigFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(key string) string {
+		if value := strings.TrimSpace(getenv(key)); value != "" {
+			return value
+		}
+		if value := file[key]; value != "" {
+			return value
+		}
+		return defaults[key]
+	}, nil
+}
+
+// loadConfigFile reads a JSON object keyed by the same names as the environment variables
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading KUDOS_CONFIG_FILE: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing KUDOS_CONFIG_FILE: %w", err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if !slices.Contains(settings, key) {
+			return nil, fmt.Errorf("unknown setting %q in KUDOS_CONFIG_FILE", key)
+		}
+		switch v := value.(type) {
+		case string:
+			values[key] = strings.TrimSpace(v)
+		case float64, bool:
+			values[key] = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("setting %q in KUDOS_CONFIG_FILE must be a string, number or boolean", key)
+		}
+	}
+	return values, nil
+}
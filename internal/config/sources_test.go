@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewLookup(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		env         map[string]string
+		key         string
+		expected    string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "default",
+			key:      "HTTP_TIMEOUT",
+			expected: DefaultHTTPTimeout,
+		},
+		{
+			name:     "unset without default",
+			key:      "KUDOS_MANAGER_FIELD",
+			expected: "",
+		},
+		{
+			name:     "file overrides default",
+			file:     `{"HTTP_TIMEOUT": "5s"}`,
+			key:      "HTTP_TIMEOUT",
+			expected: "5s",
+		},
+		{
+			name:     "env overrides file",
+			file:     `{"HTTP_TIMEOUT": "5s"}`,
+			env:      map[string]string{"HTTP_TIMEOUT": "7s"},
+			key:      "HTTP_TIMEOUT",
+			expected: "7s",
+		},
+		{
+			name:     "blank env falls through to file",
+			file:     `{"TIMEZONE": "UTC"}`,
+			env:      map[string]string{"TIMEZONE": "  "},
+			key:      "TIMEZONE",
+			expected: "UTC",
+		},
+		{
+			name:     "numbers and booleans",
+			file:     `{"KUDOS_MONTHLY_POINTS": 100, "DEBUG": true}`,
+			key:      "KUDOS_MONTHLY_POINTS",
+			expected: "100",
+		},
+		{
+			name:        "unknown setting",
+			file:        `{"SLACK_CHANEL_ID": "C123456"}`,
+			wantErr:     true,
+			errContains: "unknown setting",
+		},
+		{
+			name:        "nested value",
+			file:        `{"KUDOS_EXPORT_USERS": ["U111111"]}`,
+			wantErr:     true,
+			errContains: "string, number or boolean",
+		},
+		{
+			name:        "invalid JSON",
+			file:        `{"TIMEZONE":`,
+			wantErr:     true,
+			errContains: "error parsing KUDOS_CONFIG_FILE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.json")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				env["KUDOS_CONFIG_FILE"] = path
+			}
+
+			lookup, err := newLookup(func(key string) string { return env[key] })
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("newLookup() expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("newLookup() error = %v, want error containing %q", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("newLookup() unexpected error = %v", err)
+			}
+			if got := lookup(tt.key); got != tt.expected {
+				t.Errorf("lookup(%s) = %q, want %q", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandleAdminCommand opens the settings modal of "/elogie admin" for admins
+func HandleAdminCommand(ctx context.Context, w http.ResponseWriter, userID, triggerID string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleAdminCommand")
+	defer span.End()
+
+	if !services.IsAdmin(ctx, userID, cfg) {
+		slog.WarnContext(ctx, "Admin settings requested by a non-admin", "user_id", userID)
+		respondEphemeral(w, "Você não tem permissão para alterar as configurações dos elogios.")
+		return
+	}
+	if triggerID == "" {
+		slog.WarnContext(ctx, "Missing trigger_id in admin command")
+		http.Error(w, "Missing trigger_id", http.StatusBadRequest)
+		return
+	}
+
+	if err := services.OpenAdminSettings(ctx, triggerID, cfg); err != nil {
+		slog.ErrorContext(ctx, "Error opening admin settings", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleAdminSettingsSubmission saves the settings modal
+// Invalid fields are reported in the modal, failures keep it open so the admin can retry
+func HandleAdminSettingsSubmission(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleAdminSettingsSubmission")
+	defer span.End()
+
+	if !services.IsAdmin(ctx, callback.User.ID, cfg) {
+		slog.WarnContext(ctx, "Admin settings submitted by a non-admin", "user_id", callback.User.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if callback.View.State == nil {
+		slog.WarnContext(ctx, "Invalid view state in admin settings")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	form, fieldErrors := services.ParseAdminSettings(callback.View.State.Values)
+	if len(fieldErrors) == 0 {
+		err := services.SaveAdminSettings(ctx, form, callback.User.ID, time.Now(), cfg)
+		switch {
+		case errors.Is(err, services.ErrKudoTypeExists):
+			fieldErrors["kudo_type_name"] = "Já existe um tipo de elogio oficial com esse nome"
+		case err != nil:
+			slog.ErrorContext(ctx, "Error saving admin settings", "error", err)
+			http.Error(w, "Error saving admin settings", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_action": "errors",
+			"errors":          fieldErrors,
+		})
+		return
+	}
+
+	slog.InfoContext(ctx, "Admin settings updated", "user_id", callback.User.ID)
+	w.WriteHeader(http.StatusOK)
+}
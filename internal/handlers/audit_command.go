@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+const auditUsage = "Uso: `/elogie auditoria [de:DD/MM/AAAA] [ate:DD/MM/AAAA] [ator:@pessoa] [acao:<ação>] [jsonl]`"
+
+// auditDefaultDays is the range queried when no start date is given, counting the end date
+const auditDefaultDays = 30
+
+// HandleAuditCommand handles "/elogie auditoria [de:] [ate:] [ator:] [acao:] [jsonl]" for admins
+// The summary is an ephemeral reply to the requester. With jsonl the command is acknowledged right away, every entry
+// is uploaded to the requester's DM in the background and the result is reported to responseURL
+func HandleAuditCommand(ctx context.Context, w http.ResponseWriter, userID, responseURL string, args []string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleAuditCommand")
+	defer span.End()
+
+	if !services.IsAdmin(ctx, userID, cfg) {
+		slog.WarnContext(ctx, "Audit log requested by a non-admin", "user_id", userID)
+		respondEphemeral(w, "Você não tem permissão para consultar a auditoria.")
+		return
+	}
+	if cfg.Store == nil {
+		respondEphemeral(w, "A auditoria não está disponível no momento.")
+		return
+	}
+
+	var query storage.AuditQuery
+	var from, until time.Time
+	export := false
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, ":")
+		var err error
+		switch strings.ToLower(key) {
+		case "de":
+			from, err = services.ParseExportDate(value, cfg.Location)
+		case "ate", "até":
+			until, err = services.ParseExportDate(value, cfg.Location)
+		case "ator":
+			var ok bool
+			if query.ActorID, ok = services.ParseUserMention(value); !ok {
+				err = fmt.Errorf("invalid user %q", value)
+			}
+		case "acao", "ação":
+			query.Action = strings.ToLower(value)
+			if !services.IsAuditAction(query.Action) {
+				respondEphemeral(w, fmt.Sprintf("Ação inválida: %s\nAções: %s", value, strings.Join(auditActionList(), ", ")))
+				return
+			}
+		case "jsonl":
+			export = value == ""
+			if !export {
+				err = fmt.Errorf("unexpected value %q", value)
+			}
+		default:
+			err = fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			respondEphemeral(w, fmt.Sprintf("Filtro inválido: %s\n%s", arg, auditUsage))
+			return
+		}
+	}
+
+	if until.IsZero() {
+		loc := cfg.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		now := time.Now().In(loc)
+		until = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	}
+	if from.IsZero() {
+		from = until.AddDate(0, 0, 1-auditDefaultDays)
+	}
+	if until.Before(from) {
+		respondEphemeral(w, "A data final deve ser igual ou posterior à data inicial.")
+		return
+	}
+
+	// The end date is inclusive, so the range ends at the start of the following day
+	query.From = from
+	query.To = until.AddDate(0, 0, 1)
+
+	if export {
+		// The export outlives the request, so it must not be canceled when the command is acknowledged
+		ctx = context.WithoutCancel(ctx)
+		runInBackground(func() {
+			entries, err := cfg.Store.ListAuditEntries(ctx, query)
+			if err == nil {
+				err = services.ExportAuditLog(ctx, userID, entries, from, until, cfg)
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "Error exporting audit log", "error", err)
+				respondLater(ctx, responseURL, "Não foi possível gerar a exportação. Tente novamente mais tarde.", cfg)
+				return
+			}
+			respondLater(ctx, responseURL, fmt.Sprintf("📄 Auditoria com %d registro(s) enviada por mensagem direta.", len(entries)), cfg)
+		})
+
+		respondEphemeral(w, "⏳ Gerando a auditoria, você receberá o arquivo por mensagem direta em instantes.")
+		return
+	}
+
+	entries, err := cfg.Store.ListAuditEntries(ctx, query)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing audit entries", "error", err)
+		respondEphemeral(w, "Não foi possível consultar a auditoria. Tente novamente mais tarde.")
+		return
+	}
+	respondEphemeral(w, services.FormatAuditSummary(entries, cfg.Location))
+}
+
+// auditActionList formats the actions accepted by the acao: filter
+func auditActionList() []string {
+	actions := make([]string, 0, len(models.AuditActions))
+	for _, action := range models.AuditActions {
+		actions = append(actions, "`"+action+"`")
+	}
+	return actions
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestHandleAuditCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		args         []string
+		expectUpload bool
+		uploadErr    error
+		expectedText string
+		unexpected   string
+		// expectedLater is reported to the response_url once the export is done
+		expectedLater string
+	}{
+		{
+			name:         "non-admin",
+			userID:       "U999999",
+			args:         []string{"de:01/03/2026"},
+			expectedText: "não tem permissão",
+		},
+		{
+			name:         "unknown filter",
+			userID:       "UADMIN1",
+			args:         []string{"tipo:rockstar"},
+			expectedText: "Filtro inválido",
+		},
+		{
+			name:         "invalid date",
+			userID:       "UADMIN1",
+			args:         []string{"de:março"},
+			expectedText: "Filtro inválido",
+		},
+		{
+			name:         "invalid actor",
+			userID:       "UADMIN1",
+			args:         []string{"ator:joana"},
+			expectedText: "Filtro inválido",
+		},
+		{
+			name:         "unknown action",
+			userID:       "UADMIN1",
+			args:         []string{"acao:kudo.deleted"},
+			expectedText: "Ação inválida",
+		},
+		{
+			name:         "end before start",
+			userID:       "UADMIN1",
+			args:         []string{"de:31/03/2026", "ate:01/03/2026"},
+			expectedText: "data final deve ser igual ou posterior",
+		},
+		{
+			name:         "summary includes the end date",
+			userID:       "UADMIN1",
+			args:         []string{"de:01/03/2026", "até:31/03/2026"},
+			expectedText: "2 registro(s)",
+		},
+		{
+			name:         "actor filter",
+			userID:       "UADMIN1",
+			args:         []string{"de:01/03/2026", "ate:31/03/2026", "ator:<@U111111|joana>"},
+			expectedText: "1 registro(s)",
+			unexpected:   "settings.updated",
+		},
+		{
+			name:         "action filter",
+			userID:       "UADMIN1",
+			args:         []string{"de:01/03/2026", "ate:31/03/2026", "ação:settings.updated"},
+			expectedText: "1 registro(s)",
+			unexpected:   "kudo.submitted",
+		},
+		{
+			name:          "jsonl export",
+			userID:        "UADMIN1",
+			args:          []string{"de:01/03/2026", "ate:31/03/2026", "jsonl"},
+			expectUpload:  true,
+			expectedText:  "Gerando a auditoria",
+			expectedLater: "Auditoria com 2 registro(s) enviada",
+		},
+		{
+			name:          "jsonl upload fails",
+			userID:        "UADMIN1",
+			args:          []string{"de:01/03/2026", "ate:31/03/2026", "jsonl"},
+			expectUpload:  true,
+			uploadErr:     errors.New("not_allowed"),
+			expectedText:  "Gerando a auditoria",
+			expectedLater: "Não foi possível gerar a exportação",
+		},
+	}
+
+	waitForBackground(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			store.AppendAuditEntry(ctx, &models.AuditEntry{
+				Action:    models.AuditKudoSubmitted,
+				ActorID:   "U111111",
+				TargetID:  "kudo-1",
+				CreatedAt: time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC),
+			})
+			store.AppendAuditEntry(ctx, &models.AuditEntry{
+				Action:    models.AuditSettingsUpdated,
+				ActorID:   "UADMIN1",
+				CreatedAt: time.Date(2026, 3, 31, 18, 0, 0, 0, time.UTC),
+			})
+
+			var uploaded *slack.UploadFileV2Parameters
+			httpClient, later := commandResponses(t)
+			cfg := &config.Config{
+				AdminUserIDs: []string{"UADMIN1"},
+				Location:     time.UTC,
+				Store:        store,
+				HTTPClient:   httpClient,
+				SlackAPI: &MockSlackClient{
+					UploadFileV2Func: func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+						uploaded = &params
+						return &slack.FileSummary{ID: "F123456"}, tt.uploadErr
+					},
+				},
+			}
+
+			w := httptest.NewRecorder()
+			HandleAuditCommand(ctx, w, tt.userID, testResponseURL, tt.args, cfg)
+
+			var response map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("response is not valid JSON: %v", err)
+			}
+			if !strings.Contains(response["text"], tt.expectedText) {
+				t.Errorf("text = %q, want it to contain %q", response["text"], tt.expectedText)
+			}
+			if tt.unexpected != "" && strings.Contains(response["text"], tt.unexpected) {
+				t.Errorf("text = %q, want it without %q", response["text"], tt.unexpected)
+			}
+			if tt.expectedLater != "" && (len(*later) != 1 || !strings.Contains((*later)[0], tt.expectedLater)) {
+				t.Errorf("later responses = %q, want one containing %q", *later, tt.expectedLater)
+			}
+			if tt.expectedLater == "" && len(*later) > 0 {
+				t.Errorf("later responses = %q, want none", *later)
+			}
+
+			if (uploaded != nil) != tt.expectUpload {
+				t.Fatalf("upload called = %v, want %v", uploaded != nil, tt.expectUpload)
+			}
+			if tt.expectUpload && uploaded.Filename != "auditoria_2026-03-01_2026-03-31.jsonl" {
+				t.Errorf("filename = %s, want auditoria_2026-03-01_2026-03-31.jsonl", uploaded.Filename)
+			}
+		})
+	}
+}
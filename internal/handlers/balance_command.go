@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandleBalanceCommand replies to "/elogie saldo" with the user's remaining monthly points
+func HandleBalanceCommand(ctx context.Context, w http.ResponseWriter, userID string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleBalanceCommand")
+	defer span.End()
+
+	if !cfg.PointsEnabled() {
+		respondEphemeral(w, "O sistema de pontos não está habilitado.")
+		return
+	}
+
+	if userID == "" {
+		slog.WarnContext(ctx, "Missing user_id in balance command")
+		http.Error(w, "Missing user_id", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	remaining, err := services.RemainingPoints(ctx, userID, now, cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading points balance", "error", err)
+		respondEphemeral(w, "Não foi possível consultar seu saldo agora. Tente novamente mais tarde.")
+		return
+	}
+
+	_, periodEnd := services.MonthPeriod(now, cfg.Location)
+	respondEphemeral(w, services.FormatBalanceMessage(remaining, cfg.MonthlyPoints, periodEnd))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestHandleBalanceCommand(t *testing.T) {
+	tests := []struct {
+		name               string
+		userID             string
+		monthlyPoints      int
+		kudos              []*models.Kudo
+		expectedStatusCode int
+		expectedText       string
+	}{
+		{
+			name:               "points disabled",
+			userID:             "U111111",
+			monthlyPoints:      0,
+			expectedStatusCode: http.StatusOK,
+			expectedText:       "não está habilitado",
+		},
+		{
+			name:               "full balance",
+			userID:             "U111111",
+			monthlyPoints:      100,
+			expectedStatusCode: http.StatusOK,
+			expectedText:       "*100* de 100 pontos",
+		},
+		{
+			name:          "balance after kudos this month",
+			userID:        "U111111",
+			monthlyPoints: 100,
+			kudos: []*models.Kudo{
+				{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, Points: 15, CreatedAt: time.Now()},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedText:       "*70* de 100 pontos",
+		},
+		{
+			name:               "missing user_id",
+			userID:             "",
+			monthlyPoints:      100,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			for _, kudo := range tt.kudos {
+				store.SaveKudo(context.Background(), kudo)
+			}
+
+			cfg := &config.Config{
+				MonthlyPoints: tt.monthlyPoints,
+				Location:      time.UTC,
+				Store:         store,
+			}
+
+			w := httptest.NewRecorder()
+			HandleBalanceCommand(context.Background(), w, tt.userID, cfg)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedText == "" {
+				return
+			}
+
+			var response map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("response is not valid JSON: %v", err)
+			}
+			if response["response_type"] != "ephemeral" {
+				t.Errorf("response_type = %q, want ephemeral", response["response_type"])
+			}
+			if !strings.Contains(response["text"], tt.expectedText) {
+				t.Errorf("text = %q, want it to contain %q", response["text"], tt.expectedText)
+			}
+		})
+	}
+}
@@ -1,22 +1,42 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
-	"github.com/vyper/my-matter/internal/models"
 	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // HandleBlockActions processes block_actions interactions for dynamic modal updates
-func HandleBlockActions(w http.ResponseWriter, callback *slack.InteractionCallback, viewTemplate string, cfg *config.Config) {
+func HandleBlockActions(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, viewTemplate string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleBlockActions")
+	defer span.End()
+	ctx = services.WithRequestID(ctx, callback.TriggerID)
+
 	// Check for reminder button action first
 	for _, action := range callback.ActionCallback.BlockActions {
 		if action.ActionID == "open_kudos_modal" {
 			// User clicked the button in reminder DM
-			HandleReminderButton(w, callback, viewTemplate, cfg)
+			HandleReminderButton(ctx, w, callback, viewTemplate, cfg)
+			return
+		}
+		if action.ActionID == "open_kudos_modal_for_user" {
+			// User clicked a button to recognize a specific person
+			HandlePrefilledKudosButton(ctx, w, callback, action, viewTemplate, cfg)
+			return
+		}
+		if services.IsReminderPreferenceAction(action.ActionID) {
+			// User snoozed or opted out of reminders
+			HandleReminderPreferenceButton(ctx, w, callback, action, cfg)
+			return
+		}
+		if services.IsKudoTypePromotionAction(action.ActionID) {
+			// Admin answered about a popular custom kudo type
+			HandleKudoTypePromotionButton(ctx, w, callback, action, cfg)
 			return
 		}
 	}
@@ -25,25 +45,25 @@ func HandleBlockActions(w http.ResponseWriter, callback *slack.InteractionCallba
 	for _, action := range callback.ActionCallback.BlockActions {
 		if action.ActionID == "kudo_type" && action.SelectedOption.Value != "" {
 			// Get current message value (preserve if user already typed something)
-			currentMessage := ""
+			var currentMessage *slack.RichTextBlock
 			if callback.View.State != nil {
 				if messageBlock, ok := callback.View.State.Values["kudo_message"]; ok {
 					if messageValue, ok := messageBlock["kudo_message"]; ok {
-						currentMessage = messageValue.Value
+						currentMessage = services.MessageInputRichText(messageValue)
 					}
 				}
 			}
 
 			// Only suggest message if field is empty (preserve user input)
 			// For custom type, never suggest a message
-			suggestedMessage := ""
+			var suggestedMessage *slack.RichTextBlock
 			if action.SelectedOption.Value == "custom" {
 				// For custom type, preserve current message but don't suggest anything
 				suggestedMessage = currentMessage
-			} else if currentMessage == "" {
+			} else if currentMessage == nil {
 				// For predefined types, suggest message only if empty
-				if msg, ok := models.KudoSuggestedMessages[action.SelectedOption.Value]; ok {
-					suggestedMessage = msg
+				if msg := services.SuggestedKudoMessage(ctx, action.SelectedOption.Value, cfg); msg != "" {
+					suggestedMessage = services.PlainRichText(msg)
 				}
 			} else {
 				// Preserve user's current message
@@ -51,16 +71,16 @@ func HandleBlockActions(w http.ResponseWriter, callback *slack.InteractionCallba
 			}
 
 			// Update the view with the suggested message
-			err := services.UpdateModal(
+			err := services.UpdateModal(ctx,
 				callback.View.ID,
 				callback.View.Hash,
 				action.SelectedOption.Value,
 				suggestedMessage,
-				viewTemplate,
+				services.KudosViewTemplate(ctx, viewTemplate, callback.Team.ID, callback.User.ID, cfg),
 				cfg,
 			)
 			if err != nil {
-				log.Printf("Error updating view: %v", err)
+				slog.ErrorContext(ctx, "Error updating view", "error", err)
 				http.Error(w, "Error updating modal", http.StatusInternalServerError)
 				return
 			}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandleBlockSuggestion returns the options of external selects as the user types
+func HandleBlockSuggestion(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleBlockSuggestion")
+	defer span.End()
+
+	// Slack expects a list, even when nothing matches
+	options := []*slack.OptionBlockObject{}
+	switch callback.ActionID {
+	case "kudo_emoji":
+		options = services.EmojiOptions(ctx, callback.Value, cfg)
+	default:
+		slog.WarnContext(ctx, "Unknown block suggestion", "action_id", callback.ActionID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"options": options,
+	})
+}
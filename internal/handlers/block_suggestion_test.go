@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+)
+
+func TestHandleBlockSuggestion(t *testing.T) {
+	tests := []struct {
+		name      string
+		actionID  string
+		query     string
+		wantFirst string
+		wantNone  bool
+	}{
+		{name: "emoji search", actionID: "kudo_emoji", query: "parrot", wantFirst: ":partyparrot:"},
+		{name: "emoji without matches", actionID: "kudo_emoji", query: "inexistente", wantNone: true},
+		{name: "unknown select", actionID: "other", query: "parrot", wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{SlackAPI: &MockSlackClient{
+				GetEmojiFunc: func() (map[string]string, error) {
+					return map[string]string{"partyparrot": "https://emoji.example.com/partyparrot.gif"}, nil
+				},
+			}}
+			callback := &slack.InteractionCallback{
+				Type:     slack.InteractionTypeBlockSuggestion,
+				ActionID: tt.actionID,
+				Value:    tt.query,
+			}
+
+			w := httptest.NewRecorder()
+			HandleBlockSuggestion(context.Background(), w, callback, cfg)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			var response struct {
+				Options []slack.OptionBlockObject `json:"options"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("error decoding response %q: %v", w.Body.String(), err)
+			}
+			if response.Options == nil {
+				t.Fatalf("response = %s, want an options list", w.Body.String())
+			}
+			if tt.wantNone {
+				if len(response.Options) != 0 {
+					t.Errorf("options = %+v, want none", response.Options)
+				}
+				return
+			}
+			if len(response.Options) == 0 || response.Options[0].Value != tt.wantFirst {
+				t.Errorf("options = %+v, want %s first", response.Options, tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+const exportUsage = "Uso: `/elogie exportar <de> <até> [csv|json]`, com datas no formato DD/MM/AAAA"
+
+// HandleExportCommand handles "/elogie exportar <de> <até> [csv|json]"
+// The command is acknowledged right away, the export file is uploaded to the requester's DM in the background
+// and the result is reported to responseURL
+func HandleExportCommand(ctx context.Context, w http.ResponseWriter, userID, responseURL string, args []string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleExportCommand")
+	defer span.End()
+
+	if !cfg.CanExport(userID) {
+		slog.WarnContext(ctx, "User is not allowed to export kudos")
+		respondEphemeral(w, "Você não tem permissão para exportar elogios.")
+		return
+	}
+
+	if len(args) < 2 || len(args) > 3 {
+		respondEphemeral(w, exportUsage)
+		return
+	}
+
+	from, err := services.ParseExportDate(args[0], cfg.Location)
+	if err != nil {
+		respondEphemeral(w, fmt.Sprintf("Data inicial inválida: %s\n%s", args[0], exportUsage))
+		return
+	}
+
+	until, err := services.ParseExportDate(args[1], cfg.Location)
+	if err != nil {
+		respondEphemeral(w, fmt.Sprintf("Data final inválida: %s\n%s", args[1], exportUsage))
+		return
+	}
+
+	if until.Before(from) {
+		respondEphemeral(w, "A data final deve ser igual ou posterior à data inicial.")
+		return
+	}
+
+	format := services.ExportFormatCSV
+	if len(args) == 3 {
+		format = strings.ToLower(args[2])
+	}
+	if format != services.ExportFormatCSV && format != services.ExportFormatJSON {
+		respondEphemeral(w, fmt.Sprintf("Formato inválido: %s\n%s", args[2], exportUsage))
+		return
+	}
+
+	// The export outlives the request, so it must not be canceled when the command is acknowledged
+	ctx = context.WithoutCancel(ctx)
+	runInBackground(func() {
+		// The end date is inclusive, so the range ends at the start of the following day
+		count, err := services.ExportKudos(ctx, userID, from, until.AddDate(0, 0, 1), format, cfg)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error exporting kudos", "error", err)
+			respondLater(ctx, responseURL, "Não foi possível gerar a exportação. Tente novamente mais tarde.", cfg)
+			return
+		}
+		respondLater(ctx, responseURL, fmt.Sprintf("📄 Exportação com %d elogio(s) enviada por mensagem direta.", count), cfg)
+	})
+
+	respondEphemeral(w, "⏳ Gerando a exportação, você receberá o arquivo por mensagem direta em instantes.")
+}
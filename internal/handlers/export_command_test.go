@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestHandleExportCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		args           []string
+		expectUpload   bool
+		expectedFormat string
+		expectedText   string
+		expectedLater  string
+		uploadErr      error
+	}{
+		{
+			name:         "unauthorized user",
+			userID:       "U999999",
+			args:         []string{"01/03/2026", "31/03/2026"},
+			expectedText: "não tem permissão",
+		},
+		{
+			name:         "missing dates",
+			userID:       "U123456",
+			args:         []string{"01/03/2026"},
+			expectedText: "Uso:",
+		},
+		{
+			name:         "invalid start date",
+			userID:       "U123456",
+			args:         []string{"março", "31/03/2026"},
+			expectedText: "Data inicial inválida",
+		},
+		{
+			name:         "invalid end date",
+			userID:       "U123456",
+			args:         []string{"01/03/2026", "31/02/2026"},
+			expectedText: "Data final inválida",
+		},
+		{
+			name:         "end before start",
+			userID:       "U123456",
+			args:         []string{"31/03/2026", "01/03/2026"},
+			expectedText: "data final deve ser igual ou posterior",
+		},
+		{
+			name:         "invalid format",
+			userID:       "U123456",
+			args:         []string{"01/03/2026", "31/03/2026", "xlsx"},
+			expectedText: "Formato inválido",
+		},
+		{
+			name:           "default csv export includes the end date",
+			userID:         "U123456",
+			args:           []string{"01/03/2026", "31/03/2026"},
+			expectUpload:   true,
+			expectedFormat: "csv",
+			expectedText:   "Gerando a exportação",
+			expectedLater:  "2 elogio(s)",
+		},
+		{
+			name:           "json export",
+			userID:         "U123456",
+			args:           []string{"2026-03-01", "2026-03-15", "JSON"},
+			expectUpload:   true,
+			expectedFormat: "json",
+			expectedText:   "Gerando a exportação",
+			expectedLater:  "1 elogio(s)",
+		},
+		{
+			name:           "upload failure is reported later",
+			userID:         "U123456",
+			args:           []string{"01/03/2026", "31/03/2026"},
+			expectUpload:   true,
+			expectedFormat: "csv",
+			uploadErr:      errors.New("not_allowed"),
+			expectedText:   "Gerando a exportação",
+			expectedLater:  "Não foi possível gerar a exportação",
+		},
+	}
+	waitForBackground(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			for _, day := range []int{10, 31} {
+				store.SaveKudo(context.Background(), &models.Kudo{
+					SenderID:     "U111111",
+					RecipientIDs: []string{"U222222"},
+					CreatedAt:    time.Date(2026, 3, day, 18, 0, 0, 0, time.UTC),
+				})
+			}
+
+			var uploaded *slack.UploadFileV2Parameters
+			httpClient, later := commandResponses(t)
+			cfg := &config.Config{
+				ExportUserIDs: []string{"U123456"},
+				Location:      time.UTC,
+				Store:         store,
+				HTTPClient:    httpClient,
+				SlackAPI: &MockSlackClient{
+					UploadFileV2Func: func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+						uploaded = &params
+						return &slack.FileSummary{ID: "F123456"}, tt.uploadErr
+					},
+				},
+			}
+
+			w := httptest.NewRecorder()
+			HandleExportCommand(context.Background(), w, tt.userID, testResponseURL, tt.args, cfg)
+
+			var response map[string]string
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("response is not valid JSON: %v", err)
+			}
+			if !strings.Contains(response["text"], tt.expectedText) {
+				t.Errorf("text = %q, want it to contain %q", response["text"], tt.expectedText)
+			}
+			if tt.expectedLater != "" && (len(*later) != 1 || !strings.Contains((*later)[0], tt.expectedLater)) {
+				t.Errorf("later responses = %q, want one containing %q", *later, tt.expectedLater)
+			}
+			if tt.expectedLater == "" && len(*later) > 0 {
+				t.Errorf("later responses = %q, want none", *later)
+			}
+
+			if (uploaded != nil) != tt.expectUpload {
+				t.Fatalf("upload called = %v, want %v", uploaded != nil, tt.expectUpload)
+			}
+			if tt.expectUpload && !strings.HasSuffix(uploaded.Filename, "."+tt.expectedFormat) {
+				t.Errorf("filename = %s, want .%s extension", uploaded.Filename, tt.expectedFormat)
+			}
+		})
+	}
+}
+
+// testResponseURL is the response_url of the slash commands in tests
+const testResponseURL = "https://hooks.slack.com/commands/T123456/1/abc"
+
+// waitForBackground runs the background work of slash commands before they return
+func waitForBackground(t *testing.T) {
+	t.Helper()
+	previous := runInBackground
+	runInBackground = func(task func()) { task() }
+	t.Cleanup(func() { runInBackground = previous })
+}
+
+// commandResponses records the texts posted to testResponseURL
+func commandResponses(t *testing.T) (*MockHTTPClient, *[]string) {
+	t.Helper()
+	var texts []string
+	return &MockHTTPClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.String() != testResponseURL {
+				t.Errorf("posted to %s, want the response_url", req.URL)
+			}
+			var body map[string]string
+			json.NewDecoder(req.Body).Decode(&body)
+			texts = append(texts, body["text"])
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: http.NoBody}, nil
+		},
+	}, &texts
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandleKudoTypePromotionButton handles the promote, merge and dismiss buttons of the admin DMs
+// about popular custom kudo types
+func HandleKudoTypePromotionButton(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, action *slack.BlockAction, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleKudoTypePromotionButton")
+	defer span.End()
+
+	if !services.IsAdmin(ctx, callback.User.ID, cfg) {
+		slog.WarnContext(ctx, "Kudo type promotion answered by a non-admin", "user_id", callback.User.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	message := services.PromotionMessage{
+		CustomTypeID: action.Value,
+		ChannelID:    callback.Container.ChannelID,
+		Timestamp:    callback.Container.MessageTs,
+	}
+	err := services.AnswerKudoTypePromotion(ctx, action.ActionID, callback.TriggerID, callback.User.ID, message, time.Now(), cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error answering kudo type promotion", "action_id", action.ActionID, "error", err)
+		http.Error(w, "Error answering kudo type promotion", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(ctx, "Kudo type promotion answered", "custom_type_id", action.Value, "action_id", action.ActionID)
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleKudoTypePromotionSubmission processes the promote and merge modals
+// Invalid fields are reported in the modal, otherwise it closes and the admin DM shows the decision
+func HandleKudoTypePromotionSubmission(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleKudoTypePromotionSubmission")
+	defer span.End()
+
+	if !services.IsAdmin(ctx, callback.User.ID, cfg) {
+		slog.WarnContext(ctx, "Kudo type promotion submitted by a non-admin", "user_id", callback.User.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	message, err := services.ParsePromotionMessage(callback.View.PrivateMetadata)
+	if err != nil || callback.View.State == nil {
+		slog.WarnContext(ctx, "Invalid kudo type promotion submission", "error", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	values := callback.View.State.Values
+
+	fieldErrors := make(map[string]string)
+	now := time.Now()
+	switch callback.View.CallbackID {
+	case services.PromoteKudoTypeCallbackID:
+		promoted := &models.KudoType{
+			Name:             strings.TrimSpace(values["kudo_type_name"]["kudo_type_name"].Value),
+			Emoji:            values["kudo_emoji"]["kudo_emoji"].SelectedOption.Value,
+			Description:      strings.TrimSpace(values["kudo_type_description"]["kudo_type_description"].Value),
+			SuggestedMessage: strings.TrimSpace(values["kudo_type_suggested_message"]["kudo_type_suggested_message"].Value),
+			Theme:            services.ParseKudoTheme(values, fieldErrors),
+		}
+		if promoted.Name == "" {
+			fieldErrors["kudo_type_name"] = "Por favor, preencha o nome do tipo de elogio"
+		}
+		if promoted.Description == "" {
+			fieldErrors["kudo_type_description"] = "Por favor, descreva quando usar este tipo de elogio"
+		}
+		if promoted.SuggestedMessage == "" {
+			fieldErrors["kudo_type_suggested_message"] = "Por favor, preencha a mensagem sugerida"
+		}
+		if len(fieldErrors) == 0 {
+			err = services.PromoteKudoType(ctx, message, promoted, callback.User.ID, now, cfg)
+			if errors.Is(err, services.ErrKudoTypeExists) {
+				fieldErrors["kudo_type_name"] = "Já existe um tipo de elogio oficial com esse nome"
+			}
+		}
+	case services.MergeKudoTypeCallbackID:
+		target := values["kudo_type_target"]["kudo_type_target"].SelectedOption.Value
+		err = services.MergeKudoType(ctx, message, target, callback.User.ID, now, cfg)
+		if errors.Is(err, services.ErrUnknownKudoType) {
+			fieldErrors["kudo_type_target"] = "Este tipo de elogio não está mais disponível. Escolha outro tipo."
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_action": "errors",
+			"errors":          fieldErrors,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrKudoTypeDecided):
+		slog.InfoContext(ctx, "Kudo type already decided", "custom_type_id", message.CustomTypeID)
+	case err != nil:
+		// The modal closes anyway, the admin can answer again from the DM
+		slog.ErrorContext(ctx, "Error deciding kudo type", "custom_type_id", message.CustomTypeID, "error", err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
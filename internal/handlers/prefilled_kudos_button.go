@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandlePrefilledKudosButton handles buttons that open the kudos modal for a specific person
+// The button value holds the user ID to pre-select as recipient
+func HandlePrefilledKudosButton(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, action *slack.BlockAction, viewTemplate string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandlePrefilledKudosButton")
+	defer span.End()
+
+	triggerID := callback.TriggerID
+	if triggerID == "" {
+		slog.WarnContext(ctx, "Missing trigger_id in prefilled kudos button interaction")
+		http.Error(w, "Missing trigger_id", http.StatusBadRequest)
+		return
+	}
+
+	viewTemplate = services.KudosViewTemplate(ctx, viewTemplate, callback.Team.ID, callback.User.ID, cfg)
+
+	if action.Value != "" {
+		prefilled, err := services.PrefillRecipients(viewTemplate, []string{action.Value})
+		if err != nil {
+			slog.WarnContext(ctx, "Could not pre-fill recipient", "recipient_id", action.Value, "error", err)
+		} else {
+			viewTemplate = prefilled
+		}
+	}
+
+	err := services.OpenModal(ctx, triggerID, viewTemplate, cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error opening modal from prefilled kudos button", "error", err)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_action": "errors",
+			"errors": map[string]string{
+				action.BlockID: "Não foi possível abrir o modal. Tente usar o comando /elogie",
+			},
+		})
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
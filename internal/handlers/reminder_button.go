@@ -1,30 +1,36 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
 	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // HandleReminderButton handles the button click from reminder DMs
 // Opens the kudos modal when user clicks "Elogiar agora!"
-func HandleReminderButton(w http.ResponseWriter, callback *slack.InteractionCallback, viewTemplate string, cfg *config.Config) {
+func HandleReminderButton(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, viewTemplate string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleReminderButton")
+	defer span.End()
+
 	// Extract trigger_id from the callback
 	triggerID := callback.TriggerID
 	if triggerID == "" {
-		log.Printf("Missing trigger_id in reminder button interaction")
+		slog.WarnContext(ctx, "Missing trigger_id in reminder button interaction")
 		http.Error(w, "Missing trigger_id", http.StatusBadRequest)
 		return
 	}
 
 	// Open the modal using the same service as slash command
-	err := services.OpenModal(triggerID, viewTemplate, cfg)
+	viewTemplate = services.KudosViewTemplate(ctx, viewTemplate, callback.Team.ID, callback.User.ID, cfg)
+	err := services.OpenModal(ctx, triggerID, viewTemplate, cfg)
 	if err != nil {
-		log.Printf("Error opening modal from reminder button: %v", err)
+		slog.ErrorContext(ctx, "Error opening modal from reminder button", "error", err)
 		// Return a visible error to the user
 		errorResponse := map[string]interface{}{
 			"response_action": "errors",
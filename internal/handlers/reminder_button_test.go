@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -98,7 +99,7 @@ func TestHandleReminderButton(t *testing.T) {
 			}
 
 			w := httptest.NewRecorder()
-			HandleReminderButton(w, tt.callback, `{"type":"modal","title":{"type":"plain_text","text":"Test"}}`, cfg)
+			HandleReminderButton(context.Background(), w, tt.callback, `{"type":"modal","title":{"type":"plain_text","text":"Test"}}`, cfg)
 
 			if w.Code != tt.expectedStatusCode {
 				t.Errorf("HandleReminderButton() status = %d, want %d", w.Code, tt.expectedStatusCode)
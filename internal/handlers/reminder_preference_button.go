@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// HandleReminderPreferenceButton handles the snooze and opt-out buttons of reminder DMs
+// The choice is saved for the user and the DM is replaced with a confirmation
+func HandleReminderPreferenceButton(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, action *slack.BlockAction, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleReminderPreferenceButton")
+	defer span.End()
+
+	err := services.SaveReminderPreference(ctx,
+		callback.User.ID,
+		action.ActionID,
+		callback.Container.ChannelID,
+		callback.Container.MessageTs,
+		time.Now(),
+		cfg,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error saving reminder preference", "action_id", action.ActionID, "error", err)
+		http.Error(w, "Error saving reminder preference", http.StatusInternalServerError)
+		return
+	}
+
+	slog.InfoContext(ctx, "Reminder preference saved", "user_id", callback.User.ID, "action_id", action.ActionID)
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestHandleReminderPreferenceButton(t *testing.T) {
+	tests := []struct {
+		name               string
+		actionID           string
+		updateErr          error
+		expectedStatusCode int
+		expectOptedOut     bool
+		expectSnoozed      bool
+	}{
+		{
+			name:               "opt out",
+			actionID:           "reminder_opt_out",
+			expectedStatusCode: http.StatusOK,
+			expectOptedOut:     true,
+		},
+		{
+			name:               "pause for a month",
+			actionID:           "reminder_snooze_month",
+			expectedStatusCode: http.StatusOK,
+			expectSnoozed:      true,
+		},
+		{
+			name:               "message update fails",
+			actionID:           "reminder_snooze_week",
+			updateErr:          errors.New("message_not_found"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectSnoozed:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updatedChannel, updatedTS string
+			mockSlack := &MockSlackClient{
+				UpdateMessageFunc: func(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+					updatedChannel, updatedTS = channelID, timestamp
+					return channelID, timestamp, "", tt.updateErr
+				},
+			}
+			store := storage.NewMemoryStore()
+			cfg := &config.Config{SlackAPI: mockSlack, Store: store, Location: time.UTC}
+
+			callback := &slack.InteractionCallback{
+				Type:      slack.InteractionTypeBlockActions,
+				User:      slack.User{ID: "U111111"},
+				Container: slack.Container{ChannelID: "D123456", MessageTs: "1700000000.000001"},
+				ActionCallback: slack.ActionCallbacks{BlockActions: []*slack.BlockAction{
+					{ActionID: tt.actionID, BlockID: "reminder_actions"},
+				}},
+			}
+			w := httptest.NewRecorder()
+
+			HandleBlockActions(context.Background(), w, callback, "", cfg)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.expectedStatusCode)
+			}
+			if updatedChannel != "D123456" || updatedTS != "1700000000.000001" {
+				t.Errorf("updated message %s/%s, want the reminder DM", updatedChannel, updatedTS)
+			}
+
+			preferences, err := store.ListReminderPreferences(context.Background())
+			if err != nil || len(preferences) != 1 {
+				t.Fatalf("ListReminderPreferences() = %v, %v, want one preference", preferences, err)
+			}
+			if preferences[0].UserID != "U111111" {
+				t.Errorf("UserID = %s, want U111111", preferences[0].UserID)
+			}
+			if preferences[0].OptedOut != tt.expectOptedOut {
+				t.Errorf("OptedOut = %v, want %v", preferences[0].OptedOut, tt.expectOptedOut)
+			}
+			if snoozed := !preferences[0].SnoozedUntil.IsZero(); snoozed != tt.expectSnoozed {
+				t.Errorf("SnoozedUntil = %v, want snoozed %v", preferences[0].SnoozedUntil, tt.expectSnoozed)
+			}
+		})
+	}
+}
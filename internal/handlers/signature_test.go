@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vyper/my-matter/internal/slacktest"
+)
+
+func TestVerifySignature(t *testing.T) {
+	form := url.Values{"command": {"/elogie"}, "user_id": {"U123456"}}
+
+	tests := []struct {
+		name      string
+		secret    string
+		tamper    bool
+		timestamp string
+		wantErr   bool
+	}{
+		{name: "valid signature", secret: "signing-secret"},
+		{name: "wrong secret", secret: "another-secret", wantErr: true},
+		{name: "tampered body", secret: "signing-secret", tamper: true, wantErr: true},
+		{name: "expired timestamp", secret: "signing-secret", timestamp: "1500000000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := slacktest.NewSignedRequest("signing-secret", form)
+			if tt.tamper {
+				req.Body = io.NopCloser(strings.NewReader("command=%2Felogie&user_id=U999999"))
+			}
+			if tt.timestamp != "" {
+				req.Header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			}
+
+			err := VerifySignature(req, tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// The form is still readable after verification
+			if err := req.ParseForm(); err != nil {
+				t.Fatalf("ParseForm() unexpected error = %v", err)
+			}
+			if req.FormValue("user_id") != "U123456" {
+				t.Errorf("user_id = %q, want U123456", req.FormValue("user_id"))
+			}
+		})
+	}
+}
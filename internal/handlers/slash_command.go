@@ -1,28 +1,78 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	"encoding/json"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/vyper/my-matter/internal/config"
 	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
-// HandleSlashCommand processes the /elogie slash command and opens the kudos modal
+// HandleSlashCommand processes the /elogie slash command
+// Subcommands (e.g. "/elogie saldo") are routed to their handlers, anything else opens the kudos modal
 func HandleSlashCommand(w http.ResponseWriter, r *http.Request, viewTemplate string, cfg *config.Config) {
+	ctx, span := tracing.Start(r.Context(), "handlers.HandleSlashCommand")
+	defer span.End()
+	ctx = services.WithRequestID(ctx, r.FormValue("trigger_id"))
+
+	args := strings.Fields(r.FormValue("text"))
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "saldo":
+			HandleBalanceCommand(ctx, w, r.FormValue("user_id"), cfg)
+			return
+		case "exportar":
+			HandleExportCommand(ctx, w, r.FormValue("user_id"), r.FormValue("response_url"), args[1:], cfg)
+			return
+		case "admin":
+			HandleAdminCommand(ctx, w, r.FormValue("user_id"), r.FormValue("trigger_id"), cfg)
+			return
+		case "auditoria":
+			HandleAuditCommand(ctx, w, r.FormValue("user_id"), r.FormValue("response_url"), args[1:], cfg)
+			return
+		}
+	}
+
 	triggerID := r.FormValue("trigger_id")
 	if triggerID == "" {
-		log.Printf("Missing trigger_id in slash command")
+		slog.WarnContext(ctx, "Missing trigger_id in slash command")
 		http.Error(w, "Missing trigger_id", http.StatusBadRequest)
 		return
 	}
 
-	err := services.OpenModal(triggerID, viewTemplate, cfg)
+	viewTemplate = services.KudosViewTemplate(ctx, viewTemplate, r.FormValue("team_id"), r.FormValue("user_id"), cfg)
+
+	err := services.OpenModal(ctx, triggerID, viewTemplate, cfg)
 	if err != nil {
-		log.Printf("Error opening modal: %v", err)
+		slog.ErrorContext(ctx, "Error opening modal", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// runInBackground runs slow slash command work after the command is acknowledged, since Slack gives up after 3 seconds
+// Tests replace it to wait for the work
+var runInBackground = func(task func()) { go task() }
+
+// respondLater reports the result of background work to the slash command's response_url
+func respondLater(ctx context.Context, responseURL, text string, cfg *config.Config) {
+	if err := services.RespondToCommand(ctx, responseURL, text, cfg); err != nil {
+		slog.ErrorContext(ctx, "Error responding to slash command", "error", err)
+	}
+}
+
+// respondEphemeral replies to a slash command with a message only the caller can see
+func respondEphemeral(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response_type": "ephemeral",
+		"text":          text,
+	})
+}
@@ -1,33 +1,58 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/metrics"
 	"github.com/vyper/my-matter/internal/models"
 	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // HandleViewSubmission processes modal submission and posts the kudos message
-func HandleViewSubmission(w http.ResponseWriter, callback *slack.InteractionCallback, cfg *config.Config) {
+func HandleViewSubmission(ctx context.Context, w http.ResponseWriter, callback *slack.InteractionCallback, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "handlers.HandleViewSubmission")
+	defer span.End()
+	ctx = services.WithRequestID(ctx, callback.View.ID)
+
+	// Admins promoting or merging a custom kudo type
+	if services.IsKudoTypePromotionView(callback.View.CallbackID) {
+		HandleKudoTypePromotionSubmission(ctx, w, callback, cfg)
+		return
+	}
+	if callback.View.CallbackID == services.AdminSettingsCallbackID {
+		HandleAdminSettingsSubmission(ctx, w, callback, cfg)
+		return
+	}
+
 	// Check if State is properly initialized
 	if callback.View.State == nil || callback.View.State.Values == nil {
-		log.Printf("Invalid view state in submission")
+		slog.WarnContext(ctx, "Invalid view state in submission")
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
 	// Extract values from modal submission
 	selectedUsers := callback.View.State.Values["kudo_users"]["kudo_users"].SelectedUsers
-	kudoMessage := callback.View.State.Values["kudo_message"]["kudo_message"].Value
+	kudoMessage, kudoRichMessage := services.ParseMessageInput(callback.View.State.Values["kudo_message"]["kudo_message"])
 	kudoTypeFullText := callback.View.State.Values["kudo_type"]["kudo_type"].SelectedOption.Text.Text
 	kudoTypeValue := callback.View.State.Values["kudo_type"]["kudo_type"].SelectedOption.Value
 
 	var kudoTypeEmoji, kudoTypeText string
+	fieldErrors := make(map[string]string)
+	// Block showing errors about the name of custom types, empty for predefined types
+	typeNameField := ""
 
 	// Handle custom kudo type
 	if kudoTypeValue == "custom" {
@@ -42,59 +67,200 @@ func HandleViewSubmission(w http.ResponseWriter, callback *slack.InteractionCall
 		}
 
 		// Validate custom description
-		errors := make(map[string]string)
 		if customDescription == "" {
-			errors["kudo_description"] = "Por favor, preencha o nome do tipo de elogio"
+			fieldErrors["kudo_description"] = "Por favor, preencha o nome do tipo de elogio"
 		} else if len(customDescription) > 150 {
-			errors["kudo_description"] = "Nome do tipo de elogio muito longo (máximo 150 caracteres)"
+			fieldErrors["kudo_description"] = "Nome do tipo de elogio muito longo (máximo 150 caracteres)"
 		}
 
 		// Validate message is required for custom type
 		if kudoMessage == "" {
-			errors["kudo_message"] = "A mensagem é obrigatória para elogios personalizados"
+			fieldErrors["kudo_message"] = "A mensagem é obrigatória para elogios personalizados"
 		}
 
-		// Return validation errors if any
-		if len(errors) > 0 {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"response_action": "errors",
-				"errors":          errors,
-			})
-			return
+		// Use the picked emoji, if any, and custom description
+		kudoTypeEmoji = services.CustomKudoEmoji(callback.View.State.Values["kudo_emoji"]["kudo_emoji"].SelectedOption.Value)
+		kudoTypeText = customDescription
+		typeNameField = "kudo_description"
+
+		// Names admins promoted or merged are posted as the official type
+		if official := services.OfficialKudoTypeFor(ctx, callback.Team.ID, customDescription, cfg); official != nil {
+			kudoTypeValue, kudoTypeEmoji, kudoTypeText = official.ID, official.Emoji, official.Name
+			typeNameField = ""
+		}
+	} else if services.IsSavedKudoType(kudoTypeValue) {
+		// Custom type saved in the sender's workspace
+		savedType, err := services.GetSavedKudoType(ctx, kudoTypeValue, cfg)
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "Error loading custom kudo type", "kudo_type", kudoTypeValue, "error", err)
+			fieldErrors["kudo_type"] = "Não foi possível carregar o tipo de elogio. Tente novamente."
+		case savedType == nil:
+			fieldErrors["kudo_type"] = "Este tipo de elogio não está mais disponível. Escolha outro tipo."
+		default:
+			kudoTypeEmoji = savedType.Emoji
+			kudoTypeText = savedType.Name
 		}
 
-		// Use fixed emoji and custom description
-		kudoTypeEmoji = "✏️"
-		kudoTypeText = customDescription
+		// Posted like any custom type
+		kudoTypeValue = "custom"
+		typeNameField = "kudo_type"
+
+		// Types admins promoted or merged are posted as the official type
+		if official := services.ResolvedKudoType(ctx, savedType, cfg); official != nil {
+			kudoTypeValue, kudoTypeEmoji, kudoTypeText = official.ID, official.Emoji, official.Name
+			typeNameField = ""
+		}
+
+		if kudoMessage == "" {
+			fieldErrors["kudo_message"] = "A mensagem é obrigatória para elogios personalizados"
+		}
 	} else {
-		// Regular predefined kudo type
+		// Regular predefined kudo type, or one promoted by admins
 		// If the user didn't interact with the message field, use the suggested message
 		if kudoMessage == "" {
-			if suggestedMsg, ok := models.KudoSuggestedMessages[kudoTypeValue]; ok {
-				kudoMessage = suggestedMsg
-			}
+			kudoMessage = services.SuggestedKudoMessage(ctx, kudoTypeValue, cfg)
 		}
 
 		// Parse emoji and text from kudo type
 		kudoTypeEmoji, kudoTypeText = services.ParseKudoTypeText(kudoTypeFullText)
 	}
 
+	// Apply the moderation rules to what the sender typed
+	rules := services.LoadModerationRules(ctx, cfg)
+	moderationErrors := make(map[string]string)
+	if _, invalid := fieldErrors["kudo_message"]; !invalid {
+		if moderationError := services.ModerateMessage(rules, kudoMessage); moderationError != "" {
+			moderationErrors["kudo_message"] = moderationError
+		}
+	}
+	if _, invalid := fieldErrors[typeNameField]; typeNameField != "" && !invalid {
+		if moderationError := services.ModerateTypeName(rules, kudoTypeText); moderationError != "" {
+			moderationErrors[typeNameField] = moderationError
+		}
+	}
+	if len(moderationErrors) > 0 {
+		maps.Copy(fieldErrors, moderationErrors)
+		services.RecordAudit(ctx, models.AuditKudoRejected, callback.User.ID, "", nil, map[string]interface{}{
+			"recipient_ids": selectedUsers,
+			"type_id":       kudoTypeValue,
+			"type_text":     kudoTypeText,
+			"message":       kudoMessage,
+			"errors":        moderationErrors,
+		}, cfg)
+	}
+
+	// Validate points against the sender's monthly balance
+	points, pointsError := validatePoints(ctx, callback, len(selectedUsers), cfg)
+	if pointsError != "" {
+		fieldErrors["kudo_points"] = pointsError
+	}
+
+	// Return validation errors if any
+	if len(fieldErrors) > 0 {
+		for field := range fieldErrors {
+			metrics.ValidationError(ctx, field)
+		}
+		metrics.ModalSubmitted(ctx, metrics.SubmissionInvalid)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_action": "errors",
+			"errors":          fieldErrors,
+		})
+		return
+	}
+
 	// Post the kudos message to Slack
-	err := services.PostKudos(
-		callback.User.ID,
-		selectedUsers,
-		kudoTypeEmoji,
-		kudoTypeText,
-		kudoMessage,
-		cfg,
-	)
-	if err != nil {
-		log.Printf("Error posting kudos: %v", err)
+	kudo := &models.Kudo{
+		SenderID:        callback.User.ID,
+		RecipientIDs:    selectedUsers,
+		TypeID:          kudoTypeValue,
+		TypeEmoji:       kudoTypeEmoji,
+		TypeText:        kudoTypeText,
+		Message:         kudoMessage,
+		MessageRichText: kudoRichMessage,
+		Points:          points,
+		CreatedAt:       time.Now(),
+		// Slack retries and double clicks resend the same view
+		IdempotencyKey: services.ViewIdempotencyKey(callback.View.ID, callback.View.Hash),
+	}
+	// Admins may have moved the kudos channel with /elogie admin
+	postCfg, _ := services.ApplySettings(ctx, cfg)
+	err := services.PostKudos(ctx, kudo, postCfg)
+	switch {
+	case errors.Is(err, services.ErrInsufficientPoints):
+		// Another submission spent the balance since it was checked
+		slog.InfoContext(ctx, "Kudos exceed the points balance", "error", err)
+		metrics.ValidationError(ctx, "kudo_points")
+		metrics.ModalSubmitted(ctx, metrics.SubmissionInvalid)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response_action": "errors",
+			"errors":          map[string]string{"kudo_points": "Saldo insuficiente: seus pontos deste mês foram usados em outro elogio"},
+		})
+		return
+	case errors.Is(err, services.ErrDuplicateKudo):
+		slog.InfoContext(ctx, "Skipping duplicate kudos submission", "view_id", callback.View.ID, "error", err)
+		metrics.ModalSubmitted(ctx, metrics.SubmissionDuplicate)
+	case err != nil:
+		slog.ErrorContext(ctx, "Error posting kudos", "error", err)
 		// Note: We don't return error to user as modal already closed
+		metrics.ModalSubmitted(ctx, metrics.SubmissionFailed)
+	default:
+		metrics.ModalSubmitted(ctx, metrics.SubmissionPosted)
+		services.RecordAudit(ctx, models.AuditKudoSubmitted, kudo.SenderID, kudo.ID, nil, kudo, cfg)
+
+		// Offer the custom type to the sender's workspace next time, and ask admins about it once it's popular
+		if kudo.TypeID == "custom" {
+			kudoType, err := services.SaveCustomKudoType(ctx, callback.Team.ID, kudo, cfg)
+			if err != nil {
+				slog.WarnContext(ctx, "Could not save custom kudo type", "error", err)
+			}
+			if err := services.SuggestKudoTypePromotion(ctx, kudoType, cfg); err != nil {
+				slog.WarnContext(ctx, "Could not suggest kudo type promotion", "error", err)
+			}
+		}
 	}
 
 	// Acknowledge submission (modal will close)
 	w.WriteHeader(http.StatusOK)
 }
+
+// validatePoints parses the points field and checks the sender can afford them
+// Returns the points per recipient and an error message for the kudo_points block
+func validatePoints(ctx context.Context, callback *slack.InteractionCallback, recipientCount int, cfg *config.Config) (int, string) {
+	if !cfg.PointsEnabled() {
+		return 0, ""
+	}
+
+	value := strings.TrimSpace(callback.View.State.Values["kudo_points"]["kudo_points"].Value)
+	if value == "" {
+		return 0, ""
+	}
+
+	points, err := strconv.Atoi(value)
+	if err != nil || points < 0 {
+		return 0, "Informe um número inteiro de pontos"
+	}
+	if points == 0 {
+		return 0, ""
+	}
+	// Checked before multiplying by the recipients so the total can't overflow
+	if points > cfg.MonthlyPoints {
+		return 0, fmt.Sprintf("Informe no máximo %d pontos, o saldo mensal completo", cfg.MonthlyPoints)
+	}
+
+	remaining, err := services.RemainingPoints(ctx, callback.User.ID, time.Now(), cfg)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error loading points balance", "error", err)
+		return 0, "Não foi possível verificar seu saldo de pontos. Tente novamente."
+	}
+
+	if total := points * recipientCount; total > remaining {
+		return 0, fmt.Sprintf("Saldo insuficiente: %d pontos para %d pessoa(s) somam %d, mas você tem %d disponíveis este mês", points, recipientCount, total, remaining)
+	}
+
+	return points, ""
+}
@@ -1,14 +1,22 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
 	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/services"
+	"github.com/vyper/my-matter/internal/storage"
 )
 
 func TestHandleViewSubmission(t *testing.T) {
@@ -344,7 +352,7 @@ func TestHandleViewSubmission(t *testing.T) {
 			// We do this by calling the actual services, which will use our mock
 			w := httptest.NewRecorder()
 
-			HandleViewSubmission(w, tt.callback, cfg)
+			HandleViewSubmission(context.Background(), w, tt.callback, cfg)
 
 			// Extract captured values from the callback for validation
 			capturedSenderID = tt.callback.User.ID
@@ -432,7 +440,7 @@ func TestHandleViewSubmission_PostKudosError(t *testing.T) {
 
 	w := httptest.NewRecorder()
 
-	HandleViewSubmission(w, callback, cfg)
+	HandleViewSubmission(context.Background(), w, callback, cfg)
 
 	// Should still return 200 OK even with error
 	if w.Code != http.StatusOK {
@@ -440,6 +448,261 @@ func TestHandleViewSubmission_PostKudosError(t *testing.T) {
 	}
 }
 
+func TestHandleViewSubmission_Duplicate(t *testing.T) {
+	callback := &slack.InteractionCallback{
+		User: slack.User{ID: "U123456"},
+		View: slack.View{
+			ID:   "V123456",
+			Hash: "hash-1",
+			State: &slack.ViewState{
+				Values: map[string]map[string]slack.BlockAction{
+					"kudo_users": {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+					"kudo_type": {"kudo_type": {SelectedOption: slack.OptionBlockObject{
+						Value: "atitude-positiva",
+						Text:  &slack.TextBlockObject{Text: ":star2: Atitude Positiva"},
+					}}},
+					"kudo_message": {"kudo_message": {Value: "Mensagem teste"}},
+				},
+			},
+		},
+	}
+
+	posts := 0
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				posts++
+				return channelID, "1234567890.123456", nil
+			},
+		},
+		Store: storage.NewMemoryStore(),
+	}
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		HandleViewSubmission(context.Background(), w, callback, cfg)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	}
+
+	if posts != 1 {
+		t.Errorf("posted %d messages, want 1", posts)
+	}
+}
+
+func TestHandleViewSubmission_Moderation(t *testing.T) {
+	submission := func(typeValue, typeName, message string) *slack.InteractionCallback {
+		return &slack.InteractionCallback{
+			User: slack.User{ID: "U123456"},
+			View: slack.View{
+				State: &slack.ViewState{
+					Values: map[string]map[string]slack.BlockAction{
+						"kudo_users": {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+						"kudo_type": {"kudo_type": {SelectedOption: slack.OptionBlockObject{
+							Value: typeValue,
+							Text:  &slack.TextBlockObject{Text: ":star2: Atitude Positiva"},
+						}}},
+						"kudo_description": {"kudo_description": {Value: typeName}},
+						"kudo_message":     {"kudo_message": {Value: message}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		callback   *slack.InteractionCallback
+		wantErrors map[string]string
+	}{
+		{
+			name:     "allowed message",
+			callback: submission("atitude-positiva", "", "Valeu pela energia de sempre!"),
+		},
+		{
+			name:       "blocked word in the message",
+			callback:   submission("atitude-positiva", "", "Valeu, seu lindo!"),
+			wantErrors: map[string]string{"kudo_message": `A mensagem contém um termo não permitido: "lindo"`},
+		},
+		{
+			name:       "message too short",
+			callback:   submission("atitude-positiva", "", "Valeu!"),
+			wantErrors: map[string]string{"kudo_message": "A mensagem precisa ter pelo menos 10 caracteres"},
+		},
+		{
+			name:     "blocked word in the custom type name",
+			callback: submission("custom", "Lindo do Mês", "Valeu pela energia de sempre!"),
+			wantErrors: map[string]string{
+				"kudo_description": `O nome do tipo de elogio contém um termo não permitido: "lindo"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			store.SaveModerationRules(context.Background(), &models.ModerationRules{MinLength: 10, BlockedWords: []string{"lindo"}})
+
+			posted := false
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						posted = true
+						return channelID, "1234567890.123456", nil
+					},
+				},
+				Store: store,
+			}
+
+			w := httptest.NewRecorder()
+			HandleViewSubmission(context.Background(), w, tt.callback, cfg)
+
+			if posted != (tt.wantErrors == nil) {
+				t.Errorf("posted = %v, want %v", posted, tt.wantErrors == nil)
+			}
+
+			wantAction := models.AuditKudoSubmitted
+			if tt.wantErrors != nil {
+				wantAction = models.AuditKudoRejected
+			}
+			entries, _ := store.ListAuditEntries(context.Background(), storage.AuditQuery{})
+			if len(entries) != 1 || entries[0].Action != wantAction || entries[0].ActorID != "U123456" {
+				t.Fatalf("audit entries = %+v, want one %s by the sender", entries, wantAction)
+			}
+			if tt.wantErrors == nil {
+				return
+			}
+			if entries[0].After["errors"] == nil {
+				t.Errorf("audit entry = %+v, want the moderation errors", entries[0])
+			}
+
+			var response struct {
+				ResponseAction string            `json:"response_action"`
+				Errors         map[string]string `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("error decoding response %q: %v", w.Body.String(), err)
+			}
+			if response.ResponseAction != "errors" || !maps.Equal(response.Errors, tt.wantErrors) {
+				t.Errorf("response = %+v, want errors %v", response, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestHandleViewSubmission_TeamKudoTypes(t *testing.T) {
+	submission := func(typeValue, typeName, emoji, message string) *slack.InteractionCallback {
+		return &slack.InteractionCallback{
+			Team: slack.Team{ID: "T123456"},
+			User: slack.User{ID: "U123456"},
+			View: slack.View{
+				ID: "V123456",
+				State: &slack.ViewState{
+					Values: map[string]map[string]slack.BlockAction{
+						"kudo_users": {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+						"kudo_type": {"kudo_type": {SelectedOption: slack.OptionBlockObject{
+							Value: typeValue,
+							Text:  &slack.TextBlockObject{Text: "🎸 Texto truncado…"},
+						}}},
+						"kudo_description": {"kudo_description": {Value: typeName}},
+						"kudo_emoji":       {"kudo_emoji": {SelectedOption: slack.OptionBlockObject{Value: emoji}}},
+						"kudo_message":     {"kudo_message": {Value: message}},
+					},
+				},
+			},
+		}
+	}
+	savedID := services.CustomKudoTypeID("T123456", "Rockstar do Deploy")
+
+	tests := []struct {
+		name       string
+		callback   *slack.InteractionCallback
+		wantErrors map[string]string
+		wantEmoji  string
+		wantText   string
+	}{
+		{
+			name:      "custom type with picked emoji",
+			callback:  submission("custom", "Rockstar do Deploy", ":guitar:", "Mandou bem!"),
+			wantEmoji: ":guitar:",
+			wantText:  "Rockstar do Deploy",
+		},
+		{
+			name:      "custom type without emoji",
+			callback:  submission("custom", "Rockstar do Deploy", "", "Mandou bem!"),
+			wantEmoji: "✏️",
+			wantText:  "Rockstar do Deploy",
+		},
+		{
+			name:      "saved team type",
+			callback:  submission(services.SavedKudoTypePrefix+savedID, "", "", "Mandou bem!"),
+			wantEmoji: ":rocket:",
+			wantText:  "Rockstar do Deploy",
+		},
+		{
+			name:       "saved team type without message",
+			callback:   submission(services.SavedKudoTypePrefix+savedID, "", "", ""),
+			wantErrors: map[string]string{"kudo_message": "A mensagem é obrigatória para elogios personalizados"},
+		},
+		{
+			name:       "saved team type no longer available",
+			callback:   submission(services.SavedKudoTypePrefix+"T123456_removido", "", "", "Mandou bem!"),
+			wantErrors: map[string]string{"kudo_type": "Este tipo de elogio não está mais disponível. Escolha outro tipo."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			created := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+			store := storage.NewMemoryStore()
+			store.SaveCustomKudoType(ctx, &models.CustomKudoType{
+				ID: savedID, TeamID: "T123456", Emoji: ":rocket:", Name: "Rockstar do Deploy",
+				CreatedBy: "U000001", CreatedAt: created, LastUsedAt: created,
+			})
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI:       &MockSlackClient{},
+				Store:          store,
+			}
+
+			w := httptest.NewRecorder()
+			HandleViewSubmission(ctx, w, tt.callback, cfg)
+
+			kudos, _ := store.ListKudos(ctx, storage.KudoQuery{})
+			if tt.wantErrors != nil {
+				var response struct {
+					Errors map[string]string `json:"errors"`
+				}
+				json.Unmarshal(w.Body.Bytes(), &response)
+				if len(kudos) != 0 || !maps.Equal(response.Errors, tt.wantErrors) {
+					t.Errorf("kudos = %d, errors = %v, want no kudos and errors %v", len(kudos), response.Errors, tt.wantErrors)
+				}
+				return
+			}
+
+			if len(kudos) != 1 {
+				t.Fatalf("kudos = %d, want 1 (response %s)", len(kudos), w.Body.String())
+			}
+			if kudos[0].TypeID != "custom" || kudos[0].TypeEmoji != tt.wantEmoji || kudos[0].TypeText != tt.wantText {
+				t.Errorf("kudo type = %s %s %s, want custom %s %s", kudos[0].TypeID, kudos[0].TypeEmoji, kudos[0].TypeText, tt.wantEmoji, tt.wantText)
+			}
+
+			// The type is offered to the team again, with the latest emoji
+			types, _ := store.ListCustomKudoTypes(ctx, "T123456")
+			if len(types) != 1 || types[0].ID != savedID || types[0].Emoji != tt.wantEmoji || types[0].CreatedBy != "U000001" {
+				t.Errorf("team types = %+v, want %s with emoji %s", types, savedID, tt.wantEmoji)
+			}
+			if !types[0].LastUsedAt.After(created) {
+				t.Errorf("last used = %v, want the time of the kudo", types[0].LastUsedAt)
+			}
+		})
+	}
+}
+
 func TestHandleViewSubmission_SuggestedMessageForAllTypes(t *testing.T) {
 	// Test that suggested messages work for all known kudo types
 	kudoTypes := []struct {
@@ -507,7 +770,7 @@ func TestHandleViewSubmission_SuggestedMessageForAllTypes(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
-			HandleViewSubmission(w, callback, cfg)
+			HandleViewSubmission(context.Background(), w, callback, cfg)
 
 			if !called {
 				t.Error("expected PostKudos to be called")
@@ -573,7 +836,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if !called {
 			t.Error("expected PostKudos to be called")
@@ -636,7 +899,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if called {
 			t.Error("expected PostKudos NOT to be called with invalid data")
@@ -705,7 +968,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if called {
 			t.Error("expected PostKudos NOT to be called with invalid data")
@@ -775,7 +1038,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if called {
 			t.Error("expected PostKudos NOT to be called with invalid data")
@@ -839,7 +1102,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if called {
 			t.Error("expected PostKudos NOT to be called with invalid data")
@@ -903,7 +1166,7 @@ func TestHandleViewSubmission_CustomKudoType(t *testing.T) {
 		}
 
 		w := httptest.NewRecorder()
-		HandleViewSubmission(w, callback, cfg)
+		HandleViewSubmission(context.Background(), w, callback, cfg)
 
 		if called {
 			t.Error("expected PostKudos NOT to be called with whitespace-only description")
@@ -922,30 +1185,36 @@ type MockSlackClient struct {
 	InviteUsersToConversationFunc func(channelID string, users ...string) (*slack.Channel, error)
 	GetUsersInConversationFunc    func(params *slack.GetUsersInConversationParameters) ([]string, string, error)
 	GetUserInfoFunc               func(user string) (*slack.User, error)
+	UploadFileV2Func              func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	OpenConversationFunc          func(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	GetUserProfileFunc            func(params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
+	UpdateMessageFunc             func(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetUserGroupMembersFunc       func(userGroup string) ([]string, error)
+	GetEmojiFunc                  func() (map[string]string, error)
 }
 
-func (m *MockSlackClient) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+func (m *MockSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
 	if m.PostMessageFunc != nil {
 		return m.PostMessageFunc(channelID, options...)
 	}
 	return "C123456", "1234567890.123456", nil
 }
 
-func (m *MockSlackClient) InviteUsersToConversation(channelID string, users ...string) (*slack.Channel, error) {
+func (m *MockSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
 	if m.InviteUsersToConversationFunc != nil {
 		return m.InviteUsersToConversationFunc(channelID, users...)
 	}
 	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}}}, nil
 }
 
-func (m *MockSlackClient) GetUsersInConversation(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+func (m *MockSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
 	if m.GetUsersInConversationFunc != nil {
 		return m.GetUsersInConversationFunc(params)
 	}
 	return []string{"U123456", "U789012"}, "", nil
 }
 
-func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
+func (m *MockSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	if m.GetUserInfoFunc != nil {
 		return m.GetUserInfoFunc(user)
 	}
@@ -955,3 +1224,417 @@ func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
 		Deleted: false,
 	}, nil
 }
+
+func (m *MockSlackClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	if m.UploadFileV2Func != nil {
+		return m.UploadFileV2Func(params)
+	}
+	return &slack.FileSummary{ID: "F123456", Title: params.Title}, nil
+}
+
+func (m *MockSlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	if m.OpenConversationFunc != nil {
+		return m.OpenConversationFunc(params)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D123456"}}}, false, false, nil
+}
+
+func (m *MockSlackClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	if m.GetUserProfileFunc != nil {
+		return m.GetUserProfileFunc(params)
+	}
+	return &slack.UserProfile{}, nil
+}
+
+func (m *MockSlackClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	if m.UpdateMessageFunc != nil {
+		return m.UpdateMessageFunc(channelID, timestamp, options...)
+	}
+	return channelID, timestamp, "", nil
+}
+
+func (m *MockSlackClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	if m.GetUserGroupMembersFunc != nil {
+		return m.GetUserGroupMembersFunc(userGroup)
+	}
+	return []string{}, nil
+}
+
+func (m *MockSlackClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	if m.GetEmojiFunc != nil {
+		return m.GetEmojiFunc()
+	}
+	return map[string]string{}, nil
+}
+
+func TestHandleViewSubmission_Points(t *testing.T) {
+	newCallback := func(points string, recipients []string) *slack.InteractionCallback {
+		return &slack.InteractionCallback{
+			User: slack.User{ID: "U123456"},
+			View: slack.View{
+				State: &slack.ViewState{
+					Values: map[string]map[string]slack.BlockAction{
+						"kudo_users": {
+							"kudo_users": {SelectedUsers: recipients},
+						},
+						"kudo_type": {
+							"kudo_type": {
+								SelectedOption: slack.OptionBlockObject{
+									Value: "acima-e-alem",
+									Text:  &slack.TextBlockObject{Text: ":rocket: Acima e Além"},
+								},
+							},
+						},
+						"kudo_message": {
+							"kudo_message": {Value: "Valeu!"},
+						},
+						"kudo_points": {
+							"kudo_points": {Value: points},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		monthlyPoints  int
+		alreadySpent   int
+		points         string
+		recipients     []string
+		expectPosted   bool
+		expectedPoints int
+		expectedError  string
+	}{
+		{
+			name:           "within balance",
+			monthlyPoints:  100,
+			points:         "20",
+			recipients:     []string{"U789012"},
+			expectPosted:   true,
+			expectedPoints: 20,
+		},
+		{
+			name:           "empty points field",
+			monthlyPoints:  100,
+			points:         "",
+			recipients:     []string{"U789012"},
+			expectPosted:   true,
+			expectedPoints: 0,
+		},
+		{
+			name:          "exceeds balance with multiple recipients",
+			monthlyPoints: 100,
+			alreadySpent:  50,
+			points:        "30",
+			recipients:    []string{"U789012", "U345678"},
+			expectPosted:  false,
+			expectedError: "Saldo insuficiente",
+		},
+		{
+			name:          "points that would overflow the total",
+			monthlyPoints: 100,
+			points:        "4611686018427387904",
+			recipients:    []string{"U789012", "U345678"},
+			expectPosted:  false,
+			expectedError: "no máximo 100 pontos",
+		},
+		{
+			name:          "negative points",
+			monthlyPoints: 100,
+			points:        "-5",
+			recipients:    []string{"U789012"},
+			expectPosted:  false,
+			expectedError: "número inteiro",
+		},
+		{
+			name:           "points ignored when disabled",
+			monthlyPoints:  0,
+			points:         "500",
+			recipients:     []string{"U789012"},
+			expectPosted:   true,
+			expectedPoints: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			if tt.alreadySpent > 0 {
+				store.SaveKudo(context.Background(), &models.Kudo{
+					SenderID:     "U123456",
+					RecipientIDs: []string{"U999999"},
+					Points:       tt.alreadySpent,
+					CreatedAt:    time.Now(),
+				})
+			}
+
+			posted := false
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						posted = true
+						return "C123456", "1234567890.123456", nil
+					},
+				},
+				MonthlyPoints: tt.monthlyPoints,
+				Location:      time.UTC,
+				Store:         store,
+			}
+
+			w := httptest.NewRecorder()
+			HandleViewSubmission(context.Background(), w, newCallback(tt.points, tt.recipients), cfg)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if posted != tt.expectPosted {
+				t.Fatalf("posted = %v, want %v", posted, tt.expectPosted)
+			}
+
+			if tt.expectedError != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, "kudo_points") || !strings.Contains(body, tt.expectedError) {
+					t.Errorf("expected kudo_points error containing %q, got %s", tt.expectedError, body)
+				}
+				return
+			}
+
+			kudos, _ := store.ListKudos(context.Background(), storage.KudoQuery{SenderID: "U123456", RecipientID: tt.recipients[0]})
+			if len(kudos) != 1 {
+				t.Fatalf("expected 1 recorded kudo, got %d", len(kudos))
+			}
+			if kudos[0].Points != tt.expectedPoints {
+				t.Errorf("recorded points = %d, want %d", kudos[0].Points, tt.expectedPoints)
+			}
+		})
+	}
+}
+
+// staleBalanceStore hides the kudos from the balance check, as if another submission spent the points after it
+type staleBalanceStore struct {
+	*storage.MemoryStore
+}
+
+func (s staleBalanceStore) ListKudos(ctx context.Context, query storage.KudoQuery) ([]*models.Kudo, error) {
+	return nil, nil
+}
+
+func TestHandleViewSubmission_PointsSpentConcurrently(t *testing.T) {
+	store := storage.NewMemoryStore()
+	store.SaveKudo(context.Background(), &models.Kudo{SenderID: "U123456", RecipientIDs: []string{"U999999"}, Points: 90, CreatedAt: time.Now()})
+
+	posted := false
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				posted = true
+				return "C123456", "1234567890.123456", nil
+			},
+		},
+		MonthlyPoints: 100,
+		Location:      time.UTC,
+		Store:         staleBalanceStore{store},
+	}
+	callback := &slack.InteractionCallback{
+		User: slack.User{ID: "U123456"},
+		View: slack.View{
+			State: &slack.ViewState{
+				Values: map[string]map[string]slack.BlockAction{
+					"kudo_users": {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+					"kudo_type": {"kudo_type": {SelectedOption: slack.OptionBlockObject{
+						Value: "acima-e-alem",
+						Text:  &slack.TextBlockObject{Text: ":rocket: Acima e Além"},
+					}}},
+					"kudo_message": {"kudo_message": {Value: "Valeu!"}},
+					"kudo_points":  {"kudo_points": {Value: "20"}},
+				},
+			},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	HandleViewSubmission(context.Background(), w, callback, cfg)
+
+	if posted {
+		t.Error("kudos should not be posted once the balance is spent")
+	}
+	if body := w.Body.String(); !strings.Contains(body, "kudo_points") || !strings.Contains(body, "Saldo insuficiente") {
+		t.Errorf("expected kudo_points error, got %s", body)
+	}
+}
+
+func TestHandleViewSubmission_ResolvedKudoTypes(t *testing.T) {
+	rockID := services.CustomKudoTypeID("T123456", "Rockstar do Deploy")
+	submission := func(typeValue, typeName string) *slack.InteractionCallback {
+		return &slack.InteractionCallback{
+			Team: slack.Team{ID: "T123456"},
+			User: slack.User{ID: "U123456"},
+			View: slack.View{
+				ID: "V123456",
+				State: &slack.ViewState{
+					Values: map[string]map[string]slack.BlockAction{
+						"kudo_users":       {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+						"kudo_type":        {"kudo_type": {SelectedOption: slack.OptionBlockObject{Value: typeValue, Text: &slack.TextBlockObject{Text: "🎸 Rockstar do Deploy"}}}},
+						"kudo_description": {"kudo_description": {Value: typeName}},
+						"kudo_message":     {"kudo_message": {Value: "Mandou bem!"}},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		callback *slack.InteractionCallback
+		status   string
+		resolved string
+		wantType string
+		wantText string
+	}{
+		{
+			name:     "typed name merged into a built-in type",
+			callback: submission("custom", "rockstar do deploy"),
+			status:   models.CustomKudoTypeMerged,
+			resolved: "entrega-excepcional",
+			wantType: "entrega-excepcional",
+			wantText: "Entrega Excepcional",
+		},
+		{
+			name:     "saved type promoted",
+			callback: submission(services.SavedKudoTypePrefix+rockID, ""),
+			status:   models.CustomKudoTypePromoted,
+			resolved: "rockstar-do-deploy",
+			wantType: "rockstar-do-deploy",
+			wantText: "Rockstar do Deploy",
+		},
+		{
+			name:     "saved type dismissed stays custom",
+			callback: submission(services.SavedKudoTypePrefix+rockID, ""),
+			status:   models.CustomKudoTypeDismissed,
+			wantType: "custom",
+			wantText: "Rockstar do Deploy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			store.SaveCustomKudoType(ctx, &models.CustomKudoType{
+				ID: rockID, TeamID: "T123456", Emoji: ":guitar:", Name: "Rockstar do Deploy",
+				UsageCount: 5, Status: tt.status, ResolvedTypeID: tt.resolved,
+			})
+			store.SaveKudoType(ctx, &models.KudoType{ID: "rockstar-do-deploy", Emoji: ":guitar:", Name: "Rockstar do Deploy"})
+			cfg := &config.Config{SlackChannelID: "C123456", SlackAPI: &MockSlackClient{}, Store: store}
+
+			w := httptest.NewRecorder()
+			HandleViewSubmission(ctx, w, tt.callback, cfg)
+
+			kudos, _ := store.ListKudos(ctx, storage.KudoQuery{})
+			if len(kudos) != 1 {
+				t.Fatalf("kudos = %d, want 1 (response %s)", len(kudos), w.Body.String())
+			}
+			if kudos[0].TypeID != tt.wantType || kudos[0].TypeText != tt.wantText {
+				t.Errorf("kudo type = %s %s, want %s %s", kudos[0].TypeID, kudos[0].TypeText, tt.wantType, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestHandleViewSubmission_SuggestsPromotion(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	var askedAdmins []string
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				if channelID != "C123456" {
+					askedAdmins = append(askedAdmins, channelID)
+				}
+				return channelID, "1234567890.123456", nil
+			},
+		},
+		Store:        store,
+		AdminUserIDs: []string{"UADMIN1", "UADMIN2"},
+		PromoteAfter: 2,
+	}
+
+	for i := range 3 {
+		callback := &slack.InteractionCallback{
+			Team: slack.Team{ID: "T123456"},
+			User: slack.User{ID: "U123456"},
+			View: slack.View{
+				ID:   fmt.Sprintf("V%d", i),
+				Hash: "hash",
+				State: &slack.ViewState{
+					Values: map[string]map[string]slack.BlockAction{
+						"kudo_users":       {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+						"kudo_type":        {"kudo_type": {SelectedOption: slack.OptionBlockObject{Value: "custom", Text: &slack.TextBlockObject{Text: "✏️ Outro..."}}}},
+						"kudo_description": {"kudo_description": {Value: "Rockstar do Deploy"}},
+						"kudo_message":     {"kudo_message": {Value: fmt.Sprintf("Mandou bem %d!", i)}},
+					},
+				},
+			},
+		}
+		HandleViewSubmission(ctx, httptest.NewRecorder(), callback, cfg)
+	}
+
+	// Asked once, when the second kudo crossed the threshold
+	if !slices.Equal(askedAdmins, []string{"UADMIN1", "UADMIN2"}) {
+		t.Errorf("asked admins %v, want each admin once", askedAdmins)
+	}
+	kudoType, _ := store.GetCustomKudoType(ctx, services.CustomKudoTypeID("T123456", "Rockstar do Deploy"))
+	if kudoType.UsageCount != 3 || kudoType.Status != models.CustomKudoTypeSuggested {
+		t.Errorf("custom type = %+v, want 3 uses and suggested", kudoType)
+	}
+}
+
+func TestHandleViewSubmission_SettingsChannel(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	store.SaveSettings(ctx, &models.Settings{KudosChannelID: "C0KUDOS"})
+
+	var postedChannel, invitedChannel string
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				postedChannel = channelID
+				return channelID, "1234567890.123456", nil
+			},
+			InviteUsersToConversationFunc: func(channelID string, users ...string) (*slack.Channel, error) {
+				invitedChannel = channelID
+				return &slack.Channel{}, nil
+			},
+		},
+		Store: store,
+	}
+	callback := &slack.InteractionCallback{
+		User: slack.User{ID: "U123456"},
+		View: slack.View{
+			ID:   "V123456",
+			Hash: "hash",
+			State: &slack.ViewState{
+				Values: map[string]map[string]slack.BlockAction{
+					"kudo_users":   {"kudo_users": {SelectedUsers: []string{"U789012"}}},
+					"kudo_type":    {"kudo_type": {SelectedOption: slack.OptionBlockObject{Value: "resiliencia", Text: &slack.TextBlockObject{Text: ":muscle: Resiliência"}}}},
+					"kudo_message": {"kudo_message": {Value: "Não desistiu!"}},
+				},
+			},
+		},
+	}
+
+	HandleViewSubmission(ctx, httptest.NewRecorder(), callback, cfg)
+
+	if postedChannel != "C0KUDOS" || invitedChannel != "C0KUDOS" {
+		t.Errorf("posted to %q and invited to %q, want the channel saved by admins", postedChannel, invitedChannel)
+	}
+	if cfg.SlackChannelID != "C123456" {
+		t.Errorf("SlackChannelID = %s, want the shared config untouched", cfg.SlackChannelID)
+	}
+}
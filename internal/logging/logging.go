@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Cloud Logging special fields
+// See https://cloud.google.com/logging/docs/structured-logging#special-payload-fields
+const (
+	TraceKey        = "logging.googleapis.com/trace"
+	SpanIDKey       = "logging.googleapis.com/spanId"
+	TraceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// Redacted replaces sensitive values in log records
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are attributes whose values are never logged
+// Message bodies are included so kudos content doesn't end up in the logs
+var sensitiveKeys = map[string]bool{
+	"token":          true,
+	"bot_token":      true,
+	"signing_secret": true,
+	"authorization":  true,
+	"payload":        true,
+	"text":           true,
+	"message":        true,
+	"body":           true,
+	"blocks":         true,
+	"attachments":    true,
+}
+
+// secretPattern matches Slack tokens (xoxb-, xoxp-, ...) and signing signatures
+var secretPattern = regexp.MustCompile(`xox[abeoprs]-[A-Za-z0-9-]+|v0=[a-f0-9]{64}`)
+
+// New creates a logger emitting Cloud Logging compatible JSON
+// Debug records are only written when debug is true
+func New(w io.Writer, debug bool) *slog.Logger {
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceAttr,
+	})
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// Setup installs the logger as the default for slog and the log package
+func Setup(w io.Writer, debug bool) {
+	slog.SetDefault(New(w, debug))
+}
+
+// RedactString removes Slack tokens and signatures from a string
+func RedactString(s string) string {
+	return secretPattern.ReplaceAllString(s, Redacted)
+}
+
+// replaceAttr renames the built-in fields to the names Cloud Logging expects and redacts secrets
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.LevelKey:
+			return slog.String("severity", severity(a.Value.Any().(slog.Level)))
+		case slog.MessageKey:
+			return slog.String("message", RedactString(a.Value.String()))
+		}
+	}
+
+	if sensitiveKeys[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// severity maps slog levels to Cloud Logging severities
+func severity(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARNING"
+	default:
+		return "ERROR"
+	}
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx whose attributes are added to every record logged with it
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, attrsKey{}, append(slices.Clip(existing), attrs...))
+}
+
+// WithSpan adds the Cloud Logging trace fields of the span in ctx, linking the logs to the trace
+func WithSpan(ctx context.Context, projectID string) context.Context {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() || projectID == "" {
+		return ctx
+	}
+
+	attrs := []slog.Attr{
+		slog.String(TraceKey, "projects/"+projectID+"/traces/"+spanContext.TraceID().String()),
+		slog.String(SpanIDKey, spanContext.SpanID().String()),
+	}
+	if spanContext.IsSampled() {
+		attrs = append(attrs, slog.Bool(TraceSampledKey, true))
+	}
+	return WithAttrs(ctx, attrs...)
+}
+
+// contextHandler adds the attributes stored in the context to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// SlackLogger routes the Slack client debug output through slog at debug level
+type SlackLogger struct{}
+
+// Output implements the logger interface expected by slack.OptionLog
+func (SlackLogger) Output(calldepth int, s string) error {
+	slog.Debug("slack api", "detail", redactSlackDebug(strings.TrimSpace(s)))
+	return nil
+}
+
+// responseDebugFields are the fields kept from Slack API response bodies, the rest echoes messages and profiles
+var responseDebugFields = []string{"ok", "error", "warning"}
+
+// redactSlackDebug removes message bodies from the request and response dumps of the Slack client
+func redactSlackDebug(s string) string {
+	if strings.HasPrefix(s, "HTTP/") {
+		head, body, _ := strings.Cut(s, "\r\n\r\n")
+		return RedactString(head) + "\r\n\r\n" + redactResponseBody(body)
+	}
+
+	const prefix = "Sending request: "
+	if form, ok := strings.CutPrefix(s, prefix); ok {
+		if values, err := url.ParseQuery(form); err == nil {
+			for key := range values {
+				if sensitiveKeys[strings.ToLower(key)] {
+					values.Set(key, Redacted)
+				}
+			}
+			return prefix + RedactString(values.Encode())
+		}
+	}
+	return RedactString(s)
+}
+
+// redactResponseBody keeps only the status fields of a dumped JSON response body
+// Chunked bodies are dumped with their chunk sizes, so the JSON object is looked up between the outer braces
+func redactResponseBody(body string) string {
+	start, end := strings.Index(body, "{"), strings.LastIndex(body, "}")
+	if start < 0 || end < start {
+		if strings.TrimSpace(body) == "" {
+			return ""
+		}
+		return Redacted
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body[start:end+1]), &fields); err != nil {
+		return Redacted
+	}
+	kept := make(map[string]json.RawMessage)
+	for _, key := range responseDebugFields {
+		if value, ok := fields[key]; ok {
+			kept[key] = value
+		}
+	}
+	redacted, err := json.Marshal(kept)
+	if err != nil {
+		return Redacted
+	}
+	return RedactString(string(redacted))
+}
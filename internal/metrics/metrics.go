@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Supported values of METRICS_EXPORTER
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultServiceName is reported when K_SERVICE is not set
+const DefaultServiceName = "my-matter"
+
+// Outcomes recorded on counters and histograms
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Results of a kudos modal submission
+const (
+	SubmissionPosted    = "posted"
+	SubmissionInvalid   = "invalid"
+	SubmissionFailed    = "failed"
+	SubmissionDuplicate = "duplicate"
+)
+
+// Instruments are created from the global meter provider and start recording once Setup installs one
+var (
+	meter = otel.Meter("github.com/vyper/my-matter")
+
+	kudosPosted        = must(meter.Int64Counter("kudos.posted", metric.WithDescription("Kudos posted to the channel"), metric.WithUnit("{kudo}")))
+	modalOpens         = must(meter.Int64Counter("kudos.modal.opens", metric.WithDescription("Kudos modal views.open calls"), metric.WithUnit("{call}")))
+	modalUpdates       = must(meter.Int64Counter("kudos.modal.updates", metric.WithDescription("Kudos modal views.update calls"), metric.WithUnit("{call}")))
+	modalSubmissions   = must(meter.Int64Counter("kudos.modal.submissions", metric.WithDescription("Kudos modal submissions"), metric.WithUnit("{submission}")))
+	validationErrors   = must(meter.Int64Counter("kudos.modal.validation_errors", metric.WithDescription("Modal fields rejected on submission"), metric.WithUnit("{error}")))
+	reminderDMs        = must(meter.Int64Counter("kudos.reminder.dms", metric.WithDescription("Reminder DMs sent"), metric.WithUnit("{message}")))
+	slackCallDuration  = must(meter.Float64Histogram("slack.client.duration", metric.WithDescription("Duration of Slack API calls"), metric.WithUnit("s")))
+	httpClientDuration = must(meter.Float64Histogram("http.client.request.duration", metric.WithDescription("Duration of HTTP requests to Slack"), metric.WithUnit("s")))
+)
+
+func must[T any](instrument T, err error) T {
+	if err != nil {
+		otel.Handle(err)
+	}
+	return instrument
+}
+
+// Setup installs the meter provider selected by METRICS_EXPORTER
+// The OTLP exporter is configured with the standard OTEL_EXPORTER_OTLP_* variables
+func Setup(ctx context.Context, getenv func(string) string) error {
+	var exporter sdkmetric.Exporter
+	var err error
+
+	switch name := getenv("METRICS_EXPORTER"); name {
+	case "", ExporterNone:
+		return nil
+	case ExporterStdout:
+		exporter, err = stdoutmetric.New()
+	case ExporterOTLP:
+		exporter, err = otlpmetrichttp.New(ctx)
+	default:
+		return fmt.Errorf("unknown METRICS_EXPORTER %q", name)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating metrics exporter: %w", err)
+	}
+
+	serviceName := getenv("K_SERVICE")
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetMeterProvider(provider)
+	flusher = provider
+	return nil
+}
+
+// flusher is the installed provider, nil when metrics are disabled
+var flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Flush exports pending metrics, used before event-driven functions return
+func Flush(ctx context.Context) error {
+	if flusher == nil {
+		return nil
+	}
+	return flusher.ForceFlush(ctx)
+}
+
+// outcome maps an error to the outcome attribute
+func outcome(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("outcome", OutcomeError)
+	}
+	return attribute.String("outcome", OutcomeOK)
+}
+
+// KudoPosted counts a kudo posted to the channel
+func KudoPosted(ctx context.Context, kudoType string) {
+	kudosPosted.Add(ctx, 1, metric.WithAttributes(attribute.String("kudo_type", kudoType)))
+}
+
+// ModalOpened counts a views.open call
+func ModalOpened(ctx context.Context, err error) {
+	modalOpens.Add(ctx, 1, metric.WithAttributes(outcome(err)))
+}
+
+// ModalUpdated counts a views.update call
+func ModalUpdated(ctx context.Context, err error) {
+	modalUpdates.Add(ctx, 1, metric.WithAttributes(outcome(err)))
+}
+
+// ModalSubmitted counts a modal submission by result
+func ModalSubmitted(ctx context.Context, result string) {
+	modalSubmissions.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// ValidationError counts a field rejected on submission
+func ValidationError(ctx context.Context, field string) {
+	validationErrors.Add(ctx, 1, metric.WithAttributes(attribute.String("field", field)))
+}
+
+// ReminderSent counts a reminder DM, failed ones included
+func ReminderSent(ctx context.Context, err error) {
+	reminderDMs.Add(ctx, 1, metric.WithAttributes(outcome(err)))
+}
+
+// SlackCall records the latency of a Slack API call
+func SlackCall(ctx context.Context, method string, start time.Time, err error) {
+	slackCallDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("slack.method", method),
+		outcome(err),
+	))
+}
+
+// HTTPCall records the latency of an HTTP request
+// statusCode is 0 when the request failed before a response
+func HTTPCall(ctx context.Context, method, path string, statusCode int, start time.Time) {
+	httpClientDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.path", path),
+		attribute.Int("http.response.status_code", statusCode),
+	))
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var reader *sdkmetric.ManualReader
+
+func TestMain(m *testing.M) {
+	// The instruments delegate to the first provider installed globally
+	reader = sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	os.Exit(m.Run())
+}
+
+// collect returns the data of the named metric
+func collect(t *testing.T, name string) metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return nil
+}
+
+// counterValue returns the sum recorded with the given attribute
+func counterValue(t *testing.T, name string, attr attribute.KeyValue) int64 {
+	t.Helper()
+	sum, ok := collect(t, name).(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("metric %s is not an int64 sum", name)
+	}
+	for _, point := range sum.DataPoints {
+		if value, ok := point.Attributes.Value(attr.Key); ok && value == attr.Value {
+			return point.Value
+		}
+	}
+	return 0
+}
+
+func TestCounters(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name   string
+		record func()
+		metric string
+		attr   attribute.KeyValue
+	}{
+		{name: "kudo posted", record: func() { KudoPosted(ctx, "ideia-brilhante") }, metric: "kudos.posted", attr: attribute.String("kudo_type", "ideia-brilhante")},
+		{name: "modal opened", record: func() { ModalOpened(ctx, nil) }, metric: "kudos.modal.opens", attr: attribute.String("outcome", OutcomeOK)},
+		{name: "modal open failed", record: func() { ModalOpened(ctx, failure) }, metric: "kudos.modal.opens", attr: attribute.String("outcome", OutcomeError)},
+		{name: "modal updated", record: func() { ModalUpdated(ctx, nil) }, metric: "kudos.modal.updates", attr: attribute.String("outcome", OutcomeOK)},
+		{name: "modal submitted", record: func() { ModalSubmitted(ctx, SubmissionInvalid) }, metric: "kudos.modal.submissions", attr: attribute.String("result", SubmissionInvalid)},
+		{name: "validation error", record: func() { ValidationError(ctx, "kudo_points") }, metric: "kudos.modal.validation_errors", attr: attribute.String("field", "kudo_points")},
+		{name: "reminder failed", record: func() { ReminderSent(ctx, failure) }, metric: "kudos.reminder.dms", attr: attribute.String("outcome", OutcomeError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.record()
+			before := counterValue(t, tt.metric, tt.attr)
+			tt.record()
+			if after := counterValue(t, tt.metric, tt.attr); after != before+1 {
+				t.Errorf("%s = %d, want %d", tt.metric, after, before+1)
+			}
+		})
+	}
+}
+
+func TestHistograms(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Add(-150 * time.Millisecond)
+
+	SlackCall(ctx, "chat.postMessage", start, nil)
+	HTTPCall(ctx, "POST", "/api/views.open", 200, start)
+
+	tests := []struct {
+		metric string
+		attr   attribute.KeyValue
+	}{
+		{metric: "slack.client.duration", attr: attribute.String("slack.method", "chat.postMessage")},
+		{metric: "http.client.request.duration", attr: attribute.String("url.path", "/api/views.open")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metric, func(t *testing.T) {
+			histogram, ok := collect(t, tt.metric).(metricdata.Histogram[float64])
+			if !ok {
+				t.Fatalf("metric %s is not a float64 histogram", tt.metric)
+			}
+			for _, point := range histogram.DataPoints {
+				if value, ok := point.Attributes.Value(tt.attr.Key); ok && value == tt.attr.Value {
+					if point.Count != 1 || point.Sum < 0.15 {
+						t.Errorf("%s count = %d sum = %f, want 1 sample of at least 0.15s", tt.metric, point.Count, point.Sum)
+					}
+					return
+				}
+			}
+			t.Errorf("%s has no data point with %s", tt.metric, tt.attr.Key)
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{name: "disabled by default", exporter: ""},
+		{name: "explicitly disabled", exporter: ExporterNone},
+		{name: "unknown exporter", exporter: "prometheus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				if key == "METRICS_EXPORTER" {
+					return tt.exporter
+				}
+				return ""
+			}
+
+			err := Setup(context.Background(), getenv)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := Flush(context.Background()); err != nil {
+				t.Errorf("Flush() unexpected error = %v", err)
+			}
+		})
+	}
+}
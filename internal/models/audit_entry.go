@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Actions recorded in the audit log
+// Kudos are immutable once posted, so there are no actions for editing or deleting them.
+// Only a kudo whose post failed is deleted, to refund the points reserved for it
+const (
+	AuditKudoSubmitted     = "kudo.submitted"      // kudo posted from the modal
+	AuditKudoRejected      = "kudo.rejected"       // submission blocked by the moderation rules
+	AuditKudoRefunded      = "kudo.refunded"       // kudo deleted after its post failed, refunding its points
+	AuditKudoTypeAdded     = "kudo_type.added"     // official type added by an admin
+	AuditKudoTypeRemoved   = "kudo_type.removed"   // official type removed by an admin
+	AuditKudoTypePromoted  = "kudo_type.promoted"  // custom type promoted to an official type
+	AuditKudoTypeMerged    = "kudo_type.merged"    // custom type merged into an official type
+	AuditKudoTypeDismissed = "kudo_type.dismissed" // custom type kept as it is
+	AuditSettingsUpdated   = "settings.updated"    // channels and reminders changed with /elogie admin
+	AuditModerationUpdated = "moderation.updated"  // moderation rules changed with /elogie admin
+	AuditLogExported       = "audit.exported"      // audit log exported with /elogie auditoria
+)
+
+// AuditActions lists every action of the audit log, as filtered with /elogie auditoria
+var AuditActions = []string{
+	AuditKudoSubmitted, AuditKudoRejected, AuditKudoRefunded,
+	AuditKudoTypeAdded, AuditKudoTypeRemoved, AuditKudoTypePromoted, AuditKudoTypeMerged, AuditKudoTypeDismissed,
+	AuditSettingsUpdated, AuditModerationUpdated, AuditLogExported,
+}
+
+// AuditEntry records who changed what, entries are never updated or deleted
+type AuditEntry struct {
+	ID       string `json:"id" firestore:"id"`
+	Action   string `json:"action" firestore:"action"`     // see AuditKudoSubmitted
+	ActorID  string `json:"actor_id" firestore:"actor_id"` // Slack user who did it
+	TargetID string `json:"target_id,omitempty" firestore:"target_id,omitempty"`
+	// Before and After are the JSON fields of the target, Before is empty when it's created
+	Before    map[string]interface{} `json:"before,omitempty" firestore:"before,omitempty"`
+	After     map[string]interface{} `json:"after,omitempty" firestore:"after,omitempty"`
+	RequestID string                 `json:"request_id,omitempty" firestore:"request_id,omitempty"` // Slack view or trigger ID
+	CreatedAt time.Time              `json:"created_at" firestore:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Review states of a custom kudo type, empty until admins are asked about it
+const (
+	CustomKudoTypeSuggested = "suggested" // admins were asked to promote it
+	CustomKudoTypePromoted  = "promoted"  // became an official type
+	CustomKudoTypeMerged    = "merged"    // posted as an existing type from now on
+	CustomKudoTypeDismissed = "dismissed" // stays a custom type, admins aren't asked again
+)
+
+// CustomKudoType is a kudo type created with "✏️ Outro...", offered again to everyone in the sender's Slack workspace
+type CustomKudoType struct {
+	ID         string    `json:"id" firestore:"id"`           // derived from the workspace and the normalized name
+	TeamID     string    `json:"team_id" firestore:"team_id"` // Slack workspace the type was created in
+	Emoji      string    `json:"emoji" firestore:"emoji"`     // e.g. :rocket: or ✏️
+	Name       string    `json:"name" firestore:"name"`
+	CreatedBy  string    `json:"created_by" firestore:"created_by"`
+	CreatedAt  time.Time `json:"created_at" firestore:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" firestore:"last_used_at"`
+	UsageCount int       `json:"usage_count" firestore:"usage_count"` // kudos posted with the type
+	// Status is the review state, see CustomKudoTypeSuggested
+	Status string `json:"status,omitempty" firestore:"status,omitempty"`
+	// ResolvedTypeID is the official type it was promoted to or merged into
+	ResolvedTypeID string    `json:"resolved_type_id,omitempty" firestore:"resolved_type_id,omitempty"`
+	ResolvedBy     string    `json:"resolved_by,omitempty" firestore:"resolved_by,omitempty"`
+	ResolvedAt     time.Time `json:"resolved_at,omitempty" firestore:"resolved_at,omitempty"`
+}
+
+// Resolved reports whether kudos of the type are posted as an official type
+func (t *CustomKudoType) Resolved() bool {
+	return (t.Status == CustomKudoTypePromoted || t.Status == CustomKudoTypeMerged) && t.ResolvedTypeID != ""
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Kudo represents a single kudos sent from one user to one or more recipients
+type Kudo struct {
+	ID           string   `json:"id" firestore:"id"`
+	SenderID     string   `json:"sender_id" firestore:"sender_id"`
+	RecipientIDs []string `json:"recipient_ids" firestore:"recipient_ids"`
+	TypeID       string   `json:"type_id" firestore:"type_id"`
+	TypeEmoji    string   `json:"type_emoji" firestore:"type_emoji"`
+	TypeText     string   `json:"type_text" firestore:"type_text"`
+	Message      string   `json:"message" firestore:"message"` // mrkdwn
+	// MessageRichText is the rich_text block JSON of the message as typed in the modal, empty for plain text
+	MessageRichText string    `json:"message_rich_text,omitempty" firestore:"message_rich_text,omitempty"`
+	Points          int       `json:"points" firestore:"points"`
+	ChannelID       string    `json:"channel_id" firestore:"channel_id"`
+	MessageTS       string    `json:"message_ts" firestore:"message_ts"`
+	CreatedAt       time.Time `json:"created_at" firestore:"created_at"`
+	// IdempotencyKey identifies the request that created the kudo, e.g. the modal view
+	IdempotencyKey string `json:"idempotency_key,omitempty" firestore:"idempotency_key,omitempty"`
+}
+
+// TotalPoints returns the points spent by the sender on this kudo.
+// Each recipient is awarded the full amount.
+func (k *Kudo) TotalPoints() int {
+	return k.Points * len(k.RecipientIDs)
+}
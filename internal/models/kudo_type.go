@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// KudoType is an official kudo type added by admins to the catalog, next to the built-in types
+type KudoType struct {
+	ID               string    `json:"id" firestore:"id"` // kudo_type value, e.g. rockstar-do-deploy
+	Emoji            string    `json:"emoji" firestore:"emoji"`
+	Name             string    `json:"name" firestore:"name"`
+	Description      string    `json:"description" firestore:"description"`
+	SuggestedMessage string    `json:"suggested_message" firestore:"suggested_message"`
+	Theme            KudoTheme `json:"theme" firestore:"theme"`
+	PromotedFrom     string    `json:"promoted_from,omitempty" firestore:"promoted_from,omitempty"` // custom type ID
+	CreatedBy        string    `json:"created_by" firestore:"created_by"`
+	CreatedAt        time.Time `json:"created_at" firestore:"created_at"`
+}
+
+// KudoTheme customizes how kudos of a type look in the channel
+// Every field is optional, empty fields keep the default look
+type KudoTheme struct {
+	Header   string `json:"header,omitempty" firestore:"header,omitempty"`       // replaces "🎉 Novo Elogio! 🎉"
+	ImageURL string `json:"image_url,omitempty" firestore:"image_url,omitempty"` // accent image shown below the header
+	Banner   string `json:"banner,omitempty" firestore:"banner,omitempty"`       // emoji line shown when there is no image
+	Footer   string `json:"footer,omitempty" firestore:"footer,omitempty"`       // replaces the rotating footer phrase
+}
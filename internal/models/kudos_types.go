@@ -27,3 +27,23 @@ var KudoDescriptions = map[string]string{
 	"conquista-do-time":       "Vitórias coletivas, marcos alcançados",
 	"resiliencia":             "Superar desafios, persistência, lidar com adversidades",
 }
+
+// KudoThemes maps built-in kudo type IDs to their visual theme, official types added by admins keep theirs
+var KudoThemes = map[string]KudoTheme{
+	"conquista-do-time": {
+		Header: "🏆 Conquista do Time! 🏆",
+		Banner: "🎊 🏆 🎉 🏆 🎊",
+		Footer: "Sucesso de todos nós!",
+	},
+	"acima-e-alem": {
+		Header: "🚀 Acima e Além! 🚀",
+		Banner: "🌟 🚀 🌟",
+	},
+	"entrega-excepcional": {
+		Header: "📦 Entrega Excepcional! 📦",
+	},
+	"resiliencia": {
+		Header: "💪 Resiliência! 💪",
+		Footer: "Nenhum desafio é grande demais para você!",
+	},
+}
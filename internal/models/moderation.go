@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ModerationRules are checked against kudo messages and custom kudo type names before posting
+// Zero values disable the rule
+type ModerationRules struct {
+	MinLength    int       `json:"min_length" firestore:"min_length"`       // characters, messages only
+	MaxLength    int       `json:"max_length" firestore:"max_length"`       // characters, messages only
+	BlockedWords []string  `json:"blocked_words" firestore:"blocked_words"` // words or phrases, case-insensitive
+	MaxMentions  int       `json:"max_mentions" firestore:"max_mentions"`   // user and group mentions in a message
+	Locale       string    `json:"locale" firestore:"locale"`               // language of the errors shown in the modal
+	UpdatedAt    time.Time `json:"updated_at" firestore:"updated_at"`
+	UpdatedBy    string    `json:"updated_by,omitempty" firestore:"updated_by,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// ReminderDelivery records that a user was reminded in a period
+type ReminderDelivery struct {
+	Period      string    `json:"period" firestore:"period"` // ISO week such as 2026-W11
+	UserID      string    `json:"user_id" firestore:"user_id"`
+	EventID     string    `json:"event_id" firestore:"event_id"` // Pub/Sub event of the run that sent it
+	DeliveredAt time.Time `json:"delivered_at" firestore:"delivered_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ReminderPreference records how a user wants to receive the weekly reminder
+type ReminderPreference struct {
+	UserID       string    `json:"user_id" firestore:"user_id"`
+	OptedOut     bool      `json:"opted_out" firestore:"opted_out"`
+	SnoozedUntil time.Time `json:"snoozed_until" firestore:"snoozed_until"`
+	UpdatedAt    time.Time `json:"updated_at" firestore:"updated_at"`
+}
+
+// Skips reports whether the user shouldn't be reminded at t
+func (p *ReminderPreference) Skips(t time.Time) bool {
+	return p.OptedOut || t.Before(p.SnoozedUntil)
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Settings are changed by admins with /elogie admin and applied without a redeploy
+// Empty fields keep the deployment's configuration
+type Settings struct {
+	KudosChannelID  string           `json:"kudos_channel_id,omitempty" firestore:"kudos_channel_id,omitempty"`   // replaces SLACK_CHANNEL_ID
+	DigestChannelID string           `json:"digest_channel_id,omitempty" firestore:"digest_channel_id,omitempty"` // defaults to the kudos channel
+	Reminder        ReminderSettings `json:"reminder" firestore:"reminder"`
+	UpdatedAt       time.Time        `json:"updated_at" firestore:"updated_at"`
+	UpdatedBy       string           `json:"updated_by,omitempty" firestore:"updated_by,omitempty"`
+}
+
+// ReminderSettings are the schedule and audience of reminder runs
+// Fields set in a run's Pub/Sub message take precedence
+type ReminderSettings struct {
+	Weekday        int      `json:"weekday" firestore:"weekday"`                                       // ISO weekday scheduled runs send on, 1 is Monday, 0 sends on every run
+	Hour           int      `json:"hour" firestore:"hour"`                                             // hour of the weekday scheduled runs start sending
+	ChannelID      string   `json:"channel_id,omitempty" firestore:"channel_id,omitempty"`             // channel whose members are reminded
+	UserGroupID    string   `json:"usergroup_id,omitempty" firestore:"usergroup_id,omitempty"`         // user group reminded instead of a channel
+	ExcludeUserIDs []string `json:"exclude_user_ids,omitempty" firestore:"exclude_user_ids,omitempty"` // never reminded
+	InactiveDays   int      `json:"inactive_days,omitempty" firestore:"inactive_days,omitempty"`       // only remind who hasn't sent kudos in that many days
+}
+
+// Due reports whether a scheduled run at now should send the week's reminders
+// Once the weekday and hour pass, later runs in the same week are due too, so a missed run is made up
+func (r ReminderSettings) Due(now time.Time) bool {
+	if r.Weekday == 0 {
+		return true
+	}
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return weekday > r.Weekday || (weekday == r.Weekday && now.Hour() >= r.Hour)
+}
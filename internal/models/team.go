@@ -0,0 +1,13 @@
+package models
+
+// Team maps a group of people to their manager or team lead
+type Team struct {
+	Name      string   `json:"name"`
+	ManagerID string   `json:"manager_id"`
+	MemberIDs []string `json:"member_ids"`
+}
+
+// OrgChart is the configured team mapping
+type OrgChart struct {
+	Teams []Team `json:"teams"`
+}
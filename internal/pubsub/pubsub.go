@@ -0,0 +1,41 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// MessagePublishedData is the CloudEvent payload delivered by Pub/Sub triggers
+type MessagePublishedData struct {
+	Message Message `json:"message"`
+}
+
+// Message represents a Pub/Sub message
+type Message struct {
+	ID   string `json:"messageId"`
+	Data []byte `json:"data"`
+}
+
+// DecodeMessage decodes the JSON payload of a Pub/Sub CloudEvent into v
+// Events without data or with an empty message leave v untouched
+func DecodeMessage(e event.Event, v interface{}) error {
+	if len(e.Data()) == 0 {
+		return nil
+	}
+
+	var published MessagePublishedData
+	if err := e.DataAs(&published); err != nil {
+		return fmt.Errorf("error decoding Pub/Sub event: %w", err)
+	}
+
+	if len(published.Message.Data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(published.Message.Data, v); err != nil {
+		return fmt.Errorf("error decoding Pub/Sub message data: %w", err)
+	}
+	return nil
+}
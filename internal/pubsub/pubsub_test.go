@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type testPayload struct {
+	Period string `json:"period"`
+}
+
+func newEvent(t *testing.T, data interface{}) event.Event {
+	e := event.New()
+	e.SetID("test-event-id")
+	e.SetSource("test-source")
+	e.SetType("google.cloud.pubsub.topic.v1.messagePublished")
+	if data != nil {
+		if err := e.SetData("application/json", data); err != nil {
+			t.Fatalf("SetData() unexpected error = %v", err)
+		}
+	}
+	return e
+}
+
+func TestDecodeMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     interface{}
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "decodes message data",
+			data:     MessagePublishedData{Message: Message{Data: []byte(`{"period":"2026-03"}`)}},
+			expected: "2026-03",
+		},
+		{
+			name:     "event without data",
+			data:     nil,
+			expected: "",
+		},
+		{
+			name:     "message without data",
+			data:     MessagePublishedData{Message: Message{ID: "123"}},
+			expected: "",
+		},
+		{
+			name:    "invalid message data",
+			data:    MessagePublishedData{Message: Message{Data: []byte(`not json`)}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid event data",
+			data:    "just a string",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload testPayload
+			err := DecodeMessage(newEvent(t, tt.data), &payload)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("DecodeMessage() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeMessage() unexpected error = %v", err)
+			}
+			if payload.Period != tt.expected {
+				t.Errorf("Period = %q, want %q", payload.Period, tt.expected)
+			}
+		})
+	}
+}
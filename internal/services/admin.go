@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// AdminSettingsCallbackID identifies the modal opened by /elogie admin
+const AdminSettingsCallbackID = "admin_settings"
+
+// MaxInactiveDays bounds the inactive days filter of reminders
+const MaxInactiveDays = 365
+
+// maxModerationLength bounds the message length rules, Slack section texts hold up to 3000 characters
+const maxModerationLength = 3000
+
+// maxModerationMentions bounds the mentions rule
+const maxModerationMentions = 100
+
+// reminderWeekdays are the options of the reminder weekday, indexed by ISO weekday
+var reminderWeekdays = []string{"Todas as execuções", "Segunda-feira", "Terça-feira", "Quarta-feira", "Quinta-feira", "Sexta-feira", "Sábado", "Domingo"}
+
+// AdminSettingsForm holds the fields of a submitted admin modal
+type AdminSettingsForm struct {
+	Settings        models.Settings
+	Moderation      models.ModerationRules
+	RemoveKudoTypes []string         // official types added by admins to remove
+	NewKudoType     *models.KudoType // nil when no type is added
+}
+
+// OpenAdminSettings opens the admin modal filled with the current settings
+func OpenAdminSettings(ctx context.Context, triggerID string, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.OpenAdminSettings")
+	defer span.End()
+
+	kudoTypes, err := cfg.Store.ListKudoTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing kudo types: %w", err)
+	}
+
+	view, err := AdminSettingsView(LoadSettings(ctx, cfg), LoadModerationRules(ctx, cfg), kudoTypes)
+	if err != nil {
+		return err
+	}
+	return OpenModal(ctx, triggerID, view, cfg)
+}
+
+// AdminSettingsView builds the admin modal as a views.open template
+// kudoTypes are the official types added by admins, the only ones that can be removed
+func AdminSettingsView(settings models.Settings, rules models.ModerationRules, kudoTypes []*models.KudoType) (string, error) {
+	weekdays := make([]interface{}, 0, len(reminderWeekdays))
+	for weekday, name := range reminderWeekdays {
+		weekdays = append(weekdays, kudoTypeOption(name, strconv.Itoa(weekday)))
+	}
+	hours := make([]interface{}, 0, 24)
+	for hour := range 24 {
+		hours = append(hours, kudoTypeOption(fmt.Sprintf("%02d:00", hour), strconv.Itoa(hour)))
+	}
+
+	blocks := []interface{}{
+		headerBlock("Canais"),
+		channelSelect("admin_kudos_channel", "Canal dos elogios", settings.KudosChannelID),
+		channelSelect("admin_digest_channel", "Canal do resumo mensal", settings.DigestChannelID),
+		headerBlock("Lembrete"),
+		staticSelect("admin_reminder_weekday", "Dia da semana", weekdays, strconv.Itoa(settings.Reminder.Weekday)),
+		staticSelect("admin_reminder_hour", "Horário", hours, strconv.Itoa(settings.Reminder.Hour)),
+		channelSelect("admin_reminder_channel", "Lembrar os membros do canal", settings.Reminder.ChannelID),
+		optionalBlock(plainTextInput("admin_reminder_usergroup", "Ou do grupo de usuários (ID, ex.: S0123ABCD)", settings.Reminder.UserGroupID, false, 20)),
+		usersSelect("admin_reminder_exclude", "Nunca lembrar", settings.Reminder.ExcludeUserIDs),
+		numberInput("admin_reminder_inactive_days", "Só lembrar quem não elogia há (dias)", settings.Reminder.InactiveDays, MaxInactiveDays),
+		headerBlock("Moderação"),
+		numberInput("admin_min_length", "Tamanho mínimo da mensagem", rules.MinLength, maxModerationLength),
+		numberInput("admin_max_length", "Tamanho máximo da mensagem", rules.MaxLength, maxModerationLength),
+		numberInput("admin_max_mentions", "Máximo de menções por mensagem", rules.MaxMentions, maxModerationMentions),
+		optionalBlock(plainTextInput("admin_blocked_words", "Termos bloqueados (um por linha)", strings.Join(rules.BlockedWords, "\n"), true, 3000)),
+		headerBlock("Tipos de elogio"),
+	}
+
+	if len(kudoTypes) > maxKudoTypeOptions {
+		kudoTypes = kudoTypes[:maxKudoTypeOptions]
+	}
+	if len(kudoTypes) > 0 {
+		options := make([]interface{}, 0, len(kudoTypes))
+		for _, kudoType := range kudoTypes {
+			options = append(options, kudoTypeOption(kudoType.Emoji+" "+kudoType.Name, kudoType.ID))
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type":     "input",
+			"block_id": "admin_remove_kudo_types",
+			"optional": true,
+			"label":    map[string]interface{}{"type": "plain_text", "text": "Remover tipos oficiais", "emoji": true},
+			"element": map[string]interface{}{
+				"type":      "multi_static_select",
+				"action_id": "admin_remove_kudo_types",
+				"options":   options,
+			},
+		})
+	}
+	blocks = append(blocks,
+		optionalBlock(plainTextInput("kudo_type_name", "Novo tipo: nome", "", false, 150)),
+		customEmojiBlock(),
+		optionalBlock(plainTextInput("kudo_type_description", "Novo tipo: descrição", "", false, 150)),
+		optionalBlock(plainTextInput("kudo_type_suggested_message", "Novo tipo: mensagem sugerida", "", true, 500)),
+	)
+	blocks = append(blocks, kudoThemeBlocks("Novo tipo: ")...)
+
+	view := map[string]interface{}{
+		"type":        "modal",
+		"callback_id": AdminSettingsCallbackID,
+		"title":       map[string]interface{}{"type": "plain_text", "text": "Configurações", "emoji": true},
+		"submit":      map[string]interface{}{"type": "plain_text", "text": "Salvar", "emoji": true},
+		"close":       map[string]interface{}{"type": "plain_text", "text": "Cancelar", "emoji": true},
+		"blocks":      blocks,
+	}
+	result, err := json.Marshal(map[string]interface{}{"view": view})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling view: %w", err)
+	}
+	return string(result), nil
+}
+
+// ParseAdminSettings reads and validates the admin modal
+// Returns the form and the errors to show in the modal, keyed by block ID
+func ParseAdminSettings(values map[string]map[string]slack.BlockAction) (*AdminSettingsForm, map[string]string) {
+	value := func(id string) slack.BlockAction {
+		return values[id][id]
+	}
+	fieldErrors := make(map[string]string)
+	number := func(id string, maxValue int) int {
+		text := strings.TrimSpace(value(id).Value)
+		if text == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 0 || n > maxValue {
+			fieldErrors[id] = fmt.Sprintf("Informe um número entre 0 e %d", maxValue)
+		}
+		return n
+	}
+
+	form := &AdminSettingsForm{
+		Settings: models.Settings{
+			KudosChannelID:  value("admin_kudos_channel").SelectedChannel,
+			DigestChannelID: value("admin_digest_channel").SelectedChannel,
+			Reminder: models.ReminderSettings{
+				ChannelID:      value("admin_reminder_channel").SelectedChannel,
+				UserGroupID:    strings.TrimSpace(value("admin_reminder_usergroup").Value),
+				ExcludeUserIDs: value("admin_reminder_exclude").SelectedUsers,
+				InactiveDays:   number("admin_reminder_inactive_days", MaxInactiveDays),
+			},
+		},
+		Moderation: models.ModerationRules{
+			MinLength:    number("admin_min_length", maxModerationLength),
+			MaxLength:    number("admin_max_length", maxModerationLength),
+			BlockedWords: parseBlockedWords(value("admin_blocked_words").Value),
+			MaxMentions:  number("admin_max_mentions", maxModerationMentions),
+		},
+	}
+
+	// The selects only offer valid values, anything else keeps the default
+	if weekday, err := strconv.Atoi(value("admin_reminder_weekday").SelectedOption.Value); err == nil && weekday >= 0 && weekday < len(reminderWeekdays) {
+		form.Settings.Reminder.Weekday = weekday
+	}
+	if hour, err := strconv.Atoi(value("admin_reminder_hour").SelectedOption.Value); err == nil && hour >= 0 && hour < 24 {
+		form.Settings.Reminder.Hour = hour
+	}
+
+	if reminder := form.Settings.Reminder; reminder.UserGroupID != "" {
+		switch {
+		case !config.IsUserGroupID(reminder.UserGroupID):
+			fieldErrors["admin_reminder_usergroup"] = "Informe o ID de um grupo de usuários, que começa com S"
+		case reminder.ChannelID != "":
+			fieldErrors["admin_reminder_usergroup"] = "Escolha um canal ou um grupo de usuários, não os dois"
+		}
+	}
+	if rules := form.Moderation; rules.MaxLength > 0 && rules.MaxLength < rules.MinLength {
+		fieldErrors["admin_max_length"] = "O tamanho máximo precisa ser maior que o mínimo"
+	}
+
+	for _, option := range value("admin_remove_kudo_types").SelectedOptions {
+		form.RemoveKudoTypes = append(form.RemoveKudoTypes, option.Value)
+	}
+
+	newType := &models.KudoType{
+		Name:             strings.TrimSpace(value("kudo_type_name").Value),
+		Emoji:            value("kudo_emoji").SelectedOption.Value,
+		Description:      strings.TrimSpace(value("kudo_type_description").Value),
+		SuggestedMessage: strings.TrimSpace(value("kudo_type_suggested_message").Value),
+		Theme:            ParseKudoTheme(values, fieldErrors),
+	}
+	if newType.Name != "" || newType.Description != "" || newType.SuggestedMessage != "" || newType.Theme != (models.KudoTheme{}) {
+		if newType.Name == "" {
+			fieldErrors["kudo_type_name"] = "Por favor, preencha o nome do tipo de elogio"
+		}
+		if newType.Description == "" {
+			fieldErrors["kudo_type_description"] = "Por favor, descreva quando usar este tipo de elogio"
+		}
+		if newType.SuggestedMessage == "" {
+			fieldErrors["kudo_type_suggested_message"] = "Por favor, preencha a mensagem sugerida"
+		}
+		form.NewKudoType = newType
+	}
+
+	return form, fieldErrors
+}
+
+// parseBlockedWords splits the blocked words field by line or comma, without blanks and repetitions
+func parseBlockedWords(text string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' }) {
+		word = strings.TrimSpace(word)
+		if word != "" && !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// SaveAdminSettings persists the admin modal, the changes apply to the next requests
+// Returns ErrKudoTypeExists before saving anything when the new type's name is taken
+func SaveAdminSettings(ctx context.Context, form *AdminSettingsForm, adminID string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.SaveAdminSettings")
+	defer span.End()
+
+	if newType := form.NewKudoType; newType != nil {
+		newType.ID = KudoTypeSlug(newType.Name)
+		if newType.ID == "" || newType.ID == "custom" {
+			return ErrKudoTypeExists
+		}
+		existing, err := KudoTypeByID(ctx, newType.ID, cfg)
+		if err != nil {
+			return err
+		}
+		if existing != nil && !slices.Contains(form.RemoveKudoTypes, existing.ID) {
+			return ErrKudoTypeExists
+		}
+		if !emojiValuePattern.MatchString(newType.Emoji) {
+			newType.Emoji = DefaultCustomKudoEmoji
+		}
+		newType.CreatedBy = adminID
+		newType.CreatedAt = now
+	}
+
+	// The previous values are recorded in the audit log
+	previous, err := cfg.Store.GetSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading settings: %w", err)
+	}
+	previousRules, err := cfg.Store.GetModerationRules(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading moderation rules: %w", err)
+	}
+
+	form.Settings.UpdatedAt = now
+	form.Settings.UpdatedBy = adminID
+	if err := cfg.Store.SaveSettings(ctx, &form.Settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	RecordAudit(ctx, models.AuditSettingsUpdated, adminID, "", previous, form.Settings, cfg)
+
+	// The locale isn't in the modal, keep the current one
+	form.Moderation.Locale = DefaultModerationRules.Locale
+	if previousRules != nil {
+		form.Moderation.Locale = previousRules.Locale
+	}
+	form.Moderation.UpdatedAt = now
+	form.Moderation.UpdatedBy = adminID
+	if err := cfg.Store.SaveModerationRules(ctx, &form.Moderation); err != nil {
+		return fmt.Errorf("error saving moderation rules: %w", err)
+	}
+	RecordAudit(ctx, models.AuditModerationUpdated, adminID, "", previousRules, form.Moderation, cfg)
+
+	for _, id := range form.RemoveKudoTypes {
+		removed, err := cfg.Store.GetKudoType(ctx, id)
+		if err != nil {
+			return fmt.Errorf("error loading kudo type: %w", err)
+		}
+		if removed == nil {
+			continue
+		}
+		if err := cfg.Store.DeleteKudoType(ctx, id); err != nil {
+			return fmt.Errorf("error removing kudo type: %w", err)
+		}
+		RecordAudit(ctx, models.AuditKudoTypeRemoved, adminID, id, removed, nil, cfg)
+	}
+	if form.NewKudoType != nil {
+		if err := cfg.Store.SaveKudoType(ctx, form.NewKudoType); err != nil {
+			return fmt.Errorf("error saving kudo type: %w", err)
+		}
+		RecordAudit(ctx, models.AuditKudoTypeAdded, adminID, form.NewKudoType.ID, nil, form.NewKudoType, cfg)
+	}
+
+	slog.InfoContext(ctx, "Admin settings saved", "admin_id", adminID, "removed_kudo_types", form.RemoveKudoTypes)
+	return nil
+}
+
+// headerBlock is a section title of a modal
+func headerBlock(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "header",
+		"text": map[string]interface{}{"type": "plain_text", "text": text, "emoji": true},
+	}
+}
+
+// optionalBlock marks an input block as optional
+func optionalBlock(block map[string]interface{}) map[string]interface{} {
+	block["optional"] = true
+	return block
+}
+
+// channelSelect is an optional channel picker whose block and action share the ID
+func channelSelect(id, label, initialChannel string) map[string]interface{} {
+	element := map[string]interface{}{
+		"type":      "channels_select",
+		"action_id": id,
+	}
+	if initialChannel != "" {
+		element["initial_channel"] = initialChannel
+	}
+	return optionalBlock(inputBlock(id, label, element))
+}
+
+// usersSelect is an optional multiple users picker whose block and action share the ID
+func usersSelect(id, label string, initialUsers []string) map[string]interface{} {
+	element := map[string]interface{}{
+		"type":      "multi_users_select",
+		"action_id": id,
+	}
+	if len(initialUsers) > 0 {
+		element["initial_users"] = initialUsers
+	}
+	return optionalBlock(inputBlock(id, label, element))
+}
+
+// staticSelect is a required select whose block and action share the ID
+func staticSelect(id, label string, options []interface{}, initialValue string) map[string]interface{} {
+	element := map[string]interface{}{
+		"type":      "static_select",
+		"action_id": id,
+		"options":   options,
+	}
+	for _, option := range options {
+		if option.(map[string]interface{})["value"] == initialValue {
+			element["initial_option"] = option
+		}
+	}
+	return inputBlock(id, label, element)
+}
+
+// numberInput is an optional whole number input whose block and action share the ID, empty means 0
+func numberInput(id, label string, initialValue, maxValue int) map[string]interface{} {
+	element := map[string]interface{}{
+		"type":               "number_input",
+		"action_id":          id,
+		"is_decimal_allowed": false,
+		"min_value":          "0",
+		"max_value":          strconv.Itoa(maxValue),
+	}
+	if initialValue > 0 {
+		element["initial_value"] = strconv.Itoa(initialValue)
+	}
+	return optionalBlock(inputBlock(id, label, element))
+}
+
+// inputBlock wraps an element in an input block with the same ID
+func inputBlock(id, label string, element map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "input",
+		"block_id": id,
+		"label":    map[string]interface{}{"type": "plain_text", "text": label, "emoji": true},
+		"element":  element,
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestAdminSettingsView(t *testing.T) {
+	settings := models.Settings{
+		KudosChannelID: "C0KUDOS",
+		Reminder:       models.ReminderSettings{Weekday: 5, Hour: 14, ExcludeUserIDs: []string{"U111111"}, InactiveDays: 7},
+	}
+	rules := models.ModerationRules{MaxLength: 500, BlockedWords: []string{"chato", "lento"}}
+
+	tests := []struct {
+		name      string
+		kudoTypes []*models.KudoType
+		wantTypes bool
+	}{
+		{name: "no official types added", kudoTypes: nil},
+		{name: "official types can be removed", kudoTypes: []*models.KudoType{{ID: "rockstar", Emoji: ":guitar:", Name: "Rockstar"}}, wantTypes: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AdminSettingsView(settings, rules, tt.kudoTypes)
+			if err != nil {
+				t.Fatalf("AdminSettingsView() unexpected error = %v", err)
+			}
+
+			var request struct {
+				View struct {
+					CallbackID string `json:"callback_id"`
+					Blocks     []struct {
+						BlockID string `json:"block_id"`
+						Element map[string]interface{}
+					} `json:"blocks"`
+				} `json:"view"`
+			}
+			if err := json.Unmarshal([]byte(result), &request); err != nil {
+				t.Fatalf("error decoding view: %v", err)
+			}
+			if request.View.CallbackID != AdminSettingsCallbackID {
+				t.Errorf("callback_id = %q, want %q", request.View.CallbackID, AdminSettingsCallbackID)
+			}
+
+			elements := make(map[string]map[string]interface{})
+			for _, block := range request.View.Blocks {
+				if block.BlockID != "" {
+					elements[block.BlockID] = block.Element
+				}
+			}
+			if got := elements["admin_kudos_channel"]["initial_channel"]; got != "C0KUDOS" {
+				t.Errorf("kudos channel = %v, want C0KUDOS", got)
+			}
+			if got := elements["admin_reminder_weekday"]["initial_option"].(map[string]interface{})["value"]; got != "5" {
+				t.Errorf("weekday = %v, want 5", got)
+			}
+			if got := elements["admin_reminder_hour"]["initial_option"].(map[string]interface{})["value"]; got != "14" {
+				t.Errorf("hour = %v, want 14", got)
+			}
+			if got := elements["admin_reminder_inactive_days"]["initial_value"]; got != "7" {
+				t.Errorf("inactive days = %v, want 7", got)
+			}
+			if _, ok := elements["admin_min_length"]["initial_value"]; ok {
+				t.Errorf("min length = %v, want it empty when disabled", elements["admin_min_length"]["initial_value"])
+			}
+			if got := elements["admin_blocked_words"]["initial_value"]; got != "chato\nlento" {
+				t.Errorf("blocked words = %q, want one per line", got)
+			}
+			if _, ok := elements["admin_remove_kudo_types"]; ok != tt.wantTypes {
+				t.Errorf("remove kudo types block = %v, want %v", ok, tt.wantTypes)
+			}
+			if _, ok := elements["kudo_emoji"]; !ok {
+				t.Error("missing the emoji select of the new kudo type")
+			}
+		})
+	}
+}
+
+// adminValues builds the state of a submitted admin modal
+func adminValues(overrides map[string]slack.BlockAction) map[string]map[string]slack.BlockAction {
+	fields := map[string]slack.BlockAction{
+		"admin_kudos_channel":          {SelectedChannel: "C0KUDOS"},
+		"admin_digest_channel":         {},
+		"admin_reminder_weekday":       {SelectedOption: slack.OptionBlockObject{Value: "1"}},
+		"admin_reminder_hour":          {SelectedOption: slack.OptionBlockObject{Value: "9"}},
+		"admin_reminder_channel":       {},
+		"admin_reminder_usergroup":     {},
+		"admin_reminder_exclude":       {SelectedUsers: []string{"U111111"}},
+		"admin_reminder_inactive_days": {Value: "14"},
+		"admin_min_length":             {Value: "10"},
+		"admin_max_length":             {Value: "1000"},
+		"admin_max_mentions":           {Value: ""},
+		"admin_blocked_words":          {Value: "chato\n lento, chato\n\n"},
+		"kudo_type_name":               {},
+		"kudo_emoji":                   {},
+		"kudo_type_description":        {},
+		"kudo_type_suggested_message":  {},
+		"kudo_type_header":             {},
+		"kudo_type_image_url":          {},
+		"kudo_type_banner":             {},
+		"kudo_type_footer":             {},
+	}
+	maps.Copy(fields, overrides)
+
+	values := make(map[string]map[string]slack.BlockAction)
+	for id, action := range fields {
+		values[id] = map[string]slack.BlockAction{id: action}
+	}
+	return values
+}
+
+func TestParseAdminSettings(t *testing.T) {
+	form, fieldErrors := ParseAdminSettings(adminValues(nil))
+	if len(fieldErrors) > 0 {
+		t.Fatalf("ParseAdminSettings() errors = %v, want none", fieldErrors)
+	}
+	reminder := form.Settings.Reminder
+	if form.Settings.KudosChannelID != "C0KUDOS" || reminder.Weekday != 1 || reminder.Hour != 9 || reminder.InactiveDays != 14 || !slices.Equal(reminder.ExcludeUserIDs, []string{"U111111"}) {
+		t.Errorf("settings = %+v, want the submitted fields", form.Settings)
+	}
+	if form.Moderation.MinLength != 10 || form.Moderation.MaxLength != 1000 || form.Moderation.MaxMentions != 0 {
+		t.Errorf("moderation = %+v, want the submitted limits", form.Moderation)
+	}
+	if !slices.Equal(form.Moderation.BlockedWords, []string{"chato", "lento"}) {
+		t.Errorf("blocked words = %q, want chato and lento once", form.Moderation.BlockedWords)
+	}
+	if form.NewKudoType != nil {
+		t.Errorf("new kudo type = %+v, want nil when its fields are empty", form.NewKudoType)
+	}
+}
+
+func TestParseAdminSettings_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]slack.BlockAction
+		expected  map[string]string
+	}{
+		{
+			name:      "user group name",
+			overrides: map[string]slack.BlockAction{"admin_reminder_usergroup": {Value: "@devs"}},
+			expected:  map[string]string{"admin_reminder_usergroup": "Informe o ID de um grupo de usuários, que começa com S"},
+		},
+		{
+			name: "channel and user group",
+			overrides: map[string]slack.BlockAction{
+				"admin_reminder_channel":   {SelectedChannel: "C0DEVS"},
+				"admin_reminder_usergroup": {Value: "S0DEVS"},
+			},
+			expected: map[string]string{"admin_reminder_usergroup": "Escolha um canal ou um grupo de usuários, não os dois"},
+		},
+		{
+			name:      "too many inactive days",
+			overrides: map[string]slack.BlockAction{"admin_reminder_inactive_days": {Value: "400"}},
+			expected:  map[string]string{"admin_reminder_inactive_days": "Informe um número entre 0 e 365"},
+		},
+		{
+			name:      "not a number",
+			overrides: map[string]slack.BlockAction{"admin_max_mentions": {Value: "dez"}},
+			expected:  map[string]string{"admin_max_mentions": "Informe um número entre 0 e 100"},
+		},
+		{
+			name:      "max length below min length",
+			overrides: map[string]slack.BlockAction{"admin_max_length": {Value: "5"}},
+			expected:  map[string]string{"admin_max_length": "O tamanho máximo precisa ser maior que o mínimo"},
+		},
+		{
+			name:      "incomplete new type",
+			overrides: map[string]slack.BlockAction{"kudo_type_name": {Value: "Rockstar"}},
+			expected: map[string]string{
+				"kudo_type_description":       "Por favor, descreva quando usar este tipo de elogio",
+				"kudo_type_suggested_message": "Por favor, preencha a mensagem sugerida",
+			},
+		},
+		{
+			name: "theme image not https",
+			overrides: map[string]slack.BlockAction{
+				"kudo_type_name":              {Value: "Rockstar"},
+				"kudo_type_description":       {Value: "Deploys sem sustos"},
+				"kudo_type_suggested_message": {Value: "Que deploy!"},
+				"kudo_type_image_url":         {Value: "http://example.com/guitarra.png"},
+			},
+			expected: map[string]string{"kudo_type_image_url": "Informe o endereço https da imagem"},
+		},
+		{
+			name:      "theme without a new type",
+			overrides: map[string]slack.BlockAction{"kudo_type_header": {Value: "🎸 Rockstar! 🎸"}},
+			expected: map[string]string{
+				"kudo_type_name":              "Por favor, preencha o nome do tipo de elogio",
+				"kudo_type_description":       "Por favor, descreva quando usar este tipo de elogio",
+				"kudo_type_suggested_message": "Por favor, preencha a mensagem sugerida",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, fieldErrors := ParseAdminSettings(adminValues(tt.overrides))
+			if !maps.Equal(fieldErrors, tt.expected) {
+				t.Errorf("errors = %v, want %v", fieldErrors, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaveAdminSettings(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		newType     *models.KudoType
+		remove      []string
+		wantErr     error
+		wantTypes   []string
+		wantSettled bool
+	}{
+		{name: "settings only", wantTypes: []string{"rockstar"}, wantSettled: true},
+		{
+			name:        "new type",
+			newType:     &models.KudoType{Name: "Mão na Massa", Description: "Arregaçou as mangas", SuggestedMessage: "Valeu pela ajuda!"},
+			wantTypes:   []string{"rockstar", "mao-na-massa"},
+			wantSettled: true,
+		},
+		{name: "remove type", remove: []string{"rockstar"}, wantSettled: true},
+		{
+			name:        "replace a removed type",
+			newType:     &models.KudoType{Name: "Rockstar", Emoji: ":star:", Description: "Brilhou", SuggestedMessage: "Brilhou!"},
+			remove:      []string{"rockstar"},
+			wantTypes:   []string{"rockstar"},
+			wantSettled: true,
+		},
+		{
+			name:      "built-in name",
+			newType:   &models.KudoType{Name: "Resiliência", Description: "Persistência", SuggestedMessage: "Não desistiu!"},
+			wantErr:   ErrKudoTypeExists,
+			wantTypes: []string{"rockstar"},
+		},
+		{
+			name:      "official type name",
+			newType:   &models.KudoType{Name: "Rockstar", Description: "Brilhou", SuggestedMessage: "Brilhou!"},
+			wantErr:   ErrKudoTypeExists,
+			wantTypes: []string{"rockstar"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			store.SaveKudoType(ctx, &models.KudoType{ID: "rockstar", Emoji: ":guitar:", Name: "Rockstar", CreatedAt: now.Add(-time.Hour)})
+			store.SaveModerationRules(ctx, &models.ModerationRules{MaxLength: 2000, Locale: LocaleEnglish})
+			cfg := &config.Config{Store: store}
+
+			form := &AdminSettingsForm{
+				Settings:        models.Settings{KudosChannelID: "C0KUDOS"},
+				Moderation:      models.ModerationRules{MaxLength: 500, BlockedWords: []string{"chato"}},
+				RemoveKudoTypes: tt.remove,
+				NewKudoType:     tt.newType,
+			}
+			err := SaveAdminSettings(ctx, form, "UADMIN1", now, cfg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SaveAdminSettings() error = %v, want %v", err, tt.wantErr)
+			}
+
+			kudoTypes, _ := store.ListKudoTypes(ctx)
+			var ids []string
+			for _, kudoType := range kudoTypes {
+				ids = append(ids, kudoType.ID)
+			}
+			if !slices.Equal(ids, tt.wantTypes) {
+				t.Errorf("kudo types = %v, want %v", ids, tt.wantTypes)
+			}
+
+			settings, _ := store.GetSettings(ctx)
+			if (settings != nil) != tt.wantSettled {
+				t.Fatalf("settings = %+v, want saved %v", settings, tt.wantSettled)
+			}
+			if !tt.wantSettled {
+				return
+			}
+			if settings.KudosChannelID != "C0KUDOS" || settings.UpdatedBy != "UADMIN1" || !settings.UpdatedAt.Equal(now) {
+				t.Errorf("settings = %+v, want the form saved by the admin", settings)
+			}
+			rules := LoadModerationRules(ctx, cfg)
+			if rules.MaxLength != 500 || rules.Locale != LocaleEnglish || rules.UpdatedBy != "UADMIN1" {
+				t.Errorf("moderation = %+v, want the form with the current locale", rules)
+			}
+			if tt.newType != nil {
+				saved, _ := store.GetKudoType(ctx, KudoTypeSlug(tt.newType.Name))
+				if saved == nil || saved.Emoji == "" || saved.CreatedBy != "UADMIN1" {
+					t.Errorf("new type = %+v, want it saved with an emoji by the admin", saved)
+				}
+			}
+
+			entries, _ := store.ListAuditEntries(ctx, storage.AuditQuery{ActorID: "UADMIN1"})
+			var actions []string
+			for _, entry := range entries {
+				actions = append(actions, entry.Action)
+			}
+			wantActions := []string{models.AuditSettingsUpdated, models.AuditModerationUpdated}
+			for range tt.remove {
+				wantActions = append(wantActions, models.AuditKudoTypeRemoved)
+			}
+			if tt.newType != nil {
+				wantActions = append(wantActions, models.AuditKudoTypeAdded)
+			}
+			if !slices.Equal(actions, wantActions) {
+				t.Errorf("audit actions = %v, want %v", actions, wantActions)
+			}
+			if entries[1].Before["max_length"] != float64(2000) || entries[1].After["max_length"] != float64(500) {
+				t.Errorf("moderation audit = %+v, want the previous and the saved rules", entries[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// maxAuditSummary bounds the entries listed by /elogie auditoria, the export has all of them
+const maxAuditSummary = 20
+
+// typedUserPattern matches a user as typed in a slash command: <@U123|name>, <@U123> or U123
+var typedUserPattern = regexp.MustCompile(`^<?@?([UW][A-Z0-9]+)(?:\|[^>]*)?>?$`)
+
+// requestIDKey is the context key of the request ID recorded in the audit log
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose audit entries record the Slack request, e.g. the view ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// requestIDFrom returns the request ID set with WithRequestID, or ""
+func requestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// RecordAudit appends an entry to the audit log, before and after are saved as their JSON fields
+// Failures are logged, the action itself is already done
+func RecordAudit(ctx context.Context, action, actorID, targetID string, before, after interface{}, cfg *config.Config) {
+	if cfg.Store == nil {
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Action:    action,
+		ActorID:   actorID,
+		TargetID:  targetID,
+		Before:    auditFields(ctx, before),
+		After:     auditFields(ctx, after),
+		RequestID: requestIDFrom(ctx),
+		CreatedAt: time.Now(),
+	}
+	if err := cfg.Store.AppendAuditEntry(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "Could not record audit entry", "action", action, "target_id", targetID, "error", err)
+	}
+}
+
+// auditFields converts a value to its JSON fields, nil for nil values
+func auditFields(ctx context.Context, value interface{}) map[string]interface{} {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not encode audit value", "error", err)
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		slog.WarnContext(ctx, "Could not decode audit value", "error", err)
+		return nil
+	}
+	return fields
+}
+
+// ParseUserMention returns the user ID of a mention typed in a slash command
+func ParseUserMention(text string) (string, bool) {
+	match := typedUserPattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// IsAuditAction reports whether action is recorded in the audit log
+func IsAuditAction(action string) bool {
+	return slices.Contains(models.AuditActions, action)
+}
+
+// FormatAuditSummary lists the most recent entries for /elogie auditoria
+func FormatAuditSummary(entries []*models.AuditEntry, loc *time.Location) string {
+	if len(entries) == 0 {
+		return "Nenhum registro de auditoria encontrado com esses filtros."
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var b strings.Builder
+	shown := entries
+	if len(shown) > maxAuditSummary {
+		shown = shown[len(shown)-maxAuditSummary:]
+		fmt.Fprintf(&b, "🔎 %d registro(s) de auditoria, os %d mais recentes abaixo. Use `jsonl` para exportar todos.\n", len(entries), maxAuditSummary)
+	} else {
+		fmt.Fprintf(&b, "🔎 %d registro(s) de auditoria:\n", len(entries))
+	}
+	for i := len(shown) - 1; i >= 0; i-- {
+		entry := shown[i]
+		fmt.Fprintf(&b, "• %s <@%s> `%s`", entry.CreatedAt.In(loc).Format("02/01/2006 15:04"), entry.ActorID, entry.Action)
+		if entry.TargetID != "" {
+			fmt.Fprintf(&b, " %s", EscapeMrkdwn(entry.TargetID))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// EncodeAuditJSONLines writes one JSON object per entry and line
+func EncodeAuditJSONLines(entries []*models.AuditEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, fmt.Errorf("error encoding audit entry %s: %w", entry.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportAuditLog uploads the entries as JSON lines to the requester's DM
+// from and lastDay name the file, both inclusive. The export itself is recorded in the audit log
+func ExportAuditLog(ctx context.Context, requesterID string, entries []*models.AuditEntry, from, lastDay time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.ExportAuditLog")
+	defer span.End()
+
+	content, err := EncodeAuditJSONLines(entries)
+	if err != nil {
+		return err
+	}
+
+	channel, _, _, err := cfg.SlackAPI.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users: []string{requesterID},
+	})
+	if err != nil {
+		return fmt.Errorf("error opening DM with %s: %w", requesterID, err)
+	}
+
+	filename := fmt.Sprintf("auditoria_%s_%s.jsonl", from.Format("2006-01-02"), lastDay.Format("2006-01-02"))
+	_, err = cfg.SlackAPI.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Channel:        channel.ID,
+		Filename:       filename,
+		Title:          fmt.Sprintf("Auditoria de %s a %s", from.Format("02/01/2006"), lastDay.Format("02/01/2006")),
+		Content:        string(content),
+		FileSize:       len(content),
+		InitialComment: fmt.Sprintf("📄 Auditoria com %d registro(s).", len(entries)),
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading audit log: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Audit log exported", "count", len(entries), "user_id", requesterID, "filename", filename)
+	RecordAudit(ctx, models.AuditLogExported, requesterID, filename, nil, map[string]interface{}{
+		"from":  from.Format("2006-01-02"),
+		"to":    lastDay.Format("2006-01-02"),
+		"count": len(entries),
+	}, cfg)
+	return nil
+}
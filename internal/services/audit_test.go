@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestRecordAudit(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "V123456")
+	store := storage.NewMemoryStore()
+	cfg := &config.Config{Store: store}
+
+	before := &models.KudoType{ID: "rockstar", Name: "Rockstar"}
+	after := &models.KudoType{ID: "rockstar", Name: "Rockstar", Emoji: ":guitar:"}
+	RecordAudit(ctx, models.AuditKudoTypeAdded, "UADMIN1", "rockstar", before, after, cfg)
+	RecordAudit(context.Background(), models.AuditSettingsUpdated, "UADMIN1", "", nil, models.Settings{}, cfg)
+
+	entries, err := store.ListAuditEntries(ctx, storage.AuditQuery{})
+	if err != nil {
+		t.Fatalf("ListAuditEntries() unexpected error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	entry := entries[0]
+	if entry.ID == "" || entry.ActorID != "UADMIN1" || entry.TargetID != "rockstar" || entry.RequestID != "V123456" || entry.CreatedAt.IsZero() {
+		t.Errorf("entry = %+v, want it identified with the actor, target and request", entry)
+	}
+	if entry.Before["emoji"] != "" || entry.After["emoji"] != ":guitar:" {
+		t.Errorf("before = %v, after = %v, want the JSON fields of each", entry.Before, entry.After)
+	}
+	if entries[1].Before != nil || entries[1].RequestID != "" {
+		t.Errorf("entry = %+v, want no before or request ID", entries[1])
+	}
+
+	// Without a store nothing is recorded, the action goes on
+	RecordAudit(ctx, models.AuditKudoSubmitted, "U111111", "kudo-1", nil, nil, &config.Config{})
+}
+
+func TestParseUserMention(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+		ok       bool
+	}{
+		{text: "<@U111111|joana>", expected: "U111111", ok: true},
+		{text: "<@W222222>", expected: "W222222", ok: true},
+		{text: "U333333", expected: "U333333", ok: true},
+		{text: "@joana"},
+		{text: "<#C123456|geral>"},
+		{text: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseUserMention(tt.text)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("ParseUserMention(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestFormatAuditSummary(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := func(count int) []*models.AuditEntry {
+		var list []*models.AuditEntry
+		for i := range count {
+			list = append(list, &models.AuditEntry{
+				Action:    models.AuditKudoSubmitted,
+				ActorID:   "U111111",
+				TargetID:  "kudo-" + string(rune('a'+i)),
+				CreatedAt: start.Add(time.Duration(i) * time.Hour),
+			})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name       string
+		entries    []*models.AuditEntry
+		expected   []string
+		unexpected []string
+	}{
+		{name: "empty", expected: []string{"Nenhum registro de auditoria"}},
+		{
+			name:     "newest first",
+			entries:  entries(2),
+			expected: []string{"2 registro(s)", "• 01/03/2026 13:00 <@U111111> `kudo.submitted` kudo-b\n• 01/03/2026 12:00"},
+		},
+		{
+			name:       "only the most recent",
+			entries:    entries(maxAuditSummary + 1),
+			expected:   []string{"21 registro(s)", "os 20 mais recentes", "kudo-u"},
+			unexpected: []string{"kudo-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := FormatAuditSummary(tt.entries, nil)
+			for _, text := range tt.expected {
+				if !strings.Contains(summary, text) {
+					t.Errorf("summary = %q, want it to contain %q", summary, text)
+				}
+			}
+			for _, text := range tt.unexpected {
+				if strings.Contains(summary, text) {
+					t.Errorf("summary = %q, want it without %q", summary, text)
+				}
+			}
+		})
+	}
+}
+
+func TestExportAuditLog(t *testing.T) {
+	entries := []*models.AuditEntry{
+		{ID: "a1", Action: models.AuditKudoSubmitted, ActorID: "U111111", TargetID: "kudo-1", CreatedAt: time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC)},
+		{ID: "a2", Action: models.AuditSettingsUpdated, ActorID: "UADMIN1", After: map[string]interface{}{"kudos_channel_id": "C0KUDOS"}, CreatedAt: time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name      string
+		uploadErr error
+		wantErr   bool
+	}{
+		{name: "json lines"},
+		{name: "upload fails", uploadErr: errors.New("not_allowed"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploaded slack.UploadFileV2Parameters
+			store := storage.NewMemoryStore()
+			cfg := &config.Config{
+				SlackAPI: &MockSlackClient{
+					UploadFileV2Func: func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+						uploaded = params
+						return &slack.FileSummary{ID: "F123456"}, tt.uploadErr
+					},
+				},
+				Store: store,
+			}
+
+			err := ExportAuditLog(context.Background(), "UADMIN1", entries,
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+				cfg,
+			)
+			exports, _ := store.ListAuditEntries(context.Background(), storage.AuditQuery{Action: models.AuditLogExported})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ExportAuditLog() expected error, got nil")
+				}
+				if len(exports) != 0 {
+					t.Errorf("recorded %d exports, want none when the upload fails", len(exports))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportAuditLog() unexpected error = %v", err)
+			}
+			if len(exports) != 1 || exports[0].ActorID != "UADMIN1" || exports[0].TargetID != "auditoria_2026-03-01_2026-03-31.jsonl" || exports[0].After["count"] != float64(2) {
+				t.Errorf("export entries = %+v, want one by UADMIN1 for the file with 2 entries", exports)
+			}
+
+			if uploaded.Channel != "D123456" || uploaded.Filename != "auditoria_2026-03-01_2026-03-31.jsonl" {
+				t.Errorf("uploaded %s to %s, want auditoria_2026-03-01_2026-03-31.jsonl in D123456", uploaded.Filename, uploaded.Channel)
+			}
+			lines := strings.Split(strings.TrimSpace(uploaded.Content), "\n")
+			if len(lines) != len(entries) {
+				t.Fatalf("got %d lines, want %d", len(lines), len(entries))
+			}
+			for i, line := range lines {
+				var entry models.AuditEntry
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("line %d is not JSON: %v", i, err)
+				}
+				if entry.ID != entries[i].ID || !entry.CreatedAt.Equal(entries[i].CreatedAt) {
+					t.Errorf("line %d = %+v, want %+v", i, entry, entries[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// RespondToCommand posts an ephemeral reply to a slash command's response_url
+// Slow commands acknowledge right away and report their result with it
+func RespondToCommand(ctx context.Context, responseURL, text string, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.RespondToCommand")
+	defer span.End()
+
+	if responseURL == "" {
+		return fmt.Errorf("missing response_url")
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"response_type": "ephemeral",
+		"text":          text,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling command response: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", responseURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making POST request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("command response rejected with %s: %s", resp.Status, body)
+	}
+	return nil
+}
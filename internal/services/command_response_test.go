@@ -0,0 +1,54 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/vyper/my-matter/internal/config"
+)
+
+func TestRespondToCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		responseURL string
+		status      int
+		wantErr     bool
+	}{
+		{name: "ephemeral reply", responseURL: "https://hooks.slack.com/commands/T1/123/abc", status: http.StatusOK},
+		{name: "expired response_url", responseURL: "https://hooks.slack.com/commands/T1/123/abc", status: http.StatusNotFound, wantErr: true},
+		{name: "missing response_url", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]string
+			cfg := &config.Config{
+				HTTPClient: &MockHTTPClient{
+					DoFunc: func(req *http.Request) (*http.Response, error) {
+						if req.URL.String() != tt.responseURL {
+							t.Errorf("posted to %s, want %s", req.URL, tt.responseURL)
+						}
+						json.NewDecoder(req.Body).Decode(&sent)
+						return &http.Response{
+							StatusCode: tt.status,
+							Status:     http.StatusText(tt.status),
+							Body:       io.NopCloser(bytes.NewBufferString("ok")),
+						}, nil
+					},
+				},
+			}
+
+			err := RespondToCommand(context.Background(), tt.responseURL, "📄 Pronto", cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RespondToCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.responseURL != "" && (sent["response_type"] != "ephemeral" || sent["text"] != "📄 Pronto") {
+				t.Errorf("sent %v, want an ephemeral reply", sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// SavedKudoTypePrefix starts the kudo_type values of the workspace's saved custom types, e.g. custom:T123_ab12
+const SavedKudoTypePrefix = "custom:"
+
+// DefaultCustomKudoEmoji is the emoji of custom types when none is picked
+const DefaultCustomKudoEmoji = "✏️"
+
+// maxSavedKudoTypes limits the saved types offered in the modal, a static select takes up to 100 options
+const maxSavedKudoTypes = 50
+
+// maxOptionTextLength is the longest option text Slack accepts
+const maxOptionTextLength = 75
+
+// CustomKudoTypeID identifies a workspace's custom type by its name, ignoring case and extra spaces
+func CustomKudoTypeID(teamID, name string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(name), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return teamID + "_" + hex.EncodeToString(sum[:8])
+}
+
+// IsSavedKudoType reports whether a kudo_type value is one of the workspace's saved custom types
+func IsSavedKudoType(value string) bool {
+	return strings.HasPrefix(value, SavedKudoTypePrefix)
+}
+
+// GetSavedKudoType returns the saved custom type selected in the modal, or nil when it no longer exists
+func GetSavedKudoType(ctx context.Context, value string, cfg *config.Config) (*models.CustomKudoType, error) {
+	if cfg.Store == nil {
+		return nil, nil
+	}
+	kudoType, err := cfg.Store.GetCustomKudoType(ctx, strings.TrimPrefix(value, SavedKudoTypePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("error loading custom kudo type: %w", err)
+	}
+	return kudoType, nil
+}
+
+// SaveCustomKudoType remembers the custom type of a posted kudo, so anyone in the workspace can pick it again
+// The creator is kept, the emoji and the last use follow the latest kudo and each kudo is counted
+// It returns the saved type, or nil when there is nowhere to save it
+func SaveCustomKudoType(ctx context.Context, teamID string, kudo *models.Kudo, cfg *config.Config) (*models.CustomKudoType, error) {
+	if cfg.Store == nil || teamID == "" {
+		return nil, nil
+	}
+
+	// Counted in the store, so concurrent kudos each get their own count and one of them reaches KUDOS_PROMOTE_AFTER
+	kudoType, err := cfg.Store.RecordCustomKudoTypeUse(ctx, &models.CustomKudoType{
+		ID:         CustomKudoTypeID(teamID, kudo.TypeText),
+		TeamID:     teamID,
+		Emoji:      kudo.TypeEmoji,
+		Name:       kudo.TypeText,
+		CreatedBy:  kudo.SenderID,
+		CreatedAt:  kudo.CreatedAt,
+		LastUsedAt: kudo.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving custom kudo type: %w", err)
+	}
+	return kudoType, nil
+}
+
+// AddSavedKudoTypes offers the workspace's saved custom types in the kudo_type select, before "✏️ Outro..."
+func AddSavedKudoTypes(viewTemplate string, kudoTypes []*models.CustomKudoType) (string, error) {
+	if len(kudoTypes) == 0 {
+		return viewTemplate, nil
+	}
+	if len(kudoTypes) > maxSavedKudoTypes {
+		kudoTypes = kudoTypes[:maxSavedKudoTypes]
+	}
+
+	saved := make([]interface{}, 0, len(kudoTypes))
+	for _, kudoType := range kudoTypes {
+		saved = append(saved, kudoTypeOption(kudoType.Emoji+" "+kudoType.Name, SavedKudoTypePrefix+kudoType.ID))
+	}
+	return insertKudoTypeOptions(viewTemplate, saved)
+}
+
+// kudoTypeOption is an option of the kudo_type select
+func kudoTypeOption(text, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"text": map[string]interface{}{
+			"type":  "plain_text",
+			"text":  truncateOptionText(text),
+			"emoji": true,
+		},
+		"value": value,
+	}
+}
+
+// insertKudoTypeOptions adds options to the kudo_type select of a modal template, before "✏️ Outro..."
+// Options beyond what Slack accepts in a select are left out
+func insertKudoTypeOptions(viewTemplate string, added []interface{}) (string, error) {
+	var viewData map[string]interface{}
+	if err := json.Unmarshal([]byte(viewTemplate), &viewData); err != nil {
+		return "", fmt.Errorf("error parsing view template: %w", err)
+	}
+
+	view, ok := viewData["view"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid view structure in template")
+	}
+
+	blocks, ok := view["blocks"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid blocks structure in template")
+	}
+
+	var element map[string]interface{}
+	for _, block := range blocks {
+		if blockMap, ok := block.(map[string]interface{}); ok && blockMap["block_id"] == "kudo_type" {
+			element, _ = blockMap["element"].(map[string]interface{})
+		}
+	}
+	if element == nil {
+		return "", fmt.Errorf("kudo_type block not found in template")
+	}
+	options, ok := element["options"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid kudo_type options in template")
+	}
+	if room := maxKudoTypeOptions - len(options); len(added) > room {
+		added = added[:max(room, 0)]
+	}
+
+	// Keep "✏️ Outro..." last
+	insertPosition := len(options)
+	for i, option := range options {
+		if optionMap, ok := option.(map[string]interface{}); ok && optionMap["value"] == "custom" {
+			insertPosition = i
+		}
+	}
+	newOptions := make([]interface{}, 0, len(options)+len(added))
+	newOptions = append(newOptions, options[:insertPosition]...)
+	newOptions = append(newOptions, added...)
+	newOptions = append(newOptions, options[insertPosition:]...)
+	element["options"] = newOptions
+
+	result, err := json.Marshal(viewData)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling view template: %w", err)
+	}
+	return string(result), nil
+}
+
+// truncateOptionText shortens long type names to fit a select option
+func truncateOptionText(text string) string {
+	if utf8.RuneCountInString(text) <= maxOptionTextLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxOptionTextLength-1]) + "…"
+}
+
+// withSavedKudoTypes adds the workspace's saved custom types to the modal template
+// Types promoted or merged by admins are left out, the official type is offered instead
+// Failures are logged and leave the template unchanged, the predefined types still work
+func withSavedKudoTypes(ctx context.Context, viewTemplate, teamID string, cfg *config.Config) string {
+	if cfg.Store == nil || teamID == "" {
+		return viewTemplate
+	}
+
+	kudoTypes, err := cfg.Store.ListCustomKudoTypes(ctx, teamID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load custom kudo types", "team_id", teamID, "error", err)
+		return viewTemplate
+	}
+	kudoTypes = slices.DeleteFunc(kudoTypes, (*models.CustomKudoType).Resolved)
+
+	withTypes, err := AddSavedKudoTypes(viewTemplate, kudoTypes)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not add custom kudo types to modal", "error", err)
+		return viewTemplate
+	}
+	return withTypes
+}
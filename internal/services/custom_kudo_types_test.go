@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+// failingCustomTypeStore fails to list custom kudo types
+type failingCustomTypeStore struct {
+	storage.Store
+}
+
+func (s failingCustomTypeStore) ListCustomKudoTypes(ctx context.Context, teamID string) ([]*models.CustomKudoType, error) {
+	return nil, errors.New("unavailable")
+}
+
+// kudoTypeOptions returns the values of the kudo_type options in a modal template
+func kudoTypeOptions(t *testing.T, viewTemplate string) []string {
+	t.Helper()
+
+	var view struct {
+		View struct {
+			Blocks []struct {
+				BlockID string `json:"block_id"`
+				Element struct {
+					Options []struct {
+						Text  struct{ Text string } `json:"text"`
+						Value string                `json:"value"`
+					} `json:"options"`
+				} `json:"element"`
+			} `json:"blocks"`
+		} `json:"view"`
+	}
+	if err := json.Unmarshal([]byte(viewTemplate), &view); err != nil {
+		t.Fatalf("error decoding view template: %v", err)
+	}
+
+	var values []string
+	for _, block := range view.View.Blocks {
+		if block.BlockID != "kudo_type" {
+			continue
+		}
+		for _, option := range block.Element.Options {
+			values = append(values, option.Value)
+		}
+	}
+	return values
+}
+
+const kudoTypeTemplate = `{"view": {"blocks": [{"block_id": "kudo_type", "element": {"options": [
+	{"text": {"type": "plain_text", "text": ":dart: Entrega Excepcional"}, "value": "entrega-excepcional"},
+	{"text": {"type": "plain_text", "text": "✏️ Outro..."}, "value": "custom"}
+]}}]}}`
+
+func TestCustomKudoTypeID(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		teamA string
+		teamB string
+		same  bool
+	}{
+		{name: "same name", a: "Rockstar", b: "Rockstar", teamA: "T1", teamB: "T1", same: true},
+		{name: "case and spaces are ignored", a: "Super  Colaborador", b: " super colaborador", teamA: "T1", teamB: "T1", same: true},
+		{name: "different names", a: "Rockstar", b: "Herói", teamA: "T1", teamB: "T1", same: false},
+		{name: "different teams", a: "Rockstar", b: "Rockstar", teamA: "T1", teamB: "T2", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := CustomKudoTypeID(tt.teamA, tt.a), CustomKudoTypeID(tt.teamB, tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("CustomKudoTypeID() = %q and %q, want same = %v", a, b, tt.same)
+			}
+			if !strings.HasPrefix(a, tt.teamA+"_") {
+				t.Errorf("CustomKudoTypeID() = %q, want the team as prefix", a)
+			}
+		})
+	}
+}
+
+func TestSaveCustomKudoType(t *testing.T) {
+	store := storage.NewMemoryStore()
+	cfg := &config.Config{Store: store}
+	ctx := context.Background()
+	created := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	first := &models.Kudo{SenderID: "U111111", TypeID: "custom", TypeEmoji: "✏️", TypeText: "Rockstar", CreatedAt: created}
+	if _, err := SaveCustomKudoType(ctx, "T1", first, cfg); err != nil {
+		t.Fatalf("SaveCustomKudoType() unexpected error = %v", err)
+	}
+	again := &models.Kudo{SenderID: "U222222", TypeID: "custom", TypeEmoji: ":guitar:", TypeText: "rockstar", CreatedAt: created.Add(time.Hour)}
+	saved, err := SaveCustomKudoType(ctx, "T1", again, cfg)
+	if err != nil {
+		t.Fatalf("SaveCustomKudoType() unexpected error = %v", err)
+	}
+	if saved.UsageCount != 2 {
+		t.Errorf("UsageCount = %d, want both kudos counted", saved.UsageCount)
+	}
+
+	types, err := store.ListCustomKudoTypes(ctx, "T1")
+	if err != nil {
+		t.Fatalf("ListCustomKudoTypes() unexpected error = %v", err)
+	}
+	if len(types) != 1 {
+		t.Fatalf("saved %d types, want the same name saved once", len(types))
+	}
+	saved = types[0]
+	if saved.Name != "Rockstar" || saved.CreatedBy != "U111111" || !saved.CreatedAt.Equal(created) {
+		t.Errorf("saved type = %+v, want the original name and creator", saved)
+	}
+	if saved.Emoji != ":guitar:" || !saved.LastUsedAt.Equal(again.CreatedAt) {
+		t.Errorf("saved type = %+v, want the latest emoji and use", saved)
+	}
+
+	// Concurrent kudos are all counted
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := SaveCustomKudoType(ctx, "T1", again, cfg); err != nil {
+				t.Errorf("SaveCustomKudoType() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if counted, _ := store.GetCustomKudoType(ctx, saved.ID); counted.UsageCount != 12 {
+		t.Errorf("UsageCount = %d, want 12 after concurrent kudos", counted.UsageCount)
+	}
+
+	found, err := GetSavedKudoType(ctx, SavedKudoTypePrefix+saved.ID, cfg)
+	if err != nil || found == nil || found.Name != "Rockstar" {
+		t.Errorf("GetSavedKudoType() = %v, %v, want Rockstar", found, err)
+	}
+
+	// Without a team the type can't be offered, so it isn't saved
+	if saved, err := SaveCustomKudoType(ctx, "", first, cfg); err != nil || saved != nil {
+		t.Errorf("SaveCustomKudoType() without team = %v, %v, want nil", saved, err)
+	}
+}
+
+func TestAddSavedKudoTypes(t *testing.T) {
+	many := make([]*models.CustomKudoType, maxSavedKudoTypes+5)
+	for i := range many {
+		many[i] = &models.CustomKudoType{ID: fmt.Sprintf("T1_%d", i), Emoji: ":star:", Name: fmt.Sprintf("Tipo %d", i)}
+	}
+
+	tests := []struct {
+		name      string
+		kudoTypes []*models.CustomKudoType
+		want      []string
+		wantCount int
+	}{
+		{
+			name:      "no saved types",
+			want:      []string{"entrega-excepcional", "custom"},
+			wantCount: 2,
+		},
+		{
+			name: "saved types before custom",
+			kudoTypes: []*models.CustomKudoType{
+				{ID: "T1_a", Emoji: ":guitar:", Name: "Rockstar"},
+				{ID: "T1_b", Emoji: "✏️", Name: "Herói"},
+			},
+			want:      []string{"entrega-excepcional", "custom:T1_a", "custom:T1_b", "custom"},
+			wantCount: 4,
+		},
+		{
+			name:      "limited number of saved types",
+			kudoTypes: many,
+			wantCount: 2 + maxSavedKudoTypes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AddSavedKudoTypes(kudoTypeTemplate, tt.kudoTypes)
+			if err != nil {
+				t.Fatalf("AddSavedKudoTypes() unexpected error = %v", err)
+			}
+			values := kudoTypeOptions(t, result)
+			if len(values) != tt.wantCount {
+				t.Errorf("options = %d, want %d", len(values), tt.wantCount)
+			}
+			if values[len(values)-1] != "custom" {
+				t.Errorf("last option = %q, want custom", values[len(values)-1])
+			}
+			if tt.want != nil && strings.Join(values, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("options = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSavedKudoTypes_LongName(t *testing.T) {
+	result, err := AddSavedKudoTypes(kudoTypeTemplate, []*models.CustomKudoType{
+		{ID: "T1_a", Emoji: ":star:", Name: strings.Repeat("Muito ", 30)},
+	})
+	if err != nil {
+		t.Fatalf("AddSavedKudoTypes() unexpected error = %v", err)
+	}
+	if strings.Contains(result, strings.Repeat("Muito ", 20)) || !strings.Contains(result, "…") {
+		t.Errorf("option text should be truncated to %d characters, got %s", maxOptionTextLength, result)
+	}
+}
+
+func TestAddSavedKudoTypes_InvalidTemplate(t *testing.T) {
+	kudoTypes := []*models.CustomKudoType{{ID: "T1_a", Name: "Rockstar"}}
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "invalid JSON", template: `{`},
+		{name: "missing view", template: `{}`},
+		{name: "missing kudo_type", template: `{"view": {"blocks": []}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AddSavedKudoTypes(tt.template, kudoTypes); err == nil {
+				t.Error("AddSavedKudoTypes() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestKudosViewTemplate_SavedKudoTypes(t *testing.T) {
+	store := storage.NewMemoryStore()
+	store.SaveCustomKudoType(context.Background(), &models.CustomKudoType{ID: "T1_a", TeamID: "T1", Emoji: ":guitar:", Name: "Rockstar"})
+
+	tests := []struct {
+		name   string
+		teamID string
+		store  storage.Store
+		want   int
+	}{
+		{name: "team with saved types", teamID: "T1", store: store, want: 3},
+		{name: "other team", teamID: "T2", store: store, want: 2},
+		{name: "store failure keeps the template", teamID: "T1", store: failingCustomTypeStore{store}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := KudosViewTemplate(context.Background(), kudoTypeTemplate, tt.teamID, "U111111", &config.Config{Store: tt.store})
+			if values := kudoTypeOptions(t, result); len(values) != tt.want {
+				t.Errorf("options = %v, want %d", values, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// ErrDuplicateKudo is returned by PostKudos when the same kudo was already posted
+var ErrDuplicateKudo = errors.New("duplicate kudo")
+
+// requestKeyTTL is how long a request's idempotency key blocks retries of the same request
+const requestKeyTTL = 24 * time.Hour
+
+// ViewIdempotencyKey identifies a modal submission, Slack retries send the same view ID and hash
+func ViewIdempotencyKey(viewID, hash string) string {
+	return fmt.Sprintf("view:%s:%s", viewID, hash)
+}
+
+// KudoFingerprint hashes the sender, recipients, type and message of a kudo
+// Recipient order, letter case and whitespace don't change the fingerprint
+func KudoFingerprint(kudo *models.Kudo) string {
+	recipients := slices.Clone(kudo.RecipientIDs)
+	slices.Sort(recipients)
+
+	message := strings.ToLower(strings.Join(strings.Fields(kudo.Message), " "))
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		kudo.SenderID,
+		strings.Join(recipients, ","),
+		kudo.TypeID,
+		kudo.TypeText,
+		message,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// claimKudo reserves the kudo's request key and content fingerprint
+// It returns ErrDuplicateKudo when either is taken, and a release func to call if posting fails
+// Without a store, or when the store fails, the kudo is let through rather than lost
+func claimKudo(ctx context.Context, kudo *models.Kudo, now time.Time, cfg *config.Config) (func(), error) {
+	if cfg.Store == nil {
+		return func() {}, nil
+	}
+
+	type claim struct {
+		key string
+		ttl time.Duration
+	}
+	var claims []claim
+	if kudo.IdempotencyKey != "" {
+		claims = append(claims, claim{key: kudo.IdempotencyKey, ttl: requestKeyTTL})
+	}
+	if cfg.DedupeWindow > 0 {
+		claims = append(claims, claim{key: "kudo:" + KudoFingerprint(kudo), ttl: cfg.DedupeWindow})
+	}
+
+	var claimed []string
+	release := func() {
+		for _, key := range claimed {
+			if err := cfg.Store.ReleaseKey(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Could not release idempotency key", "key", key, "error", err)
+			}
+		}
+	}
+
+	for _, c := range claims {
+		ok, err := cfg.Store.ClaimKey(ctx, c.key, now, now.Add(c.ttl))
+		if err != nil {
+			slog.WarnContext(ctx, "Could not check for duplicate kudos", "key", c.key, "error", err)
+			continue
+		}
+		if !ok {
+			release()
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKudo, c.key)
+		}
+		claimed = append(claimed, c.key)
+	}
+
+	return release, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestKudoFingerprint(t *testing.T) {
+	base := &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222", "U333333"},
+		TypeID:       "ideia-brilhante",
+		Message:      "Ótima ideia!",
+	}
+
+	tests := []struct {
+		name string
+		kudo models.Kudo
+		same bool
+	}{
+		{
+			name: "recipients in another order",
+			kudo: models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U333333", "U222222"}, TypeID: "ideia-brilhante", Message: "Ótima ideia!"},
+			same: true,
+		},
+		{
+			name: "message case and spacing",
+			kudo: models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, TypeID: "ideia-brilhante", Message: "  ótima   IDEIA! "},
+			same: true,
+		},
+		{
+			name: "different message",
+			kudo: models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, TypeID: "ideia-brilhante", Message: "Outra ideia"},
+		},
+		{
+			name: "different sender",
+			kudo: models.Kudo{SenderID: "U999999", RecipientIDs: []string{"U222222", "U333333"}, TypeID: "ideia-brilhante", Message: "Ótima ideia!"},
+		},
+		{
+			name: "different recipients",
+			kudo: models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}, TypeID: "ideia-brilhante", Message: "Ótima ideia!"},
+		},
+		{
+			name: "different type",
+			kudo: models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, TypeID: "espirito-de-equipe", Message: "Ótima ideia!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KudoFingerprint(&tt.kudo) == KudoFingerprint(base)
+			if got != tt.same {
+				t.Errorf("same fingerprint = %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestPostKudos_Dedupe(t *testing.T) {
+	kudo := func(key, message string) *models.Kudo {
+		return &models.Kudo{
+			SenderID:       "U111111",
+			RecipientIDs:   []string{"U222222"},
+			TypeID:         "ideia-brilhante",
+			Message:        message,
+			IdempotencyKey: key,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		window    time.Duration
+		first     *models.Kudo
+		second    *models.Kudo
+		wantPosts int
+	}{
+		{
+			name:      "same view submitted twice",
+			window:    0,
+			first:     kudo("view:V1:h1", "Valeu!"),
+			second:    kudo("view:V1:h1", "Valeu!"),
+			wantPosts: 1,
+		},
+		{
+			name:      "same content from another view within the window",
+			window:    2 * time.Minute,
+			first:     kudo("view:V1:h1", "Valeu!"),
+			second:    kudo("view:V2:h2", "valeu! "),
+			wantPosts: 1,
+		},
+		{
+			name:      "same content with the window disabled",
+			window:    0,
+			first:     kudo("view:V1:h1", "Valeu!"),
+			second:    kudo("view:V2:h2", "Valeu!"),
+			wantPosts: 2,
+		},
+		{
+			name:      "different content",
+			window:    2 * time.Minute,
+			first:     kudo("view:V1:h1", "Valeu!"),
+			second:    kudo("view:V2:h2", "Obrigado pela ajuda!"),
+			wantPosts: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := 0
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						posts++
+						return channelID, "1234567890.123456", nil
+					},
+				},
+				Store:        storage.NewMemoryStore(),
+				DedupeWindow: tt.window,
+			}
+
+			if err := PostKudos(context.Background(), tt.first, cfg); err != nil {
+				t.Fatalf("first PostKudos() unexpected error = %v", err)
+			}
+			err := PostKudos(context.Background(), tt.second, cfg)
+			if tt.wantPosts == 1 && !errors.Is(err, ErrDuplicateKudo) {
+				t.Errorf("second PostKudos() error = %v, want ErrDuplicateKudo", err)
+			}
+			if tt.wantPosts == 2 && err != nil {
+				t.Errorf("second PostKudos() unexpected error = %v", err)
+			}
+			if posts != tt.wantPosts {
+				t.Errorf("posted %d messages, want %d", posts, tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestPostKudos_DedupeReleasesOnFailure(t *testing.T) {
+	fail := true
+	posts := 0
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				if fail {
+					return "", "", errors.New("ratelimited")
+				}
+				posts++
+				return channelID, "1234567890.123456", nil
+			},
+		},
+		Store:        storage.NewMemoryStore(),
+		DedupeWindow: 2 * time.Minute,
+	}
+	kudo := &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Message: "Valeu!", IdempotencyKey: "view:V1:h1"}
+
+	if err := PostKudos(context.Background(), kudo, cfg); err == nil || errors.Is(err, ErrDuplicateKudo) {
+		t.Fatalf("first PostKudos() error = %v, want post failure", err)
+	}
+
+	fail = false
+	if err := PostKudos(context.Background(), kudo, cfg); err != nil {
+		t.Fatalf("retried PostKudos() unexpected error = %v", err)
+	}
+	if posts != 1 {
+		t.Errorf("posted %d messages, want 1", posts)
+	}
+}
+
+// failingKeyStore fails every idempotency key claim
+type failingKeyStore struct {
+	storage.Store
+}
+
+func (failingKeyStore) ClaimKey(context.Context, string, time.Time, time.Time) (bool, error) {
+	return false, errors.New("unavailable")
+}
+
+func TestPostKudos_DedupeFailsOpen(t *testing.T) {
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI:       &MockSlackClient{},
+		Store:          failingKeyStore{Store: storage.NewMemoryStore()},
+		DedupeWindow:   2 * time.Minute,
+	}
+
+	kudo := &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Message: "Valeu!", IdempotencyKey: "view:V1:h1"}
+	if err := PostKudos(context.Background(), kudo, cfg); err != nil {
+		t.Errorf("PostKudos() unexpected error = %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// digestTopSize is how many entries the digest rankings show
+const digestTopSize = 5
+
+// RankedUser is a user and how many kudos they received
+type RankedUser struct {
+	UserID string
+	Count  int
+}
+
+// RankedType is a kudo type and how many times it was used
+type RankedType struct {
+	Emoji string
+	Text  string
+	Count int
+}
+
+// Digest summarizes the kudos given in a period
+type Digest struct {
+	From          time.Time
+	To            time.Time
+	Total         int
+	PreviousTotal int
+	TopRecipients []RankedUser
+	TopTypes      []RankedType
+	Newcomers     []string // recipients whose first kudo was in the period
+}
+
+// DigestPeriod parses a YYYY-MM period into its month bounds
+// An empty period selects the month before now
+func DigestPeriod(period string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if period == "" {
+		currentStart, _ := MonthPeriod(now, loc)
+		from, to := MonthPeriod(currentStart.AddDate(0, 0, -1), loc)
+		return from, to, nil
+	}
+
+	month, err := time.ParseInLocation("2006-01", period, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q, expected YYYY-MM", period)
+	}
+	from, to := MonthPeriod(month, loc)
+	return from, to, nil
+}
+
+// BuildDigest computes the digest for the kudos created in [from, to)
+// and compares it with the month before
+func BuildDigest(ctx context.Context, from, to time.Time, cfg *config.Config) (*Digest, error) {
+	ctx, span := tracing.Start(ctx, "services.BuildDigest")
+	defer span.End()
+
+	// The whole history is read once, earlier kudos tell the previous total and who was recognized before
+	history, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{To: to})
+	if err != nil {
+		return nil, fmt.Errorf("error listing kudos: %w", err)
+	}
+
+	var kudos []*models.Kudo
+	previousFrom := from.AddDate(0, -1, 0)
+	previousTotal := 0
+	recognizedBefore := make(map[string]bool)
+	for _, kudo := range history {
+		if !kudo.CreatedAt.Before(from) {
+			kudos = append(kudos, kudo)
+			continue
+		}
+		if !kudo.CreatedAt.Before(previousFrom) {
+			previousTotal++
+		}
+		for _, recipientID := range kudo.RecipientIDs {
+			recognizedBefore[recipientID] = true
+		}
+	}
+
+	digest := &Digest{
+		From:          from,
+		To:            to,
+		Total:         len(kudos),
+		PreviousTotal: previousTotal,
+	}
+
+	recipientCounts := make(map[string]int)
+	var recipientOrder []string
+	typeCounts := make(map[string]*RankedType)
+	var typeOrder []string
+
+	for _, kudo := range kudos {
+		for _, recipientID := range kudo.RecipientIDs {
+			if _, seen := recipientCounts[recipientID]; !seen {
+				recipientOrder = append(recipientOrder, recipientID)
+			}
+			recipientCounts[recipientID]++
+		}
+
+		// Custom types are grouped by name, predefined ones by ID
+		key := kudo.TypeID
+		if key == "custom" || key == "" {
+			key = "custom:" + kudo.TypeText
+		}
+		if _, seen := typeCounts[key]; !seen {
+			typeCounts[key] = &RankedType{Emoji: kudo.TypeEmoji, Text: kudo.TypeText}
+			typeOrder = append(typeOrder, key)
+		}
+		typeCounts[key].Count++
+	}
+
+	for _, userID := range recipientOrder {
+		digest.TopRecipients = append(digest.TopRecipients, RankedUser{UserID: userID, Count: recipientCounts[userID]})
+	}
+	sort.SliceStable(digest.TopRecipients, func(i, j int) bool {
+		return digest.TopRecipients[i].Count > digest.TopRecipients[j].Count
+	})
+	if len(digest.TopRecipients) > digestTopSize {
+		digest.TopRecipients = digest.TopRecipients[:digestTopSize]
+	}
+
+	for _, key := range typeOrder {
+		digest.TopTypes = append(digest.TopTypes, *typeCounts[key])
+	}
+	sort.SliceStable(digest.TopTypes, func(i, j int) bool {
+		return digest.TopTypes[i].Count > digest.TopTypes[j].Count
+	})
+	if len(digest.TopTypes) > digestTopSize {
+		digest.TopTypes = digest.TopTypes[:digestTopSize]
+	}
+
+	for _, recipientID := range recipientOrder {
+		if !recognizedBefore[recipientID] {
+			digest.Newcomers = append(digest.Newcomers, recipientID)
+		}
+	}
+
+	return digest, nil
+}
+
+// ErrDigestPosted is returned by PostDigest when the period's digest was already posted to the channel
+var ErrDigestPosted = errors.New("digest already posted")
+
+// digestKeyTTL keeps a period's digest from being posted again, longer than any redelivery or re-run
+const digestKeyTTL = 400 * 24 * time.Hour
+
+// DigestIdempotencyKey identifies the digest of the month starting at from in the channel
+func DigestIdempotencyKey(from time.Time, channelID string) string {
+	return fmt.Sprintf("digest:%s:%s", from.Format("2006-01"), channelID)
+}
+
+// PostDigest publishes the digest to the kudos channel
+// A period's digest is posted once per channel, Pub/Sub redeliveries return ErrDigestPosted
+func PostDigest(ctx context.Context, digest *Digest, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.PostDigest")
+	defer span.End()
+
+	key := DigestIdempotencyKey(digest.From, cfg.SlackChannelID)
+	claimed := false
+	if cfg.Store != nil {
+		now := time.Now()
+		ok, err := cfg.Store.ClaimKey(ctx, key, now, now.Add(digestKeyTTL))
+		switch {
+		case err != nil:
+			slog.WarnContext(ctx, "Could not check whether the digest was posted", "key", key, "error", err)
+		case !ok:
+			return fmt.Errorf("%w: %s", ErrDigestPosted, key)
+		default:
+			claimed = true
+		}
+	}
+
+	_, timestamp, err := cfg.SlackAPI.PostMessageContext(ctx,
+		cfg.SlackChannelID,
+		slack.MsgOptionBlocks(FormatDigestBlocks(digest)...),
+		slack.MsgOptionText(fmt.Sprintf("Resumo de reconhecimentos de %s: %d elogio(s)", FormatMonth(digest.From), digest.Total), false),
+	)
+	if err != nil {
+		// Let a retry post it
+		if claimed {
+			if err := cfg.Store.ReleaseKey(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Could not release idempotency key", "key", key, "error", err)
+			}
+		}
+		return fmt.Errorf("error posting digest: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Digest posted", "period", FormatMonth(digest.From), "channel_id", cfg.SlackChannelID, "message_ts", timestamp)
+	return nil
+}
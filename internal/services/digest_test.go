@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestDigestPeriod(t *testing.T) {
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		period       string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		wantErr      bool
+	}{
+		{
+			name:         "empty period selects previous month",
+			period:       "",
+			expectedFrom: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "explicit month",
+			period:       "2025-12",
+			expectedFrom: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid month",
+			period:  "2026-13",
+			wantErr: true,
+		},
+		{
+			name:    "wrong format",
+			period:  "03/2026",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := DigestPeriod(tt.period, now, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("DigestPeriod() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DigestPeriod() unexpected error = %v", err)
+			}
+			if !from.Equal(tt.expectedFrom) || !to.Equal(tt.expectedTo) {
+				t.Errorf("DigestPeriod() = [%v, %v), want [%v, %v)", from, to, tt.expectedFrom, tt.expectedTo)
+			}
+		})
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	store := storage.NewMemoryStore()
+	fixtures := []*models.Kudo{
+		// January is before the previous month, but U555555 was already recognized
+		{SenderID: "U111111", RecipientIDs: []string{"U555555"}, TypeID: "resiliencia", CreatedAt: day(1, 20)},
+		// February: U222222 already received a kudo before
+		{SenderID: "U111111", RecipientIDs: []string{"U222222"}, TypeID: "resiliencia", TypeEmoji: ":muscle:", TypeText: "Resiliência", CreatedAt: day(2, 10)},
+		// March
+		{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, TypeID: "ideia-brilhante", TypeEmoji: ":bulb:", TypeText: "Ideia Brilhante", CreatedAt: day(3, 2)},
+		{SenderID: "U444444", RecipientIDs: []string{"U333333"}, TypeID: "ideia-brilhante", TypeEmoji: ":bulb:", TypeText: "Ideia Brilhante", CreatedAt: day(3, 5)},
+		{SenderID: "U444444", RecipientIDs: []string{"U333333"}, TypeID: "custom", TypeEmoji: "✏️", TypeText: "Super Colaborador", CreatedAt: day(3, 8)},
+		{SenderID: "U333333", RecipientIDs: []string{"U555555"}, TypeID: "custom", TypeEmoji: "✏️", TypeText: "Outro Tipo", CreatedAt: day(3, 9)},
+		// April is out of the period
+		{SenderID: "U111111", RecipientIDs: []string{"U666666"}, TypeID: "resiliencia", CreatedAt: day(4, 1)},
+	}
+	for _, kudo := range fixtures {
+		store.SaveKudo(context.Background(), kudo)
+	}
+
+	counting := &countingKudoStore{MemoryStore: store}
+	cfg := &config.Config{Store: counting}
+
+	digest, err := BuildDigest(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), cfg)
+	if err != nil {
+		t.Fatalf("BuildDigest() unexpected error = %v", err)
+	}
+
+	if counting.queries != 1 {
+		t.Errorf("ListKudos() called %d times, want the history read once", counting.queries)
+	}
+	if digest.Total != 4 {
+		t.Errorf("Total = %d, want 4", digest.Total)
+	}
+	if digest.PreviousTotal != 1 {
+		t.Errorf("PreviousTotal = %d, want 1", digest.PreviousTotal)
+	}
+
+	if len(digest.TopRecipients) != 3 {
+		t.Fatalf("TopRecipients = %v, want 3 entries", digest.TopRecipients)
+	}
+	if digest.TopRecipients[0] != (RankedUser{UserID: "U333333", Count: 3}) {
+		t.Errorf("top recipient = %+v, want U333333 with 3", digest.TopRecipients[0])
+	}
+
+	if len(digest.TopTypes) != 3 {
+		t.Fatalf("TopTypes = %v, want 3 entries (custom types grouped by name)", digest.TopTypes)
+	}
+	if digest.TopTypes[0].Text != "Ideia Brilhante" || digest.TopTypes[0].Count != 2 {
+		t.Errorf("top type = %+v, want Ideia Brilhante with 2", digest.TopTypes[0])
+	}
+
+	expectedNewcomers := map[string]bool{"U333333": true}
+	if len(digest.Newcomers) != len(expectedNewcomers) {
+		t.Fatalf("Newcomers = %v, want U333333", digest.Newcomers)
+	}
+	for _, userID := range digest.Newcomers {
+		if !expectedNewcomers[userID] {
+			t.Errorf("unexpected newcomer %s", userID)
+		}
+	}
+}
+
+// countingKudoStore counts the kudos queries
+type countingKudoStore struct {
+	*storage.MemoryStore
+	queries int
+}
+
+func (s *countingKudoStore) ListKudos(ctx context.Context, query storage.KudoQuery) ([]*models.Kudo, error) {
+	s.queries++
+	return s.MemoryStore.ListKudos(ctx, query)
+}
+
+func TestBuildDigest_TopSizeLimit(t *testing.T) {
+	store := storage.NewMemoryStore()
+	for i, userID := range []string{"U1", "U2", "U3", "U4", "U5", "U6", "U7"} {
+		store.SaveKudo(context.Background(), &models.Kudo{
+			SenderID:     "U0",
+			RecipientIDs: []string{userID},
+			TypeID:       userID,
+			CreatedAt:    time.Date(2026, 3, i+1, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	digest, err := BuildDigest(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), &config.Config{Store: store})
+	if err != nil {
+		t.Fatalf("BuildDigest() unexpected error = %v", err)
+	}
+
+	if len(digest.TopRecipients) != digestTopSize {
+		t.Errorf("TopRecipients has %d entries, want %d", len(digest.TopRecipients), digestTopSize)
+	}
+	if len(digest.TopTypes) != digestTopSize {
+		t.Errorf("TopTypes has %d entries, want %d", len(digest.TopTypes), digestTopSize)
+	}
+}
+
+func TestPostDigest_Once(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	posts := 0
+	failing := true
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		Store:          store,
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				posts++
+				if failing {
+					return "", "", errors.New("rate_limited")
+				}
+				return channelID, "1234567890.123456", nil
+			},
+		},
+	}
+	march := &Digest{From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	// A failed post can be retried
+	if err := PostDigest(ctx, march, cfg); err == nil {
+		t.Fatal("PostDigest() expected error, got nil")
+	}
+	failing = false
+	if err := PostDigest(ctx, march, cfg); err != nil {
+		t.Fatalf("PostDigest() retry unexpected error = %v", err)
+	}
+	if err := PostDigest(ctx, march, cfg); !errors.Is(err, ErrDigestPosted) {
+		t.Fatalf("PostDigest() redelivery error = %v, want ErrDigestPosted", err)
+	}
+	if posts != 2 {
+		t.Errorf("posted %d times, want 2", posts)
+	}
+
+	// Another month or channel is posted
+	if err := PostDigest(ctx, &Digest{From: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}, cfg); err != nil {
+		t.Errorf("PostDigest() April unexpected error = %v", err)
+	}
+	cfg.SlackChannelID = "C0DIGEST"
+	if err := PostDigest(ctx, march, cfg); err != nil {
+		t.Errorf("PostDigest() other channel unexpected error = %v", err)
+	}
+}
+
+func TestPostDigest(t *testing.T) {
+	tests := []struct {
+		name    string
+		postErr error
+		wantErr bool
+	}{
+		{name: "posts to the kudos channel"},
+		{name: "Slack API error", postErr: errors.New("channel_not_found"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var postedTo string
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						postedTo = channelID
+						return channelID, "1234567890.123456", tt.postErr
+					},
+				},
+			}
+
+			err := PostDigest(context.Background(), &Digest{From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}, cfg)
+
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("PostDigest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if postedTo != "C123456" {
+				t.Errorf("posted to %s, want C123456", postedTo)
+			}
+		})
+	}
+}
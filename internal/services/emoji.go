@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+)
+
+// StandardEmoji are the emoji offered for custom kudo types before the workspace's own
+var StandardEmoji = []string{
+	"pencil2", "star", "star2", "sparkles", "tada", "trophy", "medal", "first_place_medal",
+	"rocket", "fire", "zap", "bulb", "brain", "dart", "gem", "crown",
+	"muscle", "raised_hands", "clap", "handshake", "heart", "green_heart", "blue_heart", "hugging_face",
+	"sunglasses", "star-struck", "sunny", "rainbow", "seedling", "herb", "hammer_and_wrench", "gear",
+	"books", "mortar_board", "compass", "mag", "shield", "lock", "chart_with_upwards_trend", "moneybag",
+	"package", "hourglass", "alarm_clock", "calendar", "speech_balloon", "loudspeaker", "art", "musical_note",
+	"coffee", "pizza", "cake", "unicorn_face", "owl", "dog", "cat", "bee",
+}
+
+// maxEmojiOptions is the most options Slack accepts in an external select
+const maxEmojiOptions = 100
+
+// emojiValuePattern matches emoji codes picked in the modal, e.g. :rocket: or :+1:
+var emojiValuePattern = regexp.MustCompile(`^:[a-z0-9_+'-]+:$`)
+
+// CustomKudoEmoji returns the emoji picked for a custom kudo type, or DefaultCustomKudoEmoji
+// Anything that isn't an emoji code is ignored, so it can't inject mrkdwn
+func CustomKudoEmoji(value string) string {
+	if emojiValuePattern.MatchString(value) {
+		return value
+	}
+	return DefaultCustomKudoEmoji
+}
+
+// EmojiOptions returns the emoji matching the text typed in the emoji select
+// Standard emoji come first, then the workspace emoji from emoji.list
+// Workspace emoji that can't be loaded are logged and left out
+func EmojiOptions(ctx context.Context, query string, cfg *config.Config) []*slack.OptionBlockObject {
+	query = strings.ToLower(strings.Trim(strings.TrimSpace(query), ":"))
+
+	names := append(slices.Clone(StandardEmoji), workspaceEmoji.names(ctx, cfg.SlackAPI, time.Now())...)
+
+	options := []*slack.OptionBlockObject{}
+	for _, name := range names {
+		if !strings.Contains(name, query) {
+			continue
+		}
+		code := fmt.Sprintf(":%s:", name)
+		options = append(options, slack.NewOptionBlockObject(code, slack.NewTextBlockObject(slack.PlainTextType, code+" "+name, true, false), nil))
+		if len(options) == maxEmojiOptions {
+			break
+		}
+	}
+	return options
+}
+
+// workspaceEmojiTTL is how long the workspace emoji are reused, emoji.list is rate limited and the modal
+// asks for options on every keystroke
+const workspaceEmojiTTL = 10 * time.Minute
+
+// workspaceEmoji caches the workspace emoji of the instance
+var workspaceEmoji = &emojiCache{}
+
+// emojiCache keeps the sorted names of the workspace emoji, leaving out the standard ones
+type emojiCache struct {
+	mu       sync.Mutex
+	client   config.SlackClient // the cached names belong to this client's workspace
+	list     []string
+	loadedAt time.Time
+}
+
+// names returns the cached names, loading them again when they're older than workspaceEmojiTTL
+// When emoji.list fails the previous names are kept and it isn't called again before the TTL
+func (c *emojiCache) names(ctx context.Context, client config.SlackClient, now time.Time) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == client && now.Sub(c.loadedAt) < workspaceEmojiTTL {
+		return c.list
+	}
+	if c.client != client {
+		c.list = nil
+	}
+	c.client = client
+	c.loadedAt = now
+
+	workspace, err := client.GetEmojiContext(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not list workspace emoji", "error", err)
+		return c.list
+	}
+	list := make([]string, 0, len(workspace))
+	for name := range workspace {
+		if !slices.Contains(StandardEmoji, name) {
+			list = append(list, name)
+		}
+	}
+	slices.Sort(list)
+	c.list = list
+	return c.list
+}
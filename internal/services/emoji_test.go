@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+)
+
+func TestCustomKudoEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "emoji code", value: ":rocket:", expected: ":rocket:"},
+		{name: "code with symbols", value: ":+1:", expected: ":+1:"},
+		{name: "none picked", value: "", expected: DefaultCustomKudoEmoji},
+		{name: "mrkdwn injection", value: "<!channel>", expected: DefaultCustomKudoEmoji},
+		{name: "text", value: ":rocket: e mais", expected: DefaultCustomKudoEmoji},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CustomKudoEmoji(tt.value); got != tt.expected {
+				t.Errorf("CustomKudoEmoji(%q) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEmojiOptions(t *testing.T) {
+	workspace := map[string]string{
+		"partyparrot": "https://emoji.example.com/partyparrot.gif",
+		"rocket-cat":  "https://emoji.example.com/rocket-cat.png",
+		"rocket":      "alias:rocket",
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		emojiErr    error
+		want        []string
+		wantAtLeast int
+	}{
+		{name: "matches standard then workspace emoji", query: "rocket", want: []string{":rocket:", ":rocket-cat:"}},
+		{name: "colons are ignored", query: ":party", want: []string{":partyparrot:"}},
+		{name: "case is ignored", query: "PARTY", want: []string{":partyparrot:"}},
+		{name: "no match", query: "inexistente", want: []string{}},
+		{name: "workspace failure keeps standard emoji", query: "rocket", emojiErr: errors.New("missing_scope"), want: []string{":rocket:"}},
+		{name: "empty query lists everything", query: "", wantAtLeast: len(StandardEmoji) + 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{SlackAPI: &MockSlackClient{
+				GetEmojiFunc: func() (map[string]string, error) {
+					if tt.emojiErr != nil {
+						return nil, tt.emojiErr
+					}
+					return workspace, nil
+				},
+			}}
+
+			options := EmojiOptions(context.Background(), tt.query, cfg)
+			if tt.wantAtLeast > 0 {
+				if len(options) < tt.wantAtLeast || len(options) > maxEmojiOptions {
+					t.Errorf("options = %d, want at least %d and at most %d", len(options), tt.wantAtLeast, maxEmojiOptions)
+				}
+				return
+			}
+
+			var values []string
+			for _, option := range options {
+				values = append(values, option.Value)
+			}
+			if len(values) != len(tt.want) {
+				t.Fatalf("options = %v, want %v", values, tt.want)
+			}
+			for i := range values {
+				if values[i] != tt.want[i] {
+					t.Errorf("options = %v, want %v", values, tt.want)
+				}
+			}
+			if len(options) > 0 && options[0].Text.Text != options[0].Value+" "+tt.want[0][1:len(tt.want[0])-1] {
+				t.Errorf("option text = %q, want the emoji and its name", options[0].Text.Text)
+			}
+		})
+	}
+}
+
+func TestEmojiCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	calls := 0
+	var emojiErr error
+	client := &MockSlackClient{
+		GetEmojiFunc: func() (map[string]string, error) {
+			calls++
+			if emojiErr != nil {
+				return nil, emojiErr
+			}
+			return map[string]string{"partyparrot": "https://emoji.example.com/partyparrot.gif", "rocket": "alias:rocket"}, nil
+		},
+	}
+	other := &MockSlackClient{GetEmojiFunc: func() (map[string]string, error) {
+		calls++
+		return map[string]string{"other": "https://emoji.example.com/other.png"}, nil
+	}}
+
+	steps := []struct {
+		name      string
+		client    config.SlackClient
+		at        time.Time
+		emojiErr  error
+		wantCalls int
+		want      []string
+	}{
+		{name: "first load", client: client, at: now, wantCalls: 1, want: []string{"partyparrot"}},
+		{name: "within the TTL", client: client, at: now.Add(workspaceEmojiTTL - time.Second), wantCalls: 1, want: []string{"partyparrot"}},
+		{name: "after the TTL", client: client, at: now.Add(workspaceEmojiTTL), wantCalls: 2, want: []string{"partyparrot"}},
+		{name: "failure keeps the names", client: client, at: now.Add(2 * workspaceEmojiTTL), emojiErr: errors.New("ratelimited"), wantCalls: 3, want: []string{"partyparrot"}},
+		{name: "no retry right after a failure", client: client, at: now.Add(2*workspaceEmojiTTL + time.Second), wantCalls: 3, want: []string{"partyparrot"}},
+		{name: "other workspace", client: other, at: now.Add(2*workspaceEmojiTTL + time.Second), wantCalls: 4, want: []string{"other"}},
+	}
+
+	cache := &emojiCache{}
+	for _, step := range steps {
+		emojiErr = step.emojiErr
+		names := cache.names(ctx, step.client, step.at)
+		if calls != step.wantCalls {
+			t.Errorf("%s: emoji.list called %d times, want %d", step.name, calls, step.wantCalls)
+		}
+		if !slices.Equal(names, step.want) {
+			t.Errorf("%s: names = %v, want %v", step.name, names, step.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// Supported export formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// exportDateLayouts lists the accepted date formats for /elogie exportar
+var exportDateLayouts = []string{"02/01/2006", "2006-01-02"}
+
+// ExportedUser identifies a sender or recipient in an export
+type ExportedUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ExportRecord is a single kudo as delivered to HR
+type ExportRecord struct {
+	Date       time.Time      `json:"date"`
+	Sender     ExportedUser   `json:"sender"`
+	Recipients []ExportedUser `json:"recipients"`
+	KudoType   string         `json:"kudo_type"`
+	CustomType string         `json:"custom_type,omitempty"`
+	Message    string         `json:"message"`
+	Points     int            `json:"points"`
+}
+
+// ParseExportDate parses a date in DD/MM/AAAA or AAAA-MM-DD format at midnight in loc
+func ParseExportDate(value string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range exportDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// userResolver looks up user names and emails, caching results for the export
+type userResolver struct {
+	client config.SlackClient
+	cache  map[string]ExportedUser
+}
+
+func (r *userResolver) resolve(ctx context.Context, userID string) ExportedUser {
+	if user, ok := r.cache[userID]; ok {
+		return user
+	}
+
+	user := ExportedUser{ID: userID}
+	info, err := r.client.GetUserInfoContext(ctx, userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not get user info", "user_id", userID, "error", err)
+	} else {
+		user.Name = info.RealName
+		if user.Name == "" {
+			user.Name = info.Name
+		}
+		user.Email = info.Profile.Email
+	}
+
+	r.cache[userID] = user
+	return user
+}
+
+// BuildExportRecords converts stored kudos into export records with user details from Slack
+func BuildExportRecords(ctx context.Context, kudos []*models.Kudo, cfg *config.Config) []ExportRecord {
+	ctx, span := tracing.Start(ctx, "services.BuildExportRecords")
+	defer span.End()
+
+	resolver := &userResolver{client: cfg.SlackAPI, cache: make(map[string]ExportedUser)}
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	records := make([]ExportRecord, 0, len(kudos))
+	for _, kudo := range kudos {
+		record := ExportRecord{
+			Date:     kudo.CreatedAt.In(loc),
+			Sender:   resolver.resolve(ctx, kudo.SenderID),
+			KudoType: kudo.TypeID,
+			Message:  kudo.Message,
+			Points:   kudo.Points,
+		}
+		if kudo.TypeID == "custom" {
+			record.CustomType = kudo.TypeText
+		}
+		for _, recipientID := range kudo.RecipientIDs {
+			record.Recipients = append(record.Recipients, resolver.resolve(ctx, recipientID))
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// EncodeExportCSV writes the records as CSV, joining multiple recipients with "; "
+// Text cells starting like a formula are escaped (see csvCell)
+func EncodeExportCSV(records []ExportRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	header := []string{
+		"date", "sender_id", "sender_name", "sender_email",
+		"recipient_ids", "recipient_names", "recipient_emails",
+		"kudo_type", "custom_type", "message", "points",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, record := range records {
+		var ids, names, emails []string
+		for _, recipient := range record.Recipients {
+			ids = append(ids, recipient.ID)
+			names = append(names, recipient.Name)
+			emails = append(emails, recipient.Email)
+		}
+
+		row := []string{
+			record.Date.Format(time.RFC3339),
+			csvCell(record.Sender.ID),
+			csvCell(record.Sender.Name),
+			csvCell(record.Sender.Email),
+			csvCell(strings.Join(ids, "; ")),
+			csvCell(strings.Join(names, "; ")),
+			csvCell(strings.Join(emails, "; ")),
+			csvCell(record.KudoType),
+			csvCell(record.CustomType),
+			csvCell(record.Message),
+			strconv.Itoa(record.Points),
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, fmt.Errorf("error writing CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("error writing CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// csvCell prefixes values spreadsheets would run as formulas with a quote, so kudos can't inject them
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// EncodeExportJSON writes the records as an indented JSON array
+func EncodeExportJSON(records []ExportRecord) ([]byte, error) {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON: %w", err)
+	}
+	return data, nil
+}
+
+// ExportKudos uploads every kudo created in [from, to) to the requester's DM
+// Returns the number of exported kudos
+func ExportKudos(ctx context.Context, requesterID string, from, to time.Time, format string, cfg *config.Config) (int, error) {
+	ctx, span := tracing.Start(ctx, "services.ExportKudos")
+	defer span.End()
+
+	kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{From: from, To: to})
+	if err != nil {
+		return 0, fmt.Errorf("error listing kudos: %w", err)
+	}
+
+	records := BuildExportRecords(ctx, kudos, cfg)
+
+	var content []byte
+	switch format {
+	case ExportFormatCSV:
+		content, err = EncodeExportCSV(records)
+	case ExportFormatJSON:
+		content, err = EncodeExportJSON(records)
+	default:
+		return 0, fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	channel, _, _, err := cfg.SlackAPI.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+		Users: []string{requesterID},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error opening DM with %s: %w", requesterID, err)
+	}
+
+	lastDay := to.AddDate(0, 0, -1)
+	filename := fmt.Sprintf("elogios_%s_%s.%s", from.Format("2006-01-02"), lastDay.Format("2006-01-02"), format)
+
+	_, err = cfg.SlackAPI.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+		Channel:        channel.ID,
+		Filename:       filename,
+		Title:          fmt.Sprintf("Elogios de %s a %s", from.Format("02/01/2006"), lastDay.Format("02/01/2006")),
+		Content:        string(content),
+		FileSize:       len(content),
+		InitialComment: fmt.Sprintf("📄 Exportação com %d elogio(s).", len(records)),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error uploading export: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Kudos exported", "count", len(records), "user_id", requesterID, "filename", filename)
+	return len(records), nil
+}
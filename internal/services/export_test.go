@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func exportTestUsers(user string) (*slack.User, error) {
+	users := map[string]*slack.User{
+		"U111111": {ID: "U111111", Name: "ana", RealName: "Ana Souza", Profile: slack.UserProfile{Email: "ana@example.com"}},
+		"U222222": {ID: "U222222", Name: "bruno", Profile: slack.UserProfile{Email: "bruno@example.com"}},
+		"U333333": {ID: "U333333", Name: "carla", RealName: "Carla Lima", Profile: slack.UserProfile{Email: "carla@example.com"}},
+	}
+	if u, ok := users[user]; ok {
+		return u, nil
+	}
+	return nil, errors.New("user_not_found")
+}
+
+func TestParseExportDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{name: "brazilian format", value: "15/03/2026", expected: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "ISO format", value: "2026-03-15", expected: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid day", value: "32/03/2026", wantErr: true},
+		{name: "free text", value: "ontem", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := ParseExportDate(tt.value, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseExportDate(%q) expected error, got %v", tt.value, date)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExportDate(%q) unexpected error = %v", tt.value, err)
+			}
+			if !date.Equal(tt.expected) {
+				t.Errorf("ParseExportDate(%q) = %v, want %v", tt.value, date, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildExportRecords(t *testing.T) {
+	calls := 0
+	cfg := &config.Config{
+		Location: time.UTC,
+		SlackAPI: &MockSlackClient{
+			GetUserInfoFunc: func(user string) (*slack.User, error) {
+				calls++
+				return exportTestUsers(user)
+			},
+		},
+	}
+
+	kudos := []*models.Kudo{
+		{
+			SenderID:     "U111111",
+			RecipientIDs: []string{"U222222", "U333333"},
+			TypeID:       "ideia-brilhante",
+			TypeText:     "Ideia Brilhante",
+			Message:      "Muito bom!",
+			CreatedAt:    time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			SenderID:     "U222222",
+			RecipientIDs: []string{"U999999"},
+			TypeID:       "custom",
+			TypeText:     "Super Colaborador",
+			Message:      "Valeu!",
+			Points:       5,
+			CreatedAt:    time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC),
+		},
+	}
+
+	records := BuildExportRecords(context.Background(), kudos, cfg)
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.Sender.Name != "Ana Souza" || first.Sender.Email != "ana@example.com" {
+		t.Errorf("sender = %+v, want Ana Souza <ana@example.com>", first.Sender)
+	}
+	if len(first.Recipients) != 2 || first.Recipients[0].Name != "bruno" {
+		t.Errorf("recipients = %+v, want bruno (fallback to user name) and carla", first.Recipients)
+	}
+	if first.CustomType != "" {
+		t.Errorf("predefined type should not have custom type, got %q", first.CustomType)
+	}
+
+	second := records[1]
+	if second.CustomType != "Super Colaborador" {
+		t.Errorf("custom type = %q, want Super Colaborador", second.CustomType)
+	}
+	if second.Recipients[0].ID != "U999999" || second.Recipients[0].Name != "" {
+		t.Errorf("unknown user should keep only the ID, got %+v", second.Recipients[0])
+	}
+
+	// U111111, U222222, U333333 and U999999 are each looked up once
+	if calls != 4 {
+		t.Errorf("expected 4 GetUserInfo calls, got %d", calls)
+	}
+}
+
+func TestEncodeExportCSV(t *testing.T) {
+	records := []ExportRecord{
+		{
+			Date:   time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC),
+			Sender: ExportedUser{ID: "U111111", Name: "Ana Souza", Email: "ana@example.com"},
+			Recipients: []ExportedUser{
+				{ID: "U222222", Name: "bruno", Email: "bruno@example.com"},
+				{ID: "U333333", Name: "Carla Lima", Email: "carla@example.com"},
+			},
+			KudoType: "ideia-brilhante",
+			Message:  "Linha 1\nLinha 2, com vírgula",
+			Points:   10,
+		},
+		{
+			Date:       time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+			Sender:     ExportedUser{ID: "U111111", Name: "@ana", Email: "ana@example.com"},
+			Recipients: []ExportedUser{{ID: "U222222", Name: "-bruno", Email: "bruno@example.com"}},
+			KudoType:   "custom",
+			CustomType: "+Rockstar",
+			Message:    `=HYPERLINK("http://example.com","clique")`,
+			Points:     -1,
+		},
+	}
+
+	data, err := EncodeExportCSV(records)
+	if err != nil {
+		t.Fatalf("EncodeExportCSV() unexpected error = %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d rows", len(rows))
+	}
+
+	header, row := rows[0], rows[1]
+	values := make(map[string]string)
+	for i, column := range header {
+		values[column] = row[i]
+	}
+
+	expected := map[string]string{
+		"date":             "2026-03-10T15:00:00Z",
+		"sender_email":     "ana@example.com",
+		"recipient_ids":    "U222222; U333333",
+		"recipient_names":  "bruno; Carla Lima",
+		"recipient_emails": "bruno@example.com; carla@example.com",
+		"kudo_type":        "ideia-brilhante",
+		"message":          "Linha 1\nLinha 2, com vírgula",
+		"points":           "10",
+	}
+	for column, want := range expected {
+		if values[column] != want {
+			t.Errorf("column %s = %q, want %q", column, values[column], want)
+		}
+	}
+
+	// Cells that spreadsheets would run as formulas are quoted
+	formulas := make(map[string]string)
+	for i, column := range header {
+		formulas[column] = rows[2][i]
+	}
+	escaped := map[string]string{
+		"sender_name":     "'@ana",
+		"recipient_names": "'-bruno",
+		"custom_type":     "'+Rockstar",
+		"message":         `'=HYPERLINK("http://example.com","clique")`,
+		"points":          "-1",
+	}
+	for column, want := range escaped {
+		if formulas[column] != want {
+			t.Errorf("column %s = %q, want %q", column, formulas[column], want)
+		}
+	}
+}
+
+func TestEncodeExportJSON(t *testing.T) {
+	records := []ExportRecord{
+		{
+			Date:       time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC),
+			Sender:     ExportedUser{ID: "U111111"},
+			Recipients: []ExportedUser{{ID: "U222222"}},
+			KudoType:   "custom",
+			CustomType: "Super Colaborador",
+			Message:    "Valeu!",
+		},
+	}
+
+	data, err := EncodeExportJSON(records)
+	if err != nil {
+		t.Fatalf("EncodeExportJSON() unexpected error = %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(decoded))
+	}
+	if decoded[0]["custom_type"] != "Super Colaborador" {
+		t.Errorf("custom_type = %v, want Super Colaborador", decoded[0]["custom_type"])
+	}
+}
+
+func TestExportKudos(t *testing.T) {
+	store := storage.NewMemoryStore()
+	for _, day := range []int{1, 15, 31} {
+		store.SaveKudo(context.Background(), &models.Kudo{
+			SenderID:     "U111111",
+			RecipientIDs: []string{"U222222"},
+			TypeID:       "resiliencia",
+			CreatedAt:    time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC),
+		})
+	}
+
+	tests := []struct {
+		name          string
+		format        string
+		uploadErr     error
+		openErr       error
+		expectedCount int
+		wantErr       bool
+	}{
+		{name: "csv export", format: ExportFormatCSV, expectedCount: 2},
+		{name: "json export", format: ExportFormatJSON, expectedCount: 2},
+		{name: "unsupported format", format: "xlsx", wantErr: true},
+		{name: "DM cannot be opened", format: ExportFormatCSV, openErr: errors.New("user_not_found"), wantErr: true},
+		{name: "upload fails", format: ExportFormatCSV, uploadErr: errors.New("not_allowed"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var uploaded slack.UploadFileV2Parameters
+			cfg := &config.Config{
+				Location: time.UTC,
+				Store:    store,
+				SlackAPI: &MockSlackClient{
+					GetUserInfoFunc: exportTestUsers,
+					OpenConversationFunc: func(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+						if tt.openErr != nil {
+							return nil, false, false, tt.openErr
+						}
+						if len(params.Users) != 1 || params.Users[0] != "U333333" {
+							t.Errorf("expected DM with requester U333333, got %v", params.Users)
+						}
+						return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D333333"}}}, false, false, nil
+					},
+					UploadFileV2Func: func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+						uploaded = params
+						if tt.uploadErr != nil {
+							return nil, tt.uploadErr
+						}
+						return &slack.FileSummary{ID: "F123456"}, nil
+					},
+				},
+			}
+
+			count, err := ExportKudos(context.Background(),
+				"U333333",
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC),
+				tt.format,
+				cfg,
+			)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ExportKudos() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExportKudos() unexpected error = %v", err)
+			}
+
+			if count != tt.expectedCount {
+				t.Errorf("ExportKudos() count = %d, want %d", count, tt.expectedCount)
+			}
+			if uploaded.Channel != "D333333" {
+				t.Errorf("uploaded to %s, want D333333", uploaded.Channel)
+			}
+			expectedFilename := "elogios_2026-03-01_2026-03-15." + tt.format
+			if uploaded.Filename != expectedFilename {
+				t.Errorf("filename = %s, want %s", uploaded.Filename, expectedFilename)
+			}
+			if uploaded.FileSize != len(uploaded.Content) || uploaded.FileSize == 0 {
+				t.Errorf("file size = %d, content length = %d", uploaded.FileSize, len(uploaded.Content))
+			}
+		})
+	}
+}
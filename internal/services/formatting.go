@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/models"
 )
 
 // FormatUsersForSlack formats a list of user IDs as Slack mentions
@@ -19,75 +22,158 @@ func FormatUsersForSlack(userIDs []string) string {
 
 // FormatAsSlackQuote formats a message as a Slack quote block
 // Adds "> " prefix to each line to maintain quote formatting
+// Lines of ``` code blocks are left unquoted, the prefix would show up inside the code
 func FormatAsSlackQuote(message string) string {
 	if message == "" {
 		return ""
 	}
 	lines := strings.Split(message, "\n")
 	var quotedLines []string
+	inCode := false
 	for _, line := range lines {
-		quotedLines = append(quotedLines, "> "+line)
+		fence := strings.Count(line, "```")%2 == 1
+		if inCode || fence {
+			quotedLines = append(quotedLines, line)
+		} else {
+			quotedLines = append(quotedLines, "> "+line)
+		}
+		if fence {
+			inCode = !inCode
+		}
 	}
 	return strings.Join(quotedLines, "\n")
 }
 
-// FormatKudosAsBlocks creates a Slack Block Kit message for kudos
-func FormatKudosAsBlocks(senderID string, recipientIDs []string, kudoTypeEmoji string, kudoTypeText string, message string) []slack.Block {
-	recipientsFormatted := FormatUsersForSlack(recipientIDs)
-	quotedMessage := FormatAsSlackQuote(message)
+// FormatPoints formats the points awarded by a kudo
+// Example: (10, 1) -> "🪙 *+10 pontos*", (10, 2) -> "🪙 *+10 pontos* para cada"
+func FormatPoints(points, recipientCount int) string {
+	unit := "pontos"
+	if points == 1 {
+		unit = "ponto"
+	}
+	text := fmt.Sprintf("🪙 *+%d %s*", points, unit)
+	if recipientCount > 1 {
+		text += " para cada"
+	}
+	return text
+}
+
+// FormatKudosAsBlocks creates the classic Slack Block Kit message for kudos, without Slack lookups
+func FormatKudosAsBlocks(kudo *models.Kudo) []slack.Block {
+	blocks, err := renderKudoBlocks(classicKudoTemplate, NewKudoMessage(context.Background(), kudo, models.KudoThemes[kudo.TypeID], nil, nil))
+	if err != nil {
+		// The built-in template is covered by tests, this only guards against a broken build
+		return []slack.Block{slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, FormatAsSlackQuote(SanitizeMrkdwn(kudo.Message)), false, false), nil, nil)}
+	}
+	return blocks
+}
+
+// monthNames holds the Portuguese month names indexed by time.Month - 1
+var monthNames = []string{
+	"Janeiro", "Fevereiro", "Março", "Abril", "Maio", "Junho",
+	"Julho", "Agosto", "Setembro", "Outubro", "Novembro", "Dezembro",
+}
 
+// FormatMonth formats a date as its Portuguese month and year
+// Example: 2026-03-01 -> "Março/2026"
+func FormatMonth(t time.Time) string {
+	return fmt.Sprintf("%s/%d", monthNames[t.Month()-1], t.Year())
+}
+
+// formatComparison describes the change in kudos compared with the previous period
+func formatComparison(total, previous int) string {
+	switch diff := total - previous; {
+	case previous == 0 && total > 0:
+		return "🆕 nenhum elogio no mês anterior"
+	case diff > 0:
+		return fmt.Sprintf("📈 +%d em relação ao mês anterior (%d)", diff, previous)
+	case diff < 0:
+		return fmt.Sprintf("📉 %d em relação ao mês anterior (%d)", diff, previous)
+	default:
+		return fmt.Sprintf("➖ mesmo total do mês anterior (%d)", previous)
+	}
+}
+
+// digestMaxNewcomers keeps the newcomers section under Slack's 3000 characters per section
+const digestMaxNewcomers = 100
+
+// FormatDigestBlocks creates a Slack Block Kit message for the monthly recognition digest
+func FormatDigestBlocks(digest *Digest) []slack.Block {
 	emojiTrue := true
 	blocks := []slack.Block{
 		slack.NewHeaderBlock(
 			&slack.TextBlockObject{
 				Type:  slack.PlainTextType,
-				Text:  "🎉 Novo Elogio! 🎉",
+				Text:  fmt.Sprintf("📊 Resumo de Reconhecimentos — %s", FormatMonth(digest.From)),
 				Emoji: &emojiTrue,
 			},
 		),
+	}
+
+	if digest.Total == 0 {
+		blocks = append(blocks,
+			slack.NewSectionBlock(
+				slack.NewTextBlockObject(slack.MarkdownType, "Nenhum elogio registrado neste período. Que tal começar agora com `/elogie`?", false, false),
+				nil,
+				nil,
+			),
+		)
+		return blocks
+	}
 
+	blocks = append(blocks,
 		slack.NewSectionBlock(
+			slack.NewTextBlockObject(
+				slack.MarkdownType,
+				fmt.Sprintf("*%d* elogio(s) no mês\n%s", digest.Total, formatComparison(digest.Total, digest.PreviousTotal)),
+				false,
+				false,
+			),
 			nil,
-			[]*slack.TextBlockObject{
-				{
-					Type: slack.MarkdownType,
-					Text: fmt.Sprintf("*De:*\n<@%s>", senderID),
-				},
-				{
-					Type: slack.MarkdownType,
-					Text: fmt.Sprintf("*Para:*\n%s", recipientsFormatted),
-				},
-			},
 			nil,
 		),
-
 		slack.NewDividerBlock(),
+	)
 
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("%s *%s*", kudoTypeEmoji, kudoTypeText),
-			},
-			nil,
-			nil,
-		),
+	var recipients []string
+	for i, ranked := range digest.TopRecipients {
+		recipients = append(recipients, fmt.Sprintf("%d. <@%s> — %d elogio(s)", i+1, ranked.UserID, ranked.Count))
+	}
+	blocks = append(blocks, slack.NewSectionBlock(
+		slack.NewTextBlockObject(slack.MarkdownType, "🏆 *Mais reconhecidos*\n"+strings.Join(recipients, "\n"), false, false),
+		nil,
+		nil,
+	))
 
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: quotedMessage,
-			},
+	var types []string
+	for i, ranked := range digest.TopTypes {
+		types = append(types, fmt.Sprintf("%d. %s %s — %d", i+1, EscapeMrkdwn(ranked.Emoji), EscapeMrkdwn(ranked.Text), ranked.Count))
+	}
+	blocks = append(blocks, slack.NewSectionBlock(
+		slack.NewTextBlockObject(slack.MarkdownType, "🏷️ *Tipos mais usados*\n"+strings.Join(types, "\n"), false, false),
+		nil,
+		nil,
+	))
+
+	if len(digest.Newcomers) > 0 {
+		newcomers := FormatUsersForSlack(digest.Newcomers[:min(len(digest.Newcomers), digestMaxNewcomers)])
+		if len(digest.Newcomers) > digestMaxNewcomers {
+			newcomers += fmt.Sprintf("\n_+%d outros._", len(digest.Newcomers)-digestMaxNewcomers)
+		}
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject(slack.MarkdownType, "🌱 *Receberam o primeiro elogio*\n"+newcomers, false, false),
 			nil,
 			nil,
-		),
+		))
+	}
 
+	blocks = append(blocks,
 		slack.NewDividerBlock(),
-
 		slack.NewContextBlock(
 			"",
-			slack.NewTextBlockObject(slack.MarkdownType, "✨ _Continue fazendo a diferença!_ ✨", false, false),
+			slack.NewTextBlockObject(slack.MarkdownType, "✨ _Obrigado a todos que reconheceram os colegas este mês!_ ✨", false, false),
 		),
-	}
+	)
 
 	return blocks
 }
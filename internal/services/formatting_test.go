@@ -1,10 +1,14 @@
 package services
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
 )
 
 func TestFormatUsersForSlack(t *testing.T) {
@@ -101,6 +105,16 @@ func TestFormatAsSlackQuote(t *testing.T) {
 			message:  "Texto com *negrito* e _itálico_",
 			expected: "> Texto com *negrito* e _itálico_",
 		},
+		{
+			name:     "message with code block",
+			message:  "Olha só:\n```\nmake deploy\n```\nFuncionou!",
+			expected: "> Olha só:\n```\nmake deploy\n```\n> Funcionou!",
+		},
+		{
+			name:     "message with inline code block",
+			message:  "Rodou ```make``` de primeira",
+			expected: "> Rodou ```make``` de primeira",
+		},
 		{
 			name:     "multiline with markdown",
 			message:  "# Título\n\nTexto **importante**\n- Item 1\n- Item 2",
@@ -266,7 +280,7 @@ func TestFormatKudosAsBlocks(t *testing.T) {
 				}
 
 				if kudoTypeSectionBlock.Text != nil {
-					expectedText := ":rocket: *Acima & Além*"
+					expectedText := ":rocket: *Acima &amp; Além*"
 					if kudoTypeSectionBlock.Text.Text != expectedText {
 						t.Errorf("kudo type text = %q, want %q", kudoTypeSectionBlock.Text.Text, expectedText)
 					}
@@ -323,8 +337,13 @@ func TestFormatKudosAsBlocks(t *testing.T) {
 					return
 				}
 
-				if textElement.Text != "✨ _Continue fazendo a diferença!_ ✨" {
-					t.Errorf("context text = %q, want '✨ _Continue fazendo a diferença!_ ✨'", textElement.Text)
+				// The footer rotates through the default phrases
+				found := false
+				for _, footer := range config.DefaultKudoFooters {
+					found = found || textElement.Text == "✨ _"+footer+"_ ✨"
+				}
+				if !found {
+					t.Errorf("context text = %q, want one of the default footers", textElement.Text)
 				}
 			},
 		},
@@ -368,13 +387,13 @@ func TestFormatKudosAsBlocks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			blocks := FormatKudosAsBlocks(
-				tt.senderID,
-				tt.recipientIDs,
-				tt.kudoTypeEmoji,
-				tt.kudoTypeText,
-				tt.message,
-			)
+			blocks := FormatKudosAsBlocks(&models.Kudo{
+				SenderID:     tt.senderID,
+				RecipientIDs: tt.recipientIDs,
+				TypeEmoji:    tt.kudoTypeEmoji,
+				TypeText:     tt.kudoTypeText,
+				Message:      tt.message,
+			})
 
 			if blocks == nil {
 				t.Fatal("FormatKudosAsBlocks() returned nil")
@@ -389,13 +408,13 @@ func TestFormatKudosAsBlocks(t *testing.T) {
 
 func TestFormatKudosAsBlocks_BlockTypes(t *testing.T) {
 	// Test that the block structure is always consistent
-	blocks := FormatKudosAsBlocks(
-		"U123",
-		[]string{"U456"},
-		":star:",
-		"Test",
-		"Message",
-	)
+	blocks := FormatKudosAsBlocks(&models.Kudo{
+		SenderID:     "U123",
+		RecipientIDs: []string{"U456"},
+		TypeEmoji:    ":star:",
+		TypeText:     "Test",
+		Message:      "Message",
+	})
 
 	expectedBlockTypes := []slack.MessageBlockType{
 		slack.MBTHeader,   // 0: Header
@@ -421,13 +440,13 @@ func TestFormatKudosAsBlocks_BlockTypes(t *testing.T) {
 
 func TestFormatKudosAsBlocks_EmptyRecipients(t *testing.T) {
 	// Test with empty recipients list
-	blocks := FormatKudosAsBlocks(
-		"U123",
-		[]string{},
-		":star:",
-		"Test",
-		"Message",
-	)
+	blocks := FormatKudosAsBlocks(&models.Kudo{
+		SenderID:     "U123",
+		RecipientIDs: []string{},
+		TypeEmoji:    ":star:",
+		TypeText:     "Test",
+		Message:      "Message",
+	})
 
 	if len(blocks) != 7 {
 		t.Errorf("expected 7 blocks even with empty recipients, got %d", len(blocks))
@@ -449,3 +468,193 @@ func TestFormatKudosAsBlocks_EmptyRecipients(t *testing.T) {
 		t.Errorf("should contain '*Para:*' label")
 	}
 }
+
+func TestFormatPoints(t *testing.T) {
+	tests := []struct {
+		name           string
+		points         int
+		recipientCount int
+		expected       string
+	}{
+		{name: "single recipient", points: 10, recipientCount: 1, expected: "🪙 *+10 pontos*"},
+		{name: "multiple recipients", points: 5, recipientCount: 3, expected: "🪙 *+5 pontos* para cada"},
+		{name: "singular point", points: 1, recipientCount: 1, expected: "🪙 *+1 ponto*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := FormatPoints(tt.points, tt.recipientCount); result != tt.expected {
+				t.Errorf("FormatPoints() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatKudosAsBlocks_WithPoints(t *testing.T) {
+	blocks := FormatKudosAsBlocks(&models.Kudo{
+		SenderID:     "U123",
+		RecipientIDs: []string{"U456", "U789"},
+		TypeEmoji:    ":star:",
+		TypeText:     "Test",
+		Message:      "Message",
+		Points:       10,
+	})
+
+	if len(blocks) != 8 {
+		t.Fatalf("expected 8 blocks with points, got %d", len(blocks))
+	}
+
+	pointsBlock, ok := blocks[4].(*slack.ContextBlock)
+	if !ok {
+		t.Fatalf("fifth block should be ContextBlock for points, got %s", blocks[4].BlockType())
+	}
+
+	textElement, ok := pointsBlock.ContextElements.Elements[0].(*slack.TextBlockObject)
+	if !ok {
+		t.Fatal("points element should be TextBlockObject")
+	}
+	if textElement.Text != "🪙 *+10 pontos* para cada" {
+		t.Errorf("points text = %q, want %q", textElement.Text, "🪙 *+10 pontos* para cada")
+	}
+}
+
+func TestFormatMonth(t *testing.T) {
+	tests := []struct {
+		date     time.Time
+		expected string
+	}{
+		{time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), "Janeiro/2026"},
+		{time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "Março/2026"},
+		{time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), "Dezembro/2025"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if result := FormatMonth(tt.date); result != tt.expected {
+				t.Errorf("FormatMonth() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatDigestBlocks(t *testing.T) {
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("empty period", func(t *testing.T) {
+		blocks := FormatDigestBlocks(&Digest{From: march})
+
+		if len(blocks) != 2 {
+			t.Fatalf("expected header and message, got %d blocks", len(blocks))
+		}
+		section, ok := blocks[1].(*slack.SectionBlock)
+		if !ok || !strings.Contains(section.Text.Text, "Nenhum elogio") {
+			t.Error("empty digest should say no kudos were given")
+		}
+	})
+
+	t.Run("full digest", func(t *testing.T) {
+		blocks := FormatDigestBlocks(&Digest{
+			From:          march,
+			Total:         12,
+			PreviousTotal: 8,
+			TopRecipients: []RankedUser{{UserID: "U111111", Count: 4}, {UserID: "U222222", Count: 2}},
+			TopTypes:      []RankedType{{Emoji: ":bulb:", Text: "Ideia Brilhante", Count: 5}},
+			Newcomers:     []string{"U333333"},
+		})
+
+		header, ok := blocks[0].(*slack.HeaderBlock)
+		if !ok || !strings.Contains(header.Text.Text, "Março/2026") {
+			t.Errorf("header should mention the month")
+		}
+
+		var text strings.Builder
+		for _, block := range blocks {
+			if section, ok := block.(*slack.SectionBlock); ok && section.Text != nil {
+				text.WriteString(section.Text.Text + "\n")
+			}
+		}
+		all := text.String()
+
+		for _, expected := range []string{
+			"*12* elogio(s)",
+			"+4 em relação ao mês anterior (8)",
+			"1. <@U111111> — 4 elogio(s)",
+			"2. <@U222222> — 2 elogio(s)",
+			"1. :bulb: Ideia Brilhante — 5",
+			"<@U333333>",
+		} {
+			if !strings.Contains(all, expected) {
+				t.Errorf("digest should contain %q, got:\n%s", expected, all)
+			}
+		}
+	})
+
+	t.Run("newcomers capped", func(t *testing.T) {
+		newcomers := make([]string, digestMaxNewcomers+30)
+		for i := range newcomers {
+			newcomers[i] = fmt.Sprintf("U%08d", i)
+		}
+		blocks := FormatDigestBlocks(&Digest{
+			From:          march,
+			Total:         len(newcomers),
+			TopRecipients: []RankedUser{{UserID: newcomers[0], Count: 1}},
+			TopTypes:      []RankedType{{Emoji: ":bulb:", Text: "Ideia Brilhante", Count: 1}},
+			Newcomers:     newcomers,
+		})
+
+		found := false
+		for _, block := range blocks {
+			section, ok := block.(*slack.SectionBlock)
+			if !ok || section.Text == nil || !strings.Contains(section.Text.Text, "primeiro elogio") {
+				continue
+			}
+			found = true
+			text := section.Text.Text
+			if len(text) > 3000 {
+				t.Errorf("newcomers section has %d characters, want at most 3000", len(text))
+			}
+			if !strings.Contains(text, "_+30 outros._") || strings.Contains(text, newcomers[digestMaxNewcomers]) {
+				t.Errorf("newcomers section = %q, want the first %d and +30", text, digestMaxNewcomers)
+			}
+		}
+		if !found {
+			t.Error("newcomers section missing")
+		}
+	})
+
+	t.Run("no newcomers section when empty", func(t *testing.T) {
+		blocks := FormatDigestBlocks(&Digest{
+			From:          march,
+			Total:         1,
+			TopRecipients: []RankedUser{{UserID: "U111111", Count: 1}},
+			TopTypes:      []RankedType{{Emoji: ":bulb:", Text: "Ideia Brilhante", Count: 1}},
+		})
+		for _, block := range blocks {
+			if section, ok := block.(*slack.SectionBlock); ok && section.Text != nil && strings.Contains(section.Text.Text, "primeiro elogio") {
+				t.Error("newcomers section should be omitted")
+			}
+		}
+	})
+}
+
+func TestFormatComparison(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		previous int
+		expected string
+	}{
+		{name: "increase", total: 10, previous: 7, expected: "📈 +3 em relação ao mês anterior (7)"},
+		{name: "decrease", total: 5, previous: 7, expected: "📉 -2 em relação ao mês anterior (7)"},
+		{name: "same", total: 7, previous: 7, expected: "➖ mesmo total do mês anterior (7)"},
+		{name: "first month", total: 3, previous: 0, expected: "🆕 nenhum elogio no mês anterior"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatComparison(tt.total, tt.previous); result != tt.expected {
+				t.Errorf("formatComparison() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
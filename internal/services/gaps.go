@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// RecognitionGap lists the people a manager leads who haven't received kudos recently
+type RecognitionGap struct {
+	ManagerID string
+	UserIDs   []string
+}
+
+// FindRecognitionGaps returns, per manager, the team members without kudos since the given time
+// Managers leading several teams get a single merged list
+func FindRecognitionGaps(ctx context.Context, since time.Time, cfg *config.Config) ([]RecognitionGap, error) {
+	ctx, span := tracing.Start(ctx, "services.FindRecognitionGaps")
+	defer span.End()
+
+	recognized := make(map[string]bool)
+	var gaps []RecognitionGap
+	gapIndex := make(map[string]int)
+
+	for _, team := range cfg.Teams {
+		for _, memberID := range team.MemberIDs {
+			if memberID == team.ManagerID {
+				continue
+			}
+
+			wasRecognized, checked := recognized[memberID]
+			if !checked {
+				kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{
+					RecipientID: memberID,
+					From:        since,
+					Limit:       1,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("error listing kudos for %s: %w", memberID, err)
+				}
+				wasRecognized = len(kudos) > 0
+				recognized[memberID] = wasRecognized
+			}
+			if wasRecognized {
+				continue
+			}
+
+			index, ok := gapIndex[team.ManagerID]
+			if !ok {
+				index = len(gaps)
+				gapIndex[team.ManagerID] = index
+				gaps = append(gaps, RecognitionGap{ManagerID: team.ManagerID})
+			}
+			if !slices.Contains(gaps[index].UserIDs, memberID) {
+				gaps[index].UserIDs = append(gaps[index].UserIDs, memberID)
+			}
+		}
+	}
+
+	return gaps, nil
+}
+
+// SendGapAlert sends a private DM to the manager listing the people without recent kudos
+func SendGapAlert(ctx context.Context, client config.SlackClient, gap RecognitionGap, weeks int) error {
+	ctx, span := tracing.Start(ctx, "services.SendGapAlert")
+	defer span.End()
+
+	_, _, err := client.PostMessageContext(ctx,
+		gap.ManagerID,
+		slack.MsgOptionBlocks(FormatGapAlertBlocks(gap.UserIDs, weeks)...),
+		slack.MsgOptionText(fmt.Sprintf("%d pessoa(s) do seu time estão há mais de %d semanas sem receber elogios", len(gap.UserIDs), weeks), false),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to send gap alert to %s: %w", gap.ManagerID, err)
+	}
+	return nil
+}
+
+// gapAlertMaxItems keeps the alert under Slack's 50 blocks limit
+const gapAlertMaxItems = 40
+
+// FormatGapAlertBlocks creates the Block Kit structure for the recognition gap alert
+// Each person gets a button that opens the kudos modal pre-filled with them
+func FormatGapAlertBlocks(userIDs []string, weeks int) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(
+			&slack.TextBlockObject{
+				Type: slack.PlainTextType,
+				Text: "🔍 Reconhecimentos pendentes",
+			},
+		),
+
+		slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: fmt.Sprintf("Estas pessoas do seu time não recebem um elogio há mais de *%d semanas*. Que tal reconhecer o trabalho delas?", weeks),
+			},
+			nil,
+			nil,
+		),
+
+		slack.NewDividerBlock(),
+	}
+
+	for i, userID := range userIDs {
+		if i == gapAlertMaxItems {
+			blocks = append(blocks, slack.NewSectionBlock(
+				&slack.TextBlockObject{
+					Type: slack.MarkdownType,
+					Text: fmt.Sprintf("_+%d outros sem elogios recentes._", len(userIDs)-gapAlertMaxItems),
+				},
+				nil,
+				nil,
+			))
+			break
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: fmt.Sprintf("<@%s>", userID),
+			},
+			nil,
+			slack.NewAccessory(
+				slack.NewButtonBlockElement(
+					"open_kudos_modal_for_user",
+					userID,
+					&slack.TextBlockObject{
+						Type: slack.PlainTextType,
+						Text: "📝 Elogiar",
+					},
+				),
+			),
+		))
+	}
+
+	blocks = append(blocks,
+		slack.NewContextBlock(
+			"gap_alert_context",
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: "🔒 Esta mensagem é visível apenas para você.",
+			},
+		),
+	)
+
+	return blocks
+}
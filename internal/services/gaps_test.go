@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestFindRecognitionGaps(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	store := storage.NewMemoryStore()
+	for _, kudo := range []*models.Kudo{
+		{SenderID: "U900", RecipientIDs: []string{"U111"}, CreatedAt: since.AddDate(0, 0, 3)},
+		// Recognized before the window, still a gap
+		{SenderID: "U900", RecipientIDs: []string{"U222"}, CreatedAt: since.AddDate(0, 0, -3)},
+	} {
+		if err := store.SaveKudo(context.Background(), kudo); err != nil {
+			t.Fatalf("SaveKudo() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		teams    []models.Team
+		expected []RecognitionGap
+	}{
+		{
+			name: "lists members without recent kudos",
+			teams: []models.Team{
+				{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"U111", "U222", "U333"}},
+			},
+			expected: []RecognitionGap{
+				{ManagerID: "UMGR1", UserIDs: []string{"U222", "U333"}},
+			},
+		},
+		{
+			name: "skips the manager listed as member",
+			teams: []models.Team{
+				{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"UMGR1", "U333"}},
+			},
+			expected: []RecognitionGap{
+				{ManagerID: "UMGR1", UserIDs: []string{"U333"}},
+			},
+		},
+		{
+			name: "merges teams led by the same manager",
+			teams: []models.Team{
+				{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"U222"}},
+				{Name: "Dados", ManagerID: "UMGR2", MemberIDs: []string{"U444"}},
+				{Name: "Infra", ManagerID: "UMGR1", MemberIDs: []string{"U333", "U222"}},
+			},
+			expected: []RecognitionGap{
+				{ManagerID: "UMGR1", UserIDs: []string{"U222", "U333"}},
+				{ManagerID: "UMGR2", UserIDs: []string{"U444"}},
+			},
+		},
+		{
+			name: "team fully recognized",
+			teams: []models.Team{
+				{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"U111"}},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Teams: tt.teams, Store: store}
+
+			gaps, err := FindRecognitionGaps(context.Background(), since, cfg)
+			if err != nil {
+				t.Fatalf("FindRecognitionGaps() unexpected error = %v", err)
+			}
+
+			if len(gaps) != len(tt.expected) {
+				t.Fatalf("FindRecognitionGaps() returned %d gaps, want %d: %+v", len(gaps), len(tt.expected), gaps)
+			}
+			for i, gap := range gaps {
+				if gap.ManagerID != tt.expected[i].ManagerID {
+					t.Errorf("gap %d manager = %s, want %s", i, gap.ManagerID, tt.expected[i].ManagerID)
+				}
+				if strings.Join(gap.UserIDs, ",") != strings.Join(tt.expected[i].UserIDs, ",") {
+					t.Errorf("gap %d users = %v, want %v", i, gap.UserIDs, tt.expected[i].UserIDs)
+				}
+			}
+		})
+	}
+}
+
+// failingStore is a storage.Store whose queries always fail
+type failingStore struct {
+	storage.Store
+}
+
+func (s *failingStore) ListKudos(ctx context.Context, query storage.KudoQuery) ([]*models.Kudo, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestFindRecognitionGaps_StoreError(t *testing.T) {
+	cfg := &config.Config{
+		Teams: []models.Team{{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"U111"}}},
+		Store: &failingStore{},
+	}
+
+	if _, err := FindRecognitionGaps(context.Background(), time.Now(), cfg); err == nil {
+		t.Error("FindRecognitionGaps() expected error, got nil")
+	}
+}
+
+func TestSendGapAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		postErr     error
+		expectError bool
+	}{
+		{
+			name: "sends DM to manager",
+		},
+		{
+			name:        "post error",
+			postErr:     errors.New("channel_not_found"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var postedTo string
+			mockSlack := &MockSlackClient{
+				PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+					postedTo = channelID
+					return channelID, "1234567890.123456", tt.postErr
+				},
+			}
+
+			err := SendGapAlert(context.Background(), mockSlack, RecognitionGap{ManagerID: "UMGR1", UserIDs: []string{"U111"}}, 4)
+			if tt.expectError && err == nil {
+				t.Error("SendGapAlert() expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("SendGapAlert() unexpected error = %v", err)
+			}
+			if postedTo != "UMGR1" {
+				t.Errorf("SendGapAlert() posted to %s, want UMGR1", postedTo)
+			}
+		})
+	}
+}
+
+func TestFormatGapAlertBlocks(t *testing.T) {
+	blocks := FormatGapAlertBlocks([]string{"U111", "U222"}, 6)
+
+	// header, intro, divider, one section per user and context
+	if len(blocks) != 6 {
+		t.Fatalf("FormatGapAlertBlocks() returned %d blocks, want 6", len(blocks))
+	}
+
+	intro, ok := blocks[1].(*slack.SectionBlock)
+	if !ok {
+		t.Fatalf("block 1 is %T, want *slack.SectionBlock", blocks[1])
+	}
+	if !strings.Contains(intro.Text.Text, "*6 semanas*") {
+		t.Errorf("intro = %q, want it to mention 6 semanas", intro.Text.Text)
+	}
+
+	for i, userID := range []string{"U111", "U222"} {
+		section, ok := blocks[3+i].(*slack.SectionBlock)
+		if !ok {
+			t.Fatalf("block %d is %T, want *slack.SectionBlock", 3+i, blocks[3+i])
+		}
+		if section.Text.Text != "<@"+userID+">" {
+			t.Errorf("section text = %q, want mention of %s", section.Text.Text, userID)
+		}
+		if section.Accessory == nil || section.Accessory.ButtonElement == nil {
+			t.Fatalf("section %d has no button accessory", i)
+		}
+		button := section.Accessory.ButtonElement
+		if button.ActionID != "open_kudos_modal_for_user" {
+			t.Errorf("button action_id = %s, want open_kudos_modal_for_user", button.ActionID)
+		}
+		if button.Value != userID {
+			t.Errorf("button value = %s, want %s", button.Value, userID)
+		}
+	}
+
+	if _, ok := blocks[5].(*slack.ContextBlock); !ok {
+		t.Errorf("last block is %T, want *slack.ContextBlock", blocks[5])
+	}
+}
+
+func TestFormatGapAlertBlocks_Cap(t *testing.T) {
+	var userIDs []string
+	for i := range gapAlertMaxItems + 25 {
+		userIDs = append(userIDs, fmt.Sprintf("U%06d", i))
+	}
+
+	blocks := FormatGapAlertBlocks(userIDs, 6)
+
+	if len(blocks) > 50 {
+		t.Errorf("FormatGapAlertBlocks() returned %d blocks, Slack allows 50", len(blocks))
+	}
+	last := blocks[len(blocks)-3].(*slack.SectionBlock)
+	if last.Text.Text != fmt.Sprintf("<@U%06d>", gapAlertMaxItems-1) {
+		t.Errorf("last listed person = %q, want the %dth", last.Text.Text, gapAlertMaxItems)
+	}
+	overflow := blocks[len(blocks)-2].(*slack.SectionBlock)
+	if !strings.Contains(overflow.Text.Text, "+25 outros") || overflow.Accessory != nil {
+		t.Errorf("overflow section = %+v, want the 25 remaining people without a button", overflow)
+	}
+}
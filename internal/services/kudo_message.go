@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"text/template"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/templates"
+)
+
+// classicKudoTemplate renders FormatKudosAsBlocks and replaces templates that fail to render
+var classicKudoTemplate = template.Must(templates.KudoTemplate(templates.DefaultKudoVariant))
+
+// KudoPerson is a sender or recipient as shown in the kudos message
+type KudoPerson struct {
+	ID      string
+	Mention string // e.g. <@U123>
+
+	profiles *profileResolver
+}
+
+// Name returns the Slack display name escaped for mrkdwn, empty when the profile can't be loaded
+func (p KudoPerson) Name() string {
+	return EscapeMrkdwn(p.PlainName())
+}
+
+// PlainName returns the Slack display name as set by the person, for plain_text fields
+func (p KudoPerson) PlainName() string {
+	return p.profiles.profile(p.ID).name
+}
+
+// AvatarURL returns the 72px Slack avatar, empty when the profile can't be loaded
+func (p KudoPerson) AvatarURL() string {
+	return p.profiles.profile(p.ID).avatarURL
+}
+
+// defaultKudoHeader is the header of kudos without a themed one
+const defaultKudoHeader = "🎉 Novo Elogio! 🎉"
+
+// KudoMessage is the data rendered by the kudos message templates
+type KudoMessage struct {
+	Theme             models.KudoTheme // the type's theme as set, empty fields when it has none
+	Header            string           // from the type's theme, or the default header
+	ImageURL          string           // accent image of the type's theme
+	Banner            string           // emoji banner of the type's theme
+	TypeID            string
+	TypeEmoji         string
+	TypeText          string // escaped with EscapeMrkdwn
+	TypeName          string // TypeText as typed, for plain_text fields
+	Message           string // sanitized with SanitizeMrkdwn
+	QuotedMessage     string // Message as a Slack quote
+	RichMessage       string // rich_text block JSON of the quoted message, empty for plain text messages
+	Points            int
+	PointsText        string // e.g. "🪙 *+10 pontos* para cada"
+	RecipientMentions string // e.g. "<@U123>, <@U456>"
+	Footer            string // from the type's theme, or a phrase picked from the footer pool
+
+	kudo     *models.Kudo
+	profiles *profileResolver
+}
+
+// NewKudoMessage prepares a kudo for rendering
+// The message, type name and display names are sanitized for mrkdwn, so they can't notify channels or disguise links
+// Names and avatars are looked up with client only when the template uses them, a nil client skips them
+func NewKudoMessage(ctx context.Context, kudo *models.Kudo, theme models.KudoTheme, footers []string, client config.SlackClient) *KudoMessage {
+	text := SanitizeMrkdwn(kudo.Message)
+	message := &KudoMessage{
+		Theme:             theme,
+		Header:            cmp.Or(theme.Header, defaultKudoHeader),
+		ImageURL:          theme.ImageURL,
+		Banner:            theme.Banner,
+		TypeID:            kudo.TypeID,
+		TypeEmoji:         EscapeMrkdwn(kudo.TypeEmoji),
+		TypeText:          EscapeMrkdwn(kudo.TypeText),
+		TypeName:          kudo.TypeText,
+		Message:           text,
+		QuotedMessage:     FormatAsSlackQuote(text),
+		Points:            kudo.Points,
+		RecipientMentions: FormatUsersForSlack(kudo.RecipientIDs),
+		Footer:            cmp.Or(SanitizeMrkdwn(theme.Footer), pickFooter(footers, kudo.CreatedAt)),
+		kudo:              kudo,
+		profiles:          &profileResolver{ctx: ctx, client: client, cache: make(map[string]profile)},
+	}
+	if kudo.MessageRichText != "" {
+		message.RichMessage = quotedRichMessage(ctx, kudo.MessageRichText)
+	}
+	if kudo.Points > 0 {
+		message.PointsText = FormatPoints(kudo.Points, len(kudo.RecipientIDs))
+	}
+	return message
+}
+
+// Sender returns the person who sent the kudo
+func (m *KudoMessage) Sender() KudoPerson {
+	return m.person(m.kudo.SenderID)
+}
+
+// Recipients returns the people who received the kudo
+func (m *KudoMessage) Recipients() []KudoPerson {
+	recipients := make([]KudoPerson, 0, len(m.kudo.RecipientIDs))
+	for _, userID := range m.kudo.RecipientIDs {
+		recipients = append(recipients, m.person(userID))
+	}
+	return recipients
+}
+
+// Limits of the recipients shown with their avatars, so the message stays under Slack's 50 blocks
+const (
+	avatarsPerRow     = 5   // each recipient takes an image and a text, a context block holds 10 elements
+	maxAvatarRows     = 8   // recipients beyond 40 are mentioned in MoreRecipients
+	maxMoreRecipients = 150 // mentions in MoreRecipients, the rest are only counted
+)
+
+// RecipientRows groups the first recipients in rows of avatarsPerRow, one context block each
+// The recipients left out are mentioned by MoreRecipients
+func (m *KudoMessage) RecipientRows() [][]KudoPerson {
+	recipients := m.Recipients()
+	if len(recipients) > avatarsPerRow*maxAvatarRows {
+		recipients = recipients[:avatarsPerRow*maxAvatarRows]
+	}
+
+	var rows [][]KudoPerson
+	for start := 0; start < len(recipients); start += avatarsPerRow {
+		rows = append(rows, recipients[start:min(start+avatarsPerRow, len(recipients))])
+	}
+	return rows
+}
+
+// MoreRecipients mentions the recipients left out of RecipientRows, e.g. "+2: <@U123>, <@U456>"
+// It's empty when every recipient has a row
+func (m *KudoMessage) MoreRecipients() string {
+	hidden := m.kudo.RecipientIDs[min(len(m.kudo.RecipientIDs), avatarsPerRow*maxAvatarRows):]
+	if len(hidden) == 0 {
+		return ""
+	}
+
+	text := fmt.Sprintf("+%d: %s", len(hidden), FormatUsersForSlack(hidden[:min(len(hidden), maxMoreRecipients)]))
+	if len(hidden) > maxMoreRecipients {
+		text += fmt.Sprintf(" e mais %d", len(hidden)-maxMoreRecipients)
+	}
+	return text
+}
+
+func (m *KudoMessage) person(userID string) KudoPerson {
+	return KudoPerson{ID: userID, Mention: fmt.Sprintf("<@%s>", userID), profiles: m.profiles}
+}
+
+// quotedRichMessage encodes the rich text message for the templates
+// Messages that can't be posted as rich text fall back to QuotedMessage
+func quotedRichMessage(ctx context.Context, richText string) string {
+	block, err := QuotedRichText(richText)
+	if err == nil {
+		SanitizeRichText(block)
+		var data []byte
+		if data, err = json.Marshal(block); err == nil {
+			return string(data)
+		}
+	}
+	slog.WarnContext(ctx, "Posting the kudos message as mrkdwn", "error", err)
+	return ""
+}
+
+// pickFooter rotates through the footer phrases as kudos are sent
+func pickFooter(footers []string, createdAt time.Time) string {
+	if len(footers) == 0 {
+		footers = config.DefaultKudoFooters
+	}
+	return footers[uint64(createdAt.Unix())%uint64(len(footers))]
+}
+
+// profile is the part of a Slack profile shown in kudos messages
+type profile struct {
+	name      string
+	avatarURL string
+}
+
+// profileResolver looks up Slack profiles, caching results for one message
+type profileResolver struct {
+	ctx    context.Context
+	client config.SlackClient
+	cache  map[string]profile
+}
+
+func (r *profileResolver) profile(userID string) profile {
+	if p, ok := r.cache[userID]; ok {
+		return p
+	}
+
+	var p profile
+	if r.client != nil {
+		info, err := r.client.GetUserInfoContext(r.ctx, userID)
+		if err != nil {
+			slog.WarnContext(r.ctx, "Could not get user info", "user_id", userID, "error", err)
+		} else {
+			p.name = info.Profile.DisplayName
+			if p.name == "" {
+				p.name = info.RealName
+			}
+			if p.name == "" {
+				p.name = info.Name
+			}
+			p.avatarURL = info.Profile.Image72
+		}
+	}
+
+	r.cache[userID] = p
+	return p
+}
+
+// RenderKudoBlocks renders a kudo with the configured message template
+func RenderKudoBlocks(ctx context.Context, kudo *models.Kudo, cfg *config.Config) ([]slack.Block, error) {
+	tmpl := cfg.KudoTemplate
+	if tmpl == nil {
+		tmpl = classicKudoTemplate
+	}
+	theme := KudoTypeTheme(ctx, kudo.TypeID, cfg)
+	return renderKudoBlocks(tmpl, NewKudoMessage(ctx, kudo, theme, cfg.KudoFooters, cfg.SlackAPI))
+}
+
+// renderKudoBlocks executes a kudos message template and decodes the Block Kit JSON it produces
+func renderKudoBlocks(tmpl *template.Template, message *KudoMessage) ([]slack.Block, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, message); err != nil {
+		return nil, fmt.Errorf("error rendering kudos template %s: %w", tmpl.Name(), err)
+	}
+
+	var blocks slack.Blocks
+	if err := json.Unmarshal(buf.Bytes(), &blocks); err != nil {
+		return nil, fmt.Errorf("kudos template %s did not render Block Kit JSON: %w", tmpl.Name(), err)
+	}
+	if len(blocks.BlockSet) == 0 {
+		return nil, fmt.Errorf("kudos template %s rendered no blocks", tmpl.Name())
+	}
+	return blocks.BlockSet, nil
+}
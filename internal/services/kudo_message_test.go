@@ -0,0 +1,515 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/templates"
+)
+
+// blockTypes lists the types of the blocks in order
+func blockTypes(blocks []slack.Block) []string {
+	var types []string
+	for _, block := range blocks {
+		types = append(types, string(block.BlockType()))
+	}
+	return types
+}
+
+func TestRenderKudoBlocks_Variants(t *testing.T) {
+	kudo := &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222", "U333333"},
+		TypeEmoji:    ":trophy:",
+		TypeText:     "Conquista do Time",
+		Message:      "Parabéns pelo \"lançamento\"!\nValeu <time>",
+		Points:       10,
+	}
+
+	tests := []struct {
+		variant   string
+		wantTypes string
+	}{
+		{variant: "classic", wantTypes: "header section divider section context section divider context"},
+		{variant: "compact", wantTypes: "section section context"},
+		{variant: "avatars", wantTypes: "header section context context divider section context"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			tmpl, err := templates.KudoTemplate(tt.variant)
+			if err != nil {
+				t.Fatalf("KudoTemplate() unexpected error = %v", err)
+			}
+			cfg := &config.Config{SlackAPI: &MockSlackClient{}, KudoTemplate: tmpl}
+
+			blocks, err := RenderKudoBlocks(context.Background(), kudo, cfg)
+			if err != nil {
+				t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+			}
+			if got := strings.Join(blockTypes(blocks), " "); got != tt.wantTypes {
+				t.Errorf("block types = %s, want %s", got, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestRenderKudoBlocks_Avatars(t *testing.T) {
+	tmpl, err := templates.KudoTemplate("avatars")
+	if err != nil {
+		t.Fatalf("KudoTemplate() unexpected error = %v", err)
+	}
+	lookups := 0
+	cfg := &config.Config{
+		KudoTemplate: tmpl,
+		SlackAPI: &MockSlackClient{
+			GetUserInfoFunc: func(user string) (*slack.User, error) {
+				lookups++
+				if user == "U333333" {
+					return nil, errors.New("user_not_found")
+				}
+				return &slack.User{ID: user, RealName: "Maria Souza", Profile: slack.UserProfile{
+					DisplayName: "maria",
+					Image72:     "https://avatars.example.com/maria.png",
+				}}, nil
+			},
+		},
+	}
+
+	blocks, err := RenderKudoBlocks(context.Background(), &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222", "U333333"},
+		Message:      "Valeu!",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+	}
+	if lookups != 2 {
+		t.Errorf("looked up %d users, want the 2 recipients", lookups)
+	}
+
+	// A recipient with a profile gets the avatar and display name
+	found := blocks[2].(*slack.ContextBlock).ContextElements.Elements
+	if len(found) != 3 {
+		t.Fatalf("elements = %+v, want both recipients in one row", found)
+	}
+	image, ok := found[0].(*slack.ImageBlockElement)
+	if !ok || image.ImageURL != "https://avatars.example.com/maria.png" || image.AltText != "maria" {
+		t.Errorf("first element = %+v, want the avatar of maria", found[0])
+	}
+	if text := found[1].(*slack.TextBlockObject).Text; text != "*maria* <@U222222>" {
+		t.Errorf("recipient text = %q, want *maria* <@U222222>", text)
+	}
+
+	// A recipient whose profile can't be loaded is only mentioned
+	if text := found[2].(*slack.TextBlockObject).Text; text != "<@U333333>" {
+		t.Errorf("recipient text = %q, want only the mention of U333333", text)
+	}
+}
+
+func TestRenderKudoBlocks_AvatarsManyRecipients(t *testing.T) {
+	tmpl, err := templates.KudoTemplate("avatars")
+	if err != nil {
+		t.Fatalf("KudoTemplate() unexpected error = %v", err)
+	}
+	cfg := &config.Config{
+		KudoTemplate: tmpl,
+		SlackAPI: &MockSlackClient{
+			GetUserInfoFunc: func(user string) (*slack.User, error) {
+				return &slack.User{ID: user, Profile: slack.UserProfile{DisplayName: "pessoa", Image72: "https://avatars.example.com/x.png"}}, nil
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		recipients int
+		rows       int
+		more       string
+	}{
+		{name: "one row", recipients: 5, rows: 1},
+		{name: "partial last row", recipients: 12, rows: 3},
+		{name: "every row", recipients: 40, rows: 8},
+		{name: "more than 50", recipients: 60, rows: 8, more: "+20: <@U0040>, <@U0041>"},
+		{name: "more than can be mentioned", recipients: 200, rows: 8, more: "<@U0189> e mais 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kudo := &models.Kudo{SenderID: "U111111", Points: 5, Message: "Valeu!"}
+			for i := range tt.recipients {
+				kudo.RecipientIDs = append(kudo.RecipientIDs, fmt.Sprintf("U%04d", i))
+			}
+
+			blocks, err := RenderKudoBlocks(context.Background(), kudo, cfg)
+			if err != nil {
+				t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+			}
+			if len(blocks) > 50 {
+				t.Fatalf("rendered %d blocks, want at most Slack's 50", len(blocks))
+			}
+
+			rows := 0
+			more := ""
+			for _, block := range blocks[2:] {
+				row, ok := block.(*slack.ContextBlock)
+				if !ok || row.BlockID == "kudo_points" {
+					break
+				}
+				elements := row.ContextElements.Elements
+				if len(elements) > 10 {
+					t.Errorf("context block has %d elements, want at most 10", len(elements))
+				}
+				if _, ok := elements[0].(*slack.ImageBlockElement); ok {
+					rows++
+				} else {
+					more = elements[0].(*slack.TextBlockObject).Text
+				}
+			}
+			if rows != tt.rows {
+				t.Errorf("rendered %d recipient rows, want %d", rows, tt.rows)
+			}
+			if !strings.Contains(more, tt.more) || (tt.more == "") != (more == "") {
+				t.Errorf("more recipients = %q, want it to contain %q", more, tt.more)
+			}
+		})
+	}
+}
+
+func TestRenderKudoBlocks_HostileNames(t *testing.T) {
+	models.KudoThemes["teste-imagem"] = models.KudoTheme{ImageURL: "https://example.com/trofeu.png"}
+	t.Cleanup(func() { delete(models.KudoThemes, "teste-imagem") })
+
+	tmpl, err := templates.KudoTemplate("avatars")
+	if err != nil {
+		t.Fatalf("KudoTemplate() unexpected error = %v", err)
+	}
+	cfg := &config.Config{
+		KudoTemplate: tmpl,
+		SlackAPI: &MockSlackClient{
+			GetUserInfoFunc: func(user string) (*slack.User, error) {
+				return &slack.User{ID: user, Profile: slack.UserProfile{
+					DisplayName: "<!channel> <https://evil.example.com|RH>",
+					Image72:     "https://avatars.example.com/x.png",
+				}}, nil
+			},
+		},
+	}
+
+	blocks, err := RenderKudoBlocks(context.Background(), &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222"},
+		TypeID:       "teste-imagem",
+		TypeText:     "P&D <Dados>",
+		Message:      "Valeu!",
+	}, cfg)
+	if err != nil {
+		t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+	}
+
+	// plain_text fields show the names as typed
+	if image := blocks[1].(*slack.ImageBlock); image.AltText != "P&D <Dados>" {
+		t.Errorf("type image alt_text = %q, want the type as typed", image.AltText)
+	}
+	elements := blocks[3].(*slack.ContextBlock).ContextElements.Elements
+	if avatar := elements[0].(*slack.ImageBlockElement); avatar.AltText != "<!channel> <https://evil.example.com|RH>" {
+		t.Errorf("avatar alt_text = %q, want the display name as typed", avatar.AltText)
+	}
+
+	// mrkdwn fields can't notify the channel or disguise a link
+	if text := blocks[2].(*slack.SectionBlock).Text.Text; !strings.Contains(text, "*P&amp;D &lt;Dados&gt;*") {
+		t.Errorf("type text = %q, want it escaped", text)
+	}
+	want := "*&lt;!channel&gt; &lt;https://evil.example.com|RH&gt;* <@U222222>"
+	if text := elements[1].(*slack.TextBlockObject).Text; text != want {
+		t.Errorf("recipient text = %q, want %q", text, want)
+	}
+}
+
+func TestRenderKudoBlocks_ClassicSkipsLookups(t *testing.T) {
+	cfg := &config.Config{
+		SlackAPI: &MockSlackClient{
+			GetUserInfoFunc: func(user string) (*slack.User, error) {
+				t.Errorf("unexpected users.info lookup of %s", user)
+				return nil, errors.New("unexpected")
+			},
+		},
+	}
+
+	if _, err := RenderKudoBlocks(context.Background(), &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}}, cfg); err != nil {
+		t.Errorf("RenderKudoBlocks() unexpected error = %v", err)
+	}
+}
+
+func TestRenderKudoBlocks_CustomTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{
+			name: "valid template",
+			text: `[{"type": "section", "text": {"type": "mrkdwn", "text": {{json (printf "%s %s" .Sender.Mention .Footer)}}}}]`,
+		},
+		{
+			name:    "unknown field",
+			text:    `[{"type": "section", "text": {"type": "mrkdwn", "text": {{json .Nope}}}}]`,
+			wantErr: "error rendering",
+		},
+		{
+			name:    "not JSON",
+			text:    `Parabéns {{.Sender.Mention}}`,
+			wantErr: "did not render Block Kit JSON",
+		},
+		{
+			name:    "no blocks",
+			text:    `[]`,
+			wantErr: "rendered no blocks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := templates.ParseKudoTemplate("custom", tt.text)
+			if err != nil {
+				t.Fatalf("ParseKudoTemplate() unexpected error = %v", err)
+			}
+			cfg := &config.Config{SlackAPI: &MockSlackClient{}, KudoTemplate: tmpl, KudoFooters: []string{"Valeu, time!"}}
+
+			blocks, err := RenderKudoBlocks(context.Background(), &models.Kudo{SenderID: "U111111"}, cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RenderKudoBlocks() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+			}
+			if text := blocks[0].(*slack.SectionBlock).Text.Text; text != "<@U111111> Valeu, time!" {
+				t.Errorf("text = %q, want <@U111111> Valeu, time!", text)
+			}
+		})
+	}
+}
+
+func TestPickFooter(t *testing.T) {
+	footers := []string{"um", "dois", "três"}
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	seen := map[string]bool{}
+	for i := range len(footers) {
+		seen[pickFooter(footers, base.Add(time.Duration(i)*time.Second))] = true
+	}
+	if len(seen) != len(footers) {
+		t.Errorf("footers used = %v, want all %d to rotate", seen, len(footers))
+	}
+
+	if footer := pickFooter(nil, base); footer == "" {
+		t.Error("pickFooter() with no footers should use the defaults")
+	}
+}
+
+func TestPostKudos_TemplateFallback(t *testing.T) {
+	tmpl, err := templates.ParseKudoTemplate("broken", `{{json .Nope}}`)
+	if err != nil {
+		t.Fatalf("ParseKudoTemplate() unexpected error = %v", err)
+	}
+
+	var posted int
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		KudoTemplate:   tmpl,
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				posted++
+				return channelID, "1234567890.123456", nil
+			},
+		},
+	}
+
+	// A template that fails to render doesn't lose the kudo
+	if err := PostKudos(context.Background(), &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Message: "Valeu!"}, cfg); err != nil {
+		t.Fatalf("PostKudos() unexpected error = %v", err)
+	}
+	if posted != 1 {
+		t.Errorf("posted %d messages, want 1", posted)
+	}
+}
+
+func TestRenderKudoBlocks_Themes(t *testing.T) {
+	models.KudoThemes["teste-imagem"] = models.KudoTheme{ImageURL: "https://example.com/trofeu.png", Banner: "🎊"}
+	t.Cleanup(func() { delete(models.KudoThemes, "teste-imagem") })
+
+	tests := []struct {
+		name       string
+		variant    string
+		typeID     string
+		wantTypes  string
+		wantHeader string
+		wantImage  string // accessory of the compact layout
+		wantFooter string
+	}{
+		{
+			name:       "type without theme",
+			variant:    "classic",
+			typeID:     "espirito-de-equipe",
+			wantTypes:  "header section divider section section divider context",
+			wantHeader: "🎉 Novo Elogio! 🎉",
+		},
+		{
+			name:       "banner, header and footer",
+			variant:    "classic",
+			typeID:     "conquista-do-time",
+			wantTypes:  "header section section divider section section divider context",
+			wantHeader: "🏆 Conquista do Time! 🏆",
+			wantFooter: "✨ _Sucesso de todos nós!_ ✨",
+		},
+		{
+			name:       "image wins over banner",
+			variant:    "classic",
+			typeID:     "teste-imagem",
+			wantTypes:  "header image section divider section section divider context",
+			wantHeader: "🎉 Novo Elogio! 🎉",
+		},
+		{
+			name:       "avatars layout",
+			variant:    "avatars",
+			typeID:     "conquista-do-time",
+			wantTypes:  "header section section context divider section context",
+			wantHeader: "🏆 Conquista do Time! 🏆",
+			wantFooter: "✨ _Sucesso de todos nós!_ ✨",
+		},
+		{
+			name:       "compact layout",
+			variant:    "compact",
+			typeID:     "conquista-do-time",
+			wantTypes:  "header section context section context",
+			wantHeader: "🏆 Conquista do Time! 🏆",
+			wantFooter: "✨ _Sucesso de todos nós!_",
+		},
+		{
+			name:      "compact image as accessory",
+			variant:   "compact",
+			typeID:    "teste-imagem",
+			wantTypes: "section section context",
+			wantImage: "https://example.com/trofeu.png",
+		},
+		{
+			name:       "theme of an official type added by an admin",
+			variant:    "classic",
+			typeID:     "rockstar-do-deploy",
+			wantTypes:  "header image section divider section section divider context",
+			wantHeader: "🎸 Rockstar! 🎸",
+			wantFooter: "✨ _Deploy sem sustos_ ✨",
+		},
+		{
+			name:       "compact theme of an official type added by an admin",
+			variant:    "compact",
+			typeID:     "rockstar-do-deploy",
+			wantTypes:  "header section section context",
+			wantHeader: "🎸 Rockstar! 🎸",
+			wantImage:  "https://example.com/guitarra.png",
+		},
+	}
+
+	store := storage.NewMemoryStore()
+	store.SaveKudoType(context.Background(), &models.KudoType{
+		ID: "rockstar-do-deploy", Emoji: ":guitar:", Name: "Rockstar do Deploy",
+		Theme: models.KudoTheme{Header: "🎸 Rockstar! 🎸", ImageURL: "https://example.com/guitarra.png", Footer: "Deploy sem sustos"},
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := templates.KudoTemplate(tt.variant)
+			if err != nil {
+				t.Fatalf("KudoTemplate() unexpected error = %v", err)
+			}
+			cfg := &config.Config{SlackAPI: &MockSlackClient{}, KudoTemplate: tmpl, Store: store}
+
+			blocks, err := RenderKudoBlocks(context.Background(), &models.Kudo{
+				SenderID:     "U111111",
+				RecipientIDs: []string{"U222222"},
+				TypeID:       tt.typeID,
+				TypeEmoji:    ":trophy:",
+				TypeText:     "Conquista do Time",
+				Message:      "Parabéns!",
+			}, cfg)
+			if err != nil {
+				t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+			}
+
+			if got := strings.Join(blockTypes(blocks), " "); got != tt.wantTypes {
+				t.Fatalf("block types = %s, want %s", got, tt.wantTypes)
+			}
+			if tt.wantHeader != "" {
+				if header := blocks[0].(*slack.HeaderBlock).Text.Text; header != tt.wantHeader {
+					t.Errorf("header = %q, want %q", header, tt.wantHeader)
+				}
+			}
+			if tt.wantImage != "" {
+				section, _ := blocks[len(blocks)-3].(*slack.SectionBlock)
+				if section == nil || section.Accessory == nil || section.Accessory.ImageElement == nil || section.Accessory.ImageElement.ImageURL != tt.wantImage {
+					t.Errorf("first section = %+v, want an image accessory %q", section, tt.wantImage)
+				}
+			}
+			if tt.wantFooter != "" {
+				elements := blocks[len(blocks)-1].(*slack.ContextBlock).ContextElements.Elements
+				if footer := elements[len(elements)-1].(*slack.TextBlockObject).Text; footer != tt.wantFooter {
+					t.Errorf("footer = %q, want %q", footer, tt.wantFooter)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderKudoBlocks_RichMessage(t *testing.T) {
+	_, rich := ParseMessageInput(slack.BlockAction{RichTextValue: richText(t, `
+		{"type": "rich_text_section", "elements": [{"type": "text", "text": "Passos:\n"}]},
+		{"type": "rich_text_list", "style": "ordered", "elements": [{"type": "rich_text_section", "elements": [{"type": "text", "text": "Deploy"}]}]}`)})
+
+	tests := []struct {
+		variant string
+		index   int
+	}{
+		{variant: "classic", index: 4},
+		{variant: "compact", index: 1},
+		{variant: "avatars", index: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			tmpl, err := templates.KudoTemplate(tt.variant)
+			if err != nil {
+				t.Fatalf("KudoTemplate() unexpected error = %v", err)
+			}
+			cfg := &config.Config{SlackAPI: &MockSlackClient{}, KudoTemplate: tmpl}
+
+			blocks, err := RenderKudoBlocks(context.Background(), &models.Kudo{
+				SenderID:        "U111111",
+				RecipientIDs:    []string{"U222222"},
+				Message:         "Passos:\n1. Deploy",
+				MessageRichText: rich,
+			}, cfg)
+			if err != nil {
+				t.Fatalf("RenderKudoBlocks() unexpected error = %v", err)
+			}
+
+			block, ok := blocks[tt.index].(*slack.RichTextBlock)
+			if !ok {
+				t.Fatalf("block %d = %s, want rich_text", tt.index, blocks[tt.index].BlockType())
+			}
+			if block.BlockID != "kudo_message" || len(block.Elements) != 2 {
+				t.Errorf("rich text block = %+v, want the quoted paragraph and the list", block)
+			}
+		})
+	}
+}
@@ -0,0 +1,501 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// Action IDs of the buttons asking admins about a popular custom kudo type
+const (
+	PromoteKudoTypeAction = "promote_kudo_type"
+	MergeKudoTypeAction   = "merge_kudo_type"
+	DismissKudoTypeAction = "dismiss_kudo_type"
+)
+
+// Callback IDs of the modals opened by the promote and merge buttons
+const (
+	PromoteKudoTypeCallbackID = "promote_kudo_type"
+	MergeKudoTypeCallbackID   = "merge_kudo_type"
+)
+
+// ErrKudoTypeDecided is returned when another admin already answered about the custom type
+var ErrKudoTypeDecided = errors.New("kudo type already decided")
+
+// ErrKudoTypeExists is returned when a promoted type would take the ID of an official type
+var ErrKudoTypeExists = errors.New("kudo type already exists")
+
+// ErrUnknownKudoType is returned when a custom type is merged into a type that doesn't exist
+var ErrUnknownKudoType = errors.New("unknown kudo type")
+
+// IsKudoTypePromotionAction reports whether the action ID belongs to a kudo type promotion button
+func IsKudoTypePromotionAction(actionID string) bool {
+	switch actionID {
+	case PromoteKudoTypeAction, MergeKudoTypeAction, DismissKudoTypeAction:
+		return true
+	}
+	return false
+}
+
+// IsKudoTypePromotionView reports whether a submitted modal is a promote or merge modal
+func IsKudoTypePromotionView(callbackID string) bool {
+	return callbackID == PromoteKudoTypeCallbackID || callbackID == MergeKudoTypeCallbackID
+}
+
+// PromotionMessage is the admin DM asking about a custom type, kept in the private_metadata
+// of the modals so the DM can be updated once they're submitted
+type PromotionMessage struct {
+	CustomTypeID string `json:"custom_type_id"`
+	ChannelID    string `json:"channel_id"`
+	Timestamp    string `json:"ts"`
+}
+
+// ParsePromotionMessage reads the private_metadata of a promote or merge modal
+func ParsePromotionMessage(metadata string) (PromotionMessage, error) {
+	var message PromotionMessage
+	if err := json.Unmarshal([]byte(metadata), &message); err != nil {
+		return message, fmt.Errorf("error parsing private metadata: %w", err)
+	}
+	if message.CustomTypeID == "" {
+		return message, fmt.Errorf("private metadata has no custom type")
+	}
+	return message, nil
+}
+
+// ResolvedKudoType returns the official type a custom type was promoted to or merged into, or nil
+// Failures are logged and keep the custom type
+func ResolvedKudoType(ctx context.Context, kudoType *models.CustomKudoType, cfg *config.Config) *models.KudoType {
+	if kudoType == nil || !kudoType.Resolved() {
+		return nil
+	}
+	official, err := KudoTypeByID(ctx, kudoType.ResolvedTypeID, cfg)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load resolved kudo type", "kudo_type", kudoType.ResolvedTypeID, "error", err)
+		return nil
+	}
+	return official
+}
+
+// OfficialKudoTypeFor returns the official type replacing the workspace's custom type with the name, or nil
+func OfficialKudoTypeFor(ctx context.Context, teamID, name string, cfg *config.Config) *models.KudoType {
+	if cfg.Store == nil || teamID == "" {
+		return nil
+	}
+	kudoType, err := cfg.Store.GetCustomKudoType(ctx, CustomKudoTypeID(teamID, name))
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load custom kudo type", "team_id", teamID, "error", err)
+		return nil
+	}
+	return ResolvedKudoType(ctx, kudoType, cfg)
+}
+
+// SuggestKudoTypePromotion asks the admins about a custom type once it's used in cfg.PromoteAfter kudos
+// Each admin gets a DM with buttons to promote, merge or dismiss it, and the type isn't suggested again
+func SuggestKudoTypePromotion(ctx context.Context, kudoType *models.CustomKudoType, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.SuggestKudoTypePromotion")
+	defer span.End()
+
+	if cfg.Store == nil || cfg.PromoteAfter <= 0 {
+		return nil
+	}
+	if kudoType == nil || kudoType.Status != "" || kudoType.UsageCount < cfg.PromoteAfter {
+		return nil
+	}
+	admins := AdminUserIDs(ctx, cfg)
+	if len(admins) == 0 {
+		return nil
+	}
+
+	// Only the kudo that moves the type out of its initial state asks the admins, concurrent ones stop here
+	kudoType, err := cfg.Store.UpdateCustomKudoTypeStatus(ctx, kudoType.ID, storage.CustomKudoTypeDecision{To: models.CustomKudoTypeSuggested})
+	if err != nil {
+		return fmt.Errorf("error saving custom kudo type: %w", err)
+	}
+	if kudoType == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, adminID := range admins {
+		_, _, err := cfg.SlackAPI.PostMessageContext(ctx,
+			adminID,
+			slack.MsgOptionBlocks(FormatKudoTypePromotionBlocks(kudoType)...),
+			slack.MsgOptionText(fmt.Sprintf("O tipo de elogio %s já foi usado em %d elogios", EscapeMrkdwn(kudoType.Name), kudoType.UsageCount), false),
+		)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to ask %s about kudo type %s: %w", adminID, kudoType.ID, err))
+		}
+	}
+
+	slog.InfoContext(ctx, "Kudo type promotion suggested", "custom_type_id", kudoType.ID, "usage_count", kudoType.UsageCount)
+	return errors.Join(errs...)
+}
+
+// FormatKudoTypePromotionBlocks creates the DM asking admins about a popular custom type
+func FormatKudoTypePromotionBlocks(kudoType *models.CustomKudoType) []slack.Block {
+	text := fmt.Sprintf("📈 O tipo de elogio personalizado *%s %s* já foi usado em *%d* elogios.\n\nQuer torná-lo um tipo oficial, mesclá-lo com um tipo existente ou dispensar a sugestão?",
+		EscapeMrkdwn(kudoType.Emoji), EscapeMrkdwn(kudoType.Name), kudoType.UsageCount)
+
+	promote := slack.NewButtonBlockElement(
+		PromoteKudoTypeAction,
+		kudoType.ID,
+		&slack.TextBlockObject{Type: slack.PlainTextType, Text: "⭐ Promover"},
+	)
+	promote.Style = slack.StylePrimary
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: text},
+			nil,
+			nil,
+		),
+		slack.NewContextBlock("",
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: fmt.Sprintf("Criado por <@%s>", kudoType.CreatedBy)},
+		),
+		slack.NewActionBlock("kudo_type_promotion_actions",
+			promote,
+			slack.NewButtonBlockElement(
+				MergeKudoTypeAction,
+				kudoType.ID,
+				&slack.TextBlockObject{Type: slack.PlainTextType, Text: "🔀 Mesclar"},
+			),
+			slack.NewButtonBlockElement(
+				DismissKudoTypeAction,
+				kudoType.ID,
+				&slack.TextBlockObject{Type: slack.PlainTextType, Text: "🙈 Dispensar"},
+			),
+		),
+	}
+}
+
+// FormatKudoTypeDecisionBlocks creates the message replacing the admin DM once the custom type is decided
+// officialName is the name of the type it was promoted to or merged into
+func FormatKudoTypeDecisionBlocks(kudoType *models.CustomKudoType, officialName string) []slack.Block {
+	name := EscapeMrkdwn(kudoType.Emoji + " " + kudoType.Name)
+	var text string
+	switch kudoType.Status {
+	case models.CustomKudoTypePromoted:
+		text = fmt.Sprintf("⭐ *%s* agora é um tipo de elogio oficial, promovido por <@%s>.", name, kudoType.ResolvedBy)
+	case models.CustomKudoTypeMerged:
+		text = fmt.Sprintf("🔀 *%s* foi mesclado em *%s* por <@%s>. Os próximos elogios usarão esse tipo.", name, EscapeMrkdwn(officialName), kudoType.ResolvedBy)
+	case models.CustomKudoTypeDismissed:
+		text = fmt.Sprintf("🙈 Sugestão de *%s* dispensada por <@%s>. Ele continua disponível como tipo personalizado.", name, kudoType.ResolvedBy)
+	default:
+		text = fmt.Sprintf("*%s* não aguarda mais uma decisão.", name)
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: text},
+			nil,
+			nil,
+		),
+	}
+}
+
+// AnswerKudoTypePromotion handles a promote, merge or dismiss button of an admin DM
+// Promoting and merging open a modal to complete the decision, dismissing updates the DM right away
+// When the type was already decided the DM shows that decision instead
+func AnswerKudoTypePromotion(ctx context.Context, actionID, triggerID, adminID string, message PromotionMessage, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.AnswerKudoTypePromotion")
+	defer span.End()
+
+	kudoType, err := loadCustomKudoType(ctx, message.CustomTypeID, cfg)
+	if err != nil {
+		return err
+	}
+	if kudoType.Status != models.CustomKudoTypeSuggested {
+		return UpdateKudoTypePromotionMessage(ctx, message, kudoType, cfg)
+	}
+
+	switch actionID {
+	case PromoteKudoTypeAction:
+		view, err := PromoteKudoTypeView(kudoType, message)
+		if err != nil {
+			return err
+		}
+		return OpenModal(ctx, triggerID, view, cfg)
+	case MergeKudoTypeAction:
+		officials, err := OfficialKudoTypes(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		view, err := MergeKudoTypeView(kudoType, officials, message)
+		if err != nil {
+			return err
+		}
+		return OpenModal(ctx, triggerID, view, cfg)
+	case DismissKudoTypeAction:
+		return DismissKudoType(ctx, message, adminID, now, cfg)
+	default:
+		return fmt.Errorf("unknown kudo type promotion action %q", actionID)
+	}
+}
+
+// PromoteKudoType adds a custom type to the official catalog with the fields filled by an admin
+// The ID is derived from the name, the emoji defaults to the custom type's when none is picked
+func PromoteKudoType(ctx context.Context, message PromotionMessage, promoted *models.KudoType, adminID string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.PromoteKudoType")
+	defer span.End()
+
+	kudoType, err := pendingCustomKudoType(ctx, message, cfg)
+	if err != nil {
+		return err
+	}
+
+	promoted.ID = KudoTypeSlug(promoted.Name)
+	if promoted.ID == "" || promoted.ID == "custom" {
+		return ErrKudoTypeExists
+	}
+	existing, err := KudoTypeByID(ctx, promoted.ID, cfg)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrKudoTypeExists
+	}
+	if !emojiValuePattern.MatchString(promoted.Emoji) {
+		promoted.Emoji = kudoType.Emoji
+	}
+	promoted.PromotedFrom = kudoType.ID
+	promoted.CreatedBy = adminID
+	promoted.CreatedAt = now
+
+	if err := cfg.Store.SaveKudoType(ctx, promoted); err != nil {
+		return fmt.Errorf("error saving kudo type: %w", err)
+	}
+	err = resolveCustomKudoType(ctx, message, kudoType, models.CustomKudoTypePromoted, promoted.ID, adminID, now, cfg)
+	if errors.Is(err, ErrKudoTypeDecided) {
+		// Another admin decided first, so the official type isn't kept
+		if err := cfg.Store.DeleteKudoType(ctx, promoted.ID); err != nil {
+			slog.ErrorContext(ctx, "Could not remove the official type of a decided custom type", "kudo_type", promoted.ID, "error", err)
+		}
+		return err
+	}
+	RecordAudit(ctx, models.AuditKudoTypeAdded, adminID, promoted.ID, nil, promoted, cfg)
+	slog.InfoContext(ctx, "Kudo type promoted", "custom_type_id", kudoType.ID, "kudo_type", promoted.ID)
+	return err
+}
+
+// MergeKudoType makes the next kudos of a custom type use an existing official type
+func MergeKudoType(ctx context.Context, message PromotionMessage, targetID, adminID string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.MergeKudoType")
+	defer span.End()
+
+	kudoType, err := pendingCustomKudoType(ctx, message, cfg)
+	if err != nil {
+		return err
+	}
+	target, err := KudoTypeByID(ctx, targetID, cfg)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrUnknownKudoType
+	}
+
+	slog.InfoContext(ctx, "Kudo type merged", "custom_type_id", kudoType.ID, "kudo_type", target.ID)
+	return resolveCustomKudoType(ctx, message, kudoType, models.CustomKudoTypeMerged, target.ID, adminID, now, cfg)
+}
+
+// DismissKudoType keeps a custom type as it is, admins aren't asked about it again
+func DismissKudoType(ctx context.Context, message PromotionMessage, adminID string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.DismissKudoType")
+	defer span.End()
+
+	kudoType, err := pendingCustomKudoType(ctx, message, cfg)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "Kudo type promotion dismissed", "custom_type_id", kudoType.ID)
+	return resolveCustomKudoType(ctx, message, kudoType, models.CustomKudoTypeDismissed, "", adminID, now, cfg)
+}
+
+// UpdateKudoTypePromotionMessage replaces the buttons of an admin DM with the decision about the custom type
+func UpdateKudoTypePromotionMessage(ctx context.Context, message PromotionMessage, kudoType *models.CustomKudoType, cfg *config.Config) error {
+	if message.ChannelID == "" || message.Timestamp == "" {
+		return nil
+	}
+
+	officialName := ""
+	if official := ResolvedKudoType(ctx, kudoType, cfg); official != nil {
+		officialName = official.Emoji + " " + official.Name
+	}
+
+	_, _, _, err := cfg.SlackAPI.UpdateMessageContext(ctx,
+		message.ChannelID,
+		message.Timestamp,
+		slack.MsgOptionBlocks(FormatKudoTypeDecisionBlocks(kudoType, officialName)...),
+		slack.MsgOptionText("Sugestão de tipo de elogio respondida", false),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update kudo type promotion message: %w", err)
+	}
+	return nil
+}
+
+// loadCustomKudoType loads the custom type an admin DM is about
+func loadCustomKudoType(ctx context.Context, id string, cfg *config.Config) (*models.CustomKudoType, error) {
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("no store to load custom kudo type %s", id)
+	}
+	kudoType, err := cfg.Store.GetCustomKudoType(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading custom kudo type: %w", err)
+	}
+	if kudoType == nil {
+		return nil, fmt.Errorf("custom kudo type %s not found", id)
+	}
+	return kudoType, nil
+}
+
+// pendingCustomKudoType loads a custom type still waiting for a decision
+// When another admin already decided, their DM is updated and ErrKudoTypeDecided is returned
+func pendingCustomKudoType(ctx context.Context, message PromotionMessage, cfg *config.Config) (*models.CustomKudoType, error) {
+	kudoType, err := loadCustomKudoType(ctx, message.CustomTypeID, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if kudoType.Status != models.CustomKudoTypeSuggested {
+		if err := UpdateKudoTypePromotionMessage(ctx, message, kudoType, cfg); err != nil {
+			slog.WarnContext(ctx, "Could not update kudo type promotion message", "error", err)
+		}
+		return nil, ErrKudoTypeDecided
+	}
+	return kudoType, nil
+}
+
+// decisionAuditActions maps the admins' decisions to their audit actions
+var decisionAuditActions = map[string]string{
+	models.CustomKudoTypePromoted:  models.AuditKudoTypePromoted,
+	models.CustomKudoTypeMerged:    models.AuditKudoTypeMerged,
+	models.CustomKudoTypeDismissed: models.AuditKudoTypeDismissed,
+}
+
+// resolveCustomKudoType records the admin's decision and updates their DM
+// The type must still be waiting for a decision when it's saved, otherwise ErrKudoTypeDecided is returned
+func resolveCustomKudoType(ctx context.Context, message PromotionMessage, kudoType *models.CustomKudoType, status, resolvedTypeID, adminID string, now time.Time, cfg *config.Config) error {
+	before := *kudoType
+	resolved, err := cfg.Store.UpdateCustomKudoTypeStatus(ctx, kudoType.ID, storage.CustomKudoTypeDecision{
+		From:           models.CustomKudoTypeSuggested,
+		To:             status,
+		ResolvedTypeID: resolvedTypeID,
+		ResolvedBy:     adminID,
+		ResolvedAt:     now,
+	})
+	if err != nil {
+		return fmt.Errorf("error saving custom kudo type: %w", err)
+	}
+	if resolved == nil {
+		// Another admin decided since the type was loaded, their decision is shown instead
+		if _, err := pendingCustomKudoType(ctx, message, cfg); err != nil {
+			return err
+		}
+		return ErrKudoTypeDecided
+	}
+	RecordAudit(ctx, decisionAuditActions[status], adminID, resolved.ID, before, resolved, cfg)
+	return UpdateKudoTypePromotionMessage(ctx, message, resolved, cfg)
+}
+
+// PromoteKudoTypeView builds the modal where an admin describes a custom type before promoting it
+func PromoteKudoTypeView(kudoType *models.CustomKudoType, message PromotionMessage) (string, error) {
+	emojiBlock := customEmojiBlock()
+	if emojiValuePattern.MatchString(kudoType.Emoji) {
+		element := emojiBlock["element"].(map[string]interface{})
+		element["initial_option"] = map[string]interface{}{
+			"text":  map[string]interface{}{"type": "plain_text", "text": kudoType.Emoji, "emoji": true},
+			"value": kudoType.Emoji,
+		}
+	}
+
+	blocks := []interface{}{
+		plainTextInput("kudo_type_name", "Nome", kudoType.Name, false, 150),
+		emojiBlock,
+		plainTextInput("kudo_type_description", "Descrição", "", false, 150),
+		plainTextInput("kudo_type_suggested_message", "Mensagem sugerida", "", true, 500),
+	}
+	return promotionView(PromoteKudoTypeCallbackID, "Promover tipo", "Promover", message, append(blocks, kudoThemeBlocks("Tema: ")...))
+}
+
+// MergeKudoTypeView builds the modal where an admin picks the official type replacing a custom type
+func MergeKudoTypeView(kudoType *models.CustomKudoType, officials []*models.KudoType, message PromotionMessage) (string, error) {
+	if len(officials) > maxKudoTypeOptions {
+		officials = officials[:maxKudoTypeOptions]
+	}
+	options := make([]interface{}, 0, len(officials))
+	for _, official := range officials {
+		options = append(options, kudoTypeOption(official.Emoji+" "+official.Name, official.ID))
+	}
+
+	return promotionView(MergeKudoTypeCallbackID, "Mesclar tipo", "Mesclar", message, []interface{}{
+		map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("Os próximos elogios de *%s* usarão o tipo escolhido.", EscapeMrkdwn(kudoType.Emoji+" "+kudoType.Name)),
+			},
+		},
+		map[string]interface{}{
+			"type":     "input",
+			"block_id": "kudo_type_target",
+			"label":    map[string]interface{}{"type": "plain_text", "text": "Tipo oficial", "emoji": true},
+			"element": map[string]interface{}{
+				"type":      "static_select",
+				"action_id": "kudo_type_target",
+				"options":   options,
+			},
+		},
+	})
+}
+
+// promotionView builds a promote or merge modal as a views.open template
+func promotionView(callbackID, title, submit string, message PromotionMessage, blocks []interface{}) (string, error) {
+	metadata, err := json.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling private metadata: %w", err)
+	}
+
+	view := map[string]interface{}{
+		"type":             "modal",
+		"callback_id":      callbackID,
+		"private_metadata": string(metadata),
+		"title":            map[string]interface{}{"type": "plain_text", "text": title, "emoji": true},
+		"submit":           map[string]interface{}{"type": "plain_text", "text": submit, "emoji": true},
+		"close":            map[string]interface{}{"type": "plain_text", "text": "Cancelar", "emoji": true},
+		"blocks":           blocks,
+	}
+	result, err := json.Marshal(map[string]interface{}{"view": view})
+	if err != nil {
+		return "", fmt.Errorf("error marshaling view: %w", err)
+	}
+	return string(result), nil
+}
+
+// plainTextInput is a required text input whose block and action share the ID
+func plainTextInput(id, label, initialValue string, multiline bool, maxLength int) map[string]interface{} {
+	element := map[string]interface{}{
+		"type":       "plain_text_input",
+		"action_id":  id,
+		"multiline":  multiline,
+		"max_length": maxLength,
+	}
+	if initialValue != "" {
+		element["initial_value"] = initialValue
+	}
+	return map[string]interface{}{
+		"type":     "input",
+		"block_id": id,
+		"label":    map[string]interface{}{"type": "plain_text", "text": label, "emoji": true},
+		"element":  element,
+	}
+}
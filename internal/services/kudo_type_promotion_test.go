@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestSuggestKudoTypePromotion(t *testing.T) {
+	tests := []struct {
+		name       string
+		usageCount int
+		status     string
+		promote    int
+		admins     []string
+		group      []string
+		postErr    error
+		wantAsked  []string
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "threshold reached", usageCount: 5, promote: 5, admins: []string{"UADMIN1", "UADMIN2"}, wantAsked: []string{"UADMIN1", "UADMIN2"}, wantStatus: models.CustomKudoTypeSuggested},
+		{name: "below threshold", usageCount: 4, promote: 5, admins: []string{"UADMIN1"}},
+		{name: "promotion disabled", usageCount: 50, promote: 0, admins: []string{"UADMIN1"}},
+		{name: "no admins", usageCount: 5, promote: 5},
+		{name: "admin group members", usageCount: 5, promote: 5, admins: []string{"UADMIN1"}, group: []string{"UADMIN1", "UADMIN3"}, wantAsked: []string{"UADMIN1", "UADMIN3"}, wantStatus: models.CustomKudoTypeSuggested},
+		{name: "already suggested", usageCount: 6, status: models.CustomKudoTypeSuggested, promote: 5, admins: []string{"UADMIN1"}, wantStatus: models.CustomKudoTypeSuggested},
+		{name: "dismissed", usageCount: 9, status: models.CustomKudoTypeDismissed, promote: 5, admins: []string{"UADMIN1"}, wantStatus: models.CustomKudoTypeDismissed},
+		{name: "DM fails", usageCount: 5, promote: 5, admins: []string{"UADMIN1"}, postErr: errors.New("user_not_found"), wantAsked: []string{"UADMIN1"}, wantStatus: models.CustomKudoTypeSuggested, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			kudoType := &models.CustomKudoType{ID: "T1_rock", TeamID: "T1", Emoji: ":guitar:", Name: "Rockstar", UsageCount: tt.usageCount, Status: tt.status}
+			store.SaveCustomKudoType(ctx, kudoType)
+
+			var asked []string
+			cfg := &config.Config{
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						asked = append(asked, channelID)
+						return channelID, "1234567890.123456", tt.postErr
+					},
+					GetUserGroupMembersFunc: func(userGroup string) ([]string, error) {
+						return tt.group, nil
+					},
+				},
+				Store:        store,
+				AdminUserIDs: tt.admins,
+				PromoteAfter: tt.promote,
+			}
+			if tt.group != nil {
+				cfg.AdminGroupID = "S0ADMINS"
+			}
+
+			err := SuggestKudoTypePromotion(ctx, kudoType, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SuggestKudoTypePromotion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(asked, tt.wantAsked) {
+				t.Errorf("asked %v, want %v", asked, tt.wantAsked)
+			}
+			saved, _ := store.GetCustomKudoType(ctx, kudoType.ID)
+			if saved.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", saved.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSuggestKudoTypePromotion_NilType(t *testing.T) {
+	cfg := &config.Config{Store: storage.NewMemoryStore(), AdminUserIDs: []string{"UADMIN1"}, PromoteAfter: 1}
+	if err := SuggestKudoTypePromotion(context.Background(), nil, cfg); err != nil {
+		t.Errorf("SuggestKudoTypePromotion() unexpected error = %v", err)
+	}
+}
+
+func TestSuggestKudoTypePromotion_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	id := CustomKudoTypeID("T1", "Rockstar")
+	store.SaveCustomKudoType(ctx, &models.CustomKudoType{ID: id, TeamID: "T1", Emoji: ":guitar:", Name: "Rockstar", UsageCount: 4})
+
+	var mu sync.Mutex
+	var asked []string
+	cfg := &config.Config{
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				asked = append(asked, channelID)
+				return channelID, "1234567890.123456", nil
+			},
+		},
+		Store:        store,
+		AdminUserIDs: []string{"UADMIN1"},
+		PromoteAfter: 5,
+	}
+
+	// The kudos counted 5th and 6th both pass the threshold, only one of them asks the admins
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			kudoType, err := SaveCustomKudoType(ctx, "T1", &models.Kudo{SenderID: "U111111", TypeEmoji: ":guitar:", TypeText: "Rockstar"}, cfg)
+			if err != nil {
+				t.Errorf("SaveCustomKudoType() unexpected error = %v", err)
+				return
+			}
+			if err := SuggestKudoTypePromotion(ctx, kudoType, cfg); err != nil {
+				t.Errorf("SuggestKudoTypePromotion() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !slices.Equal(asked, []string{"UADMIN1"}) {
+		t.Errorf("asked %v, want UADMIN1 once", asked)
+	}
+	saved, _ := store.GetCustomKudoType(ctx, id)
+	if saved.Status != models.CustomKudoTypeSuggested || saved.UsageCount != 6 {
+		t.Errorf("saved type = %+v, want it suggested with both uses counted", saved)
+	}
+}
+
+// decidedMeanwhileStore dismisses the custom type right before an admin's decision is saved
+type decidedMeanwhileStore struct {
+	*storage.MemoryStore
+}
+
+func (s decidedMeanwhileStore) UpdateCustomKudoTypeStatus(ctx context.Context, id string, decision storage.CustomKudoTypeDecision) (*models.CustomKudoType, error) {
+	s.MemoryStore.UpdateCustomKudoTypeStatus(ctx, id, storage.CustomKudoTypeDecision{
+		From:       models.CustomKudoTypeSuggested,
+		To:         models.CustomKudoTypeDismissed,
+		ResolvedBy: "UADMIN2",
+	})
+	s.MemoryStore.RecordCustomKudoTypeUse(ctx, &models.CustomKudoType{ID: id})
+	return s.MemoryStore.UpdateCustomKudoTypeStatus(ctx, id, decision)
+}
+
+func TestPromoteKudoType_DecidedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	store := decidedMeanwhileStore{storage.NewMemoryStore()}
+	store.SaveCustomKudoType(ctx, &models.CustomKudoType{ID: "T1_rock", TeamID: "T1", Emoji: ":guitar:", Name: "Rockstar", UsageCount: 5, Status: models.CustomKudoTypeSuggested})
+
+	var updated bool
+	cfg := &config.Config{
+		SlackAPI: &MockSlackClient{
+			UpdateMessageFunc: func(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+				updated = true
+				return channelID, timestamp, "", nil
+			},
+		},
+		Store: store,
+	}
+
+	message := PromotionMessage{CustomTypeID: "T1_rock", ChannelID: "D123456", Timestamp: "1234567890.123456"}
+	err := PromoteKudoType(ctx, message, &models.KudoType{Name: "Rockstar", Emoji: ":guitar:"}, "UADMIN1", time.Now(), cfg)
+	if !errors.Is(err, ErrKudoTypeDecided) {
+		t.Fatalf("PromoteKudoType() error = %v, want ErrKudoTypeDecided", err)
+	}
+	if !updated {
+		t.Error("DM not updated, want it to show the other admin's decision")
+	}
+
+	saved, _ := store.GetCustomKudoType(ctx, "T1_rock")
+	if saved.Status != models.CustomKudoTypeDismissed || saved.ResolvedBy != "UADMIN2" || saved.UsageCount != 6 {
+		t.Errorf("saved type = %+v, want the other admin's decision and the use recorded meanwhile", saved)
+	}
+	if official, _ := store.GetKudoType(ctx, "rockstar"); official != nil {
+		t.Errorf("official type = %+v, want it removed", official)
+	}
+	if entries, _ := store.ListAuditEntries(ctx, storage.AuditQuery{}); len(entries) != 0 {
+		t.Errorf("audit entries = %d, want none for a promotion that didn't happen", len(entries))
+	}
+}
+
+func TestFormatKudoTypePromotionBlocks(t *testing.T) {
+	kudoType := &models.CustomKudoType{ID: "T1_rock", Emoji: ":guitar:", Name: "<!channel> Rockstar", CreatedBy: "U111111", UsageCount: 7}
+
+	data, err := json.Marshal(FormatKudoTypePromotionBlocks(kudoType))
+	if err != nil {
+		t.Fatalf("error marshaling blocks: %v", err)
+	}
+	var blocks []struct {
+		Type     string                `json:"type"`
+		Text     struct{ Text string } `json:"text"`
+		Elements []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"elements"`
+	}
+	json.Unmarshal(data, &blocks)
+
+	if len(blocks) != 3 {
+		t.Fatalf("blocks = %d, want section, context and actions", len(blocks))
+	}
+	if !strings.Contains(blocks[0].Text.Text, "*7*") || !strings.Contains(blocks[0].Text.Text, "&lt;!channel&gt;") {
+		t.Errorf("text = %q, want the usage count and the escaped name", blocks[0].Text.Text)
+	}
+	var actions []string
+	for _, element := range blocks[2].Elements {
+		if element.Value != "T1_rock" {
+			t.Errorf("button %s value = %q, want the custom type ID", element.ActionID, element.Value)
+		}
+		actions = append(actions, element.ActionID)
+	}
+	if !slices.Equal(actions, []string{PromoteKudoTypeAction, MergeKudoTypeAction, DismissKudoTypeAction}) {
+		t.Errorf("buttons = %v, want promote, merge and dismiss", actions)
+	}
+}
+
+func TestFormatKudoTypeDecisionBlocks(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		officialName string
+		expected     string
+	}{
+		{name: "promoted", status: models.CustomKudoTypePromoted, expected: "agora é um tipo de elogio oficial, promovido por <@UADMIN1>"},
+		{name: "merged", status: models.CustomKudoTypeMerged, officialName: ":dart: Entrega Excepcional", expected: "foi mesclado em *:dart: Entrega Excepcional* por <@UADMIN1>"},
+		{name: "dismissed", status: models.CustomKudoTypeDismissed, expected: "dispensada por <@UADMIN1>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kudoType := &models.CustomKudoType{Emoji: ":guitar:", Name: "Rockstar", Status: tt.status, ResolvedBy: "UADMIN1"}
+			blocks := FormatKudoTypeDecisionBlocks(kudoType, tt.officialName)
+			section, ok := blocks[0].(*slack.SectionBlock)
+			if !ok || !strings.Contains(section.Text.Text, tt.expected) {
+				t.Errorf("blocks = %+v, want text containing %q", blocks, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKudoTypePromotionViews(t *testing.T) {
+	kudoType := &models.CustomKudoType{ID: "T1_rock", Emoji: ":guitar:", Name: "Rockstar"}
+	message := PromotionMessage{CustomTypeID: "T1_rock", ChannelID: "D123456", Timestamp: "1700000000.000001"}
+	officials, err := BuiltInKudoTypes()
+	if err != nil {
+		t.Fatalf("BuiltInKudoTypes() unexpected error = %v", err)
+	}
+
+	promote, err := PromoteKudoTypeView(kudoType, message)
+	if err != nil {
+		t.Fatalf("PromoteKudoTypeView() unexpected error = %v", err)
+	}
+	merge, err := MergeKudoTypeView(kudoType, officials, message)
+	if err != nil {
+		t.Fatalf("MergeKudoTypeView() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		view       string
+		callbackID string
+		blockIDs   []string
+	}{
+		{name: "promote", view: promote, callbackID: PromoteKudoTypeCallbackID, blockIDs: []string{"kudo_type_name", "kudo_emoji", "kudo_type_description", "kudo_type_suggested_message", "kudo_type_header", "kudo_type_image_url", "kudo_type_banner", "kudo_type_footer"}},
+		{name: "merge", view: merge, callbackID: MergeKudoTypeCallbackID, blockIDs: []string{"", "kudo_type_target"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request struct {
+				View struct {
+					CallbackID      string `json:"callback_id"`
+					PrivateMetadata string `json:"private_metadata"`
+					Blocks          []struct {
+						BlockID string `json:"block_id"`
+					} `json:"blocks"`
+				} `json:"view"`
+			}
+			if err := json.Unmarshal([]byte(tt.view), &request); err != nil {
+				t.Fatalf("error decoding view: %v", err)
+			}
+			if request.View.CallbackID != tt.callbackID || !IsKudoTypePromotionView(request.View.CallbackID) {
+				t.Errorf("callback_id = %q, want %q", request.View.CallbackID, tt.callbackID)
+			}
+			var blockIDs []string
+			for _, block := range request.View.Blocks {
+				blockIDs = append(blockIDs, block.BlockID)
+			}
+			if !slices.Equal(blockIDs, tt.blockIDs) {
+				t.Errorf("blocks = %v, want %v", blockIDs, tt.blockIDs)
+			}
+
+			parsed, err := ParsePromotionMessage(request.View.PrivateMetadata)
+			if err != nil || parsed != message {
+				t.Errorf("ParsePromotionMessage() = %+v, %v, want %+v", parsed, err, message)
+			}
+		})
+	}
+}
+
+func TestParsePromotionMessage_Invalid(t *testing.T) {
+	for _, metadata := range []string{"", "not json", `{"channel_id": "D123456"}`} {
+		if _, err := ParsePromotionMessage(metadata); err == nil {
+			t.Errorf("ParsePromotionMessage(%q) expected error, got nil", metadata)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/templates"
+)
+
+// maxKudoTypeOptions is the most options Slack accepts in a static select
+const maxKudoTypeOptions = 100
+
+// BuiltInKudoTypes returns the kudo types of the modal template, without "✏️ Outro..."
+func BuiltInKudoTypes() ([]*models.KudoType, error) {
+	var view struct {
+		View struct {
+			Blocks []struct {
+				BlockID string `json:"block_id"`
+				Element struct {
+					Options []struct {
+						Text  struct{ Text string } `json:"text"`
+						Value string                `json:"value"`
+					} `json:"options"`
+				} `json:"element"`
+			} `json:"blocks"`
+		} `json:"view"`
+	}
+	if err := json.Unmarshal([]byte(templates.GiveKudosViewTemplate), &view); err != nil {
+		return nil, fmt.Errorf("error parsing view template: %w", err)
+	}
+
+	var kudoTypes []*models.KudoType
+	for _, block := range view.View.Blocks {
+		if block.BlockID != "kudo_type" {
+			continue
+		}
+		for _, option := range block.Element.Options {
+			if option.Value == "custom" {
+				continue
+			}
+			emoji, name := ParseKudoTypeText(option.Text.Text)
+			kudoTypes = append(kudoTypes, &models.KudoType{
+				ID:               option.Value,
+				Emoji:            emoji,
+				Name:             name,
+				Description:      models.KudoDescriptions[option.Value],
+				SuggestedMessage: models.KudoSuggestedMessages[option.Value],
+				Theme:            models.KudoThemes[option.Value],
+			})
+		}
+	}
+	return kudoTypes, nil
+}
+
+// OfficialKudoTypes returns the built-in kudo types followed by the ones promoted by admins
+func OfficialKudoTypes(ctx context.Context, cfg *config.Config) ([]*models.KudoType, error) {
+	kudoTypes, err := BuiltInKudoTypes()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Store == nil {
+		return kudoTypes, nil
+	}
+
+	promoted, err := cfg.Store.ListKudoTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kudo types: %w", err)
+	}
+	return append(kudoTypes, promoted...), nil
+}
+
+// KudoTypeByID returns an official kudo type, or nil when there is none with the ID
+func KudoTypeByID(ctx context.Context, id string, cfg *config.Config) (*models.KudoType, error) {
+	builtIn, err := BuiltInKudoTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, kudoType := range builtIn {
+		if kudoType.ID == id {
+			return kudoType, nil
+		}
+	}
+	if cfg.Store == nil {
+		return nil, nil
+	}
+
+	kudoType, err := cfg.Store.GetKudoType(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading kudo type: %w", err)
+	}
+	return kudoType, nil
+}
+
+// KudoTypeDescription returns the description of an official kudo type, empty when it has none
+func KudoTypeDescription(ctx context.Context, id string, cfg *config.Config) string {
+	if description, ok := models.KudoDescriptions[id]; ok {
+		return description
+	}
+	if kudoType := promotedKudoType(ctx, id, cfg); kudoType != nil {
+		return kudoType.Description
+	}
+	return ""
+}
+
+// SuggestedKudoMessage returns the message suggested for an official kudo type, empty when it has none
+func SuggestedKudoMessage(ctx context.Context, id string, cfg *config.Config) string {
+	if message, ok := models.KudoSuggestedMessages[id]; ok {
+		return message
+	}
+	if kudoType := promotedKudoType(ctx, id, cfg); kudoType != nil {
+		return kudoType.SuggestedMessage
+	}
+	return ""
+}
+
+// KudoTypeTheme returns the theme of an official kudo type, empty when it has none
+func KudoTypeTheme(ctx context.Context, id string, cfg *config.Config) models.KudoTheme {
+	if theme, ok := models.KudoThemes[id]; ok {
+		return theme
+	}
+	if kudoType := promotedKudoType(ctx, id, cfg); kudoType != nil {
+		return kudoType.Theme
+	}
+	return models.KudoTheme{}
+}
+
+// kudoThemeBlocks are the optional theme inputs of the admin and promote modals, labelled with prefix
+func kudoThemeBlocks(prefix string) []interface{} {
+	return []interface{}{
+		optionalBlock(plainTextInput("kudo_type_header", prefix+"cabeçalho", "", false, 150)),
+		optionalBlock(plainTextInput("kudo_type_image_url", prefix+"imagem (endereço https)", "", false, 3000)),
+		optionalBlock(plainTextInput("kudo_type_banner", prefix+"faixa de emojis", "", false, 150)),
+		optionalBlock(plainTextInput("kudo_type_footer", prefix+"rodapé", "", false, 150)),
+	}
+}
+
+// ParseKudoTheme reads the theme inputs of the admin and promote modals
+// An image address that isn't https is reported in fieldErrors, keyed by block ID
+func ParseKudoTheme(values map[string]map[string]slack.BlockAction, fieldErrors map[string]string) models.KudoTheme {
+	value := func(id string) string {
+		return strings.TrimSpace(values[id][id].Value)
+	}
+	theme := models.KudoTheme{
+		Header:   value("kudo_type_header"),
+		ImageURL: value("kudo_type_image_url"),
+		Banner:   value("kudo_type_banner"),
+		Footer:   value("kudo_type_footer"),
+	}
+	if theme.ImageURL != "" {
+		if parsed, err := url.Parse(theme.ImageURL); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			fieldErrors["kudo_type_image_url"] = "Informe o endereço https da imagem"
+		}
+	}
+	return theme
+}
+
+// promotedKudoType loads a kudo type promoted by admins, failures are logged
+func promotedKudoType(ctx context.Context, id string, cfg *config.Config) *models.KudoType {
+	if cfg.Store == nil || id == "" || id == "custom" || IsSavedKudoType(id) {
+		return nil
+	}
+	kudoType, err := cfg.Store.GetKudoType(ctx, id)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load kudo type", "kudo_type", id, "error", err)
+		return nil
+	}
+	return kudoType
+}
+
+// slugAccents folds the accented letters of Portuguese names
+var slugAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// slugSeparators matches the runs of characters that aren't letters or digits in a slug
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// KudoTypeSlug turns a type name into a kudo_type value like the built-in ones, e.g. "Mão na Massa" becomes mao-na-massa
+func KudoTypeSlug(name string) string {
+	slug := slugAccents.Replace(strings.ToLower(name))
+	return strings.Trim(slugSeparators.ReplaceAllString(slug, "-"), "-")
+}
+
+// AddOfficialKudoTypes offers the kudo types promoted by admins in the kudo_type select, before "✏️ Outro..."
+func AddOfficialKudoTypes(viewTemplate string, kudoTypes []*models.KudoType) (string, error) {
+	if len(kudoTypes) == 0 {
+		return viewTemplate, nil
+	}
+
+	options := make([]interface{}, 0, len(kudoTypes))
+	for _, kudoType := range kudoTypes {
+		options = append(options, kudoTypeOption(kudoType.Emoji+" "+kudoType.Name, kudoType.ID))
+	}
+	return insertKudoTypeOptions(viewTemplate, options)
+}
+
+// withOfficialKudoTypes adds the kudo types promoted by admins to the modal template
+// Failures are logged and leave the template unchanged, the built-in types still work
+func withOfficialKudoTypes(ctx context.Context, viewTemplate string, cfg *config.Config) string {
+	if cfg.Store == nil {
+		return viewTemplate
+	}
+
+	kudoTypes, err := cfg.Store.ListKudoTypes(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load kudo types", "error", err)
+		return viewTemplate
+	}
+
+	withTypes, err := AddOfficialKudoTypes(viewTemplate, kudoTypes)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not add kudo types to modal", "error", err)
+		return viewTemplate
+	}
+	return withTypes
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+// failingKudoTypeStore fails to load official kudo types
+type failingKudoTypeStore struct {
+	storage.Store
+}
+
+func (s failingKudoTypeStore) GetKudoType(ctx context.Context, id string) (*models.KudoType, error) {
+	return nil, errors.New("unavailable")
+}
+
+func (s failingKudoTypeStore) ListKudoTypes(ctx context.Context) ([]*models.KudoType, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestBuiltInKudoTypes(t *testing.T) {
+	kudoTypes, err := BuiltInKudoTypes()
+	if err != nil {
+		t.Fatalf("BuiltInKudoTypes() unexpected error = %v", err)
+	}
+	if len(kudoTypes) != len(models.KudoDescriptions) {
+		t.Fatalf("BuiltInKudoTypes() = %d types, want %d", len(kudoTypes), len(models.KudoDescriptions))
+	}
+	for _, kudoType := range kudoTypes {
+		if kudoType.ID == "custom" || kudoType.Emoji == "" || kudoType.Name == "" || kudoType.Description == "" || kudoType.SuggestedMessage == "" {
+			t.Errorf("built-in type = %+v, want every field of a predefined type", kudoType)
+		}
+	}
+}
+
+func TestKudoTypeSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain name", input: "Rockstar do Deploy", expected: "rockstar-do-deploy"},
+		{name: "accents", input: "Mão na Massa", expected: "mao-na-massa"},
+		{name: "punctuation", input: "  Líder(a) Inspirador(a)!  ", expected: "lider-a-inspirador-a"},
+		{name: "built-in name", input: "Resiliência", expected: "resiliencia"},
+		{name: "only symbols", input: "🎸🎸", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KudoTypeSlug(tt.input); got != tt.expected {
+				t.Errorf("KudoTypeSlug(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKudoTypeByID(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+	store.SaveKudoType(ctx, &models.KudoType{ID: "rockstar-do-deploy", Emoji: ":guitar:", Name: "Rockstar do Deploy", Description: "Deploys sem sustos", SuggestedMessage: "Que deploy!"})
+
+	tests := []struct {
+		name            string
+		id              string
+		store           storage.Store
+		wantName        string
+		wantDescription string
+		wantMessage     string
+		wantErr         bool
+	}{
+		{name: "built-in type", id: "resiliencia", store: store, wantName: "Resiliência", wantDescription: models.KudoDescriptions["resiliencia"], wantMessage: models.KudoSuggestedMessages["resiliencia"]},
+		{name: "promoted type", id: "rockstar-do-deploy", store: store, wantName: "Rockstar do Deploy", wantDescription: "Deploys sem sustos", wantMessage: "Que deploy!"},
+		{name: "unknown type", id: "removido", store: store},
+		{name: "saved custom type", id: SavedKudoTypePrefix + "T1_rock", store: store},
+		{name: "built-in type without store", id: "resiliencia", wantName: "Resiliência", wantDescription: models.KudoDescriptions["resiliencia"], wantMessage: models.KudoSuggestedMessages["resiliencia"]},
+		{name: "store failure", id: "rockstar-do-deploy", store: failingKudoTypeStore{store}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Store: tt.store}
+
+			kudoType, err := KudoTypeByID(ctx, tt.id, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("KudoTypeByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			name := ""
+			if kudoType != nil {
+				name = kudoType.Name
+			}
+			if name != tt.wantName {
+				t.Errorf("KudoTypeByID() = %+v, want %q", kudoType, tt.wantName)
+			}
+			if got := KudoTypeDescription(ctx, tt.id, cfg); got != tt.wantDescription {
+				t.Errorf("KudoTypeDescription() = %q, want %q", got, tt.wantDescription)
+			}
+			if got := SuggestedKudoMessage(ctx, tt.id, cfg); got != tt.wantMessage {
+				t.Errorf("SuggestedKudoMessage() = %q, want %q", got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestKudosViewTemplate_OfficialKudoTypes(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+	store.SaveKudoType(ctx, &models.KudoType{ID: "rockstar-do-deploy", Emoji: ":guitar:", Name: "Rockstar do Deploy", CreatedAt: created})
+	store.SaveCustomKudoType(ctx, &models.CustomKudoType{ID: "T1_rock", TeamID: "T1", Emoji: ":guitar:", Name: "Rockstar do Deploy", Status: models.CustomKudoTypePromoted, ResolvedTypeID: "rockstar-do-deploy"})
+	store.SaveCustomKudoType(ctx, &models.CustomKudoType{ID: "T1_hero", TeamID: "T1", Emoji: ":superhero:", Name: "Herói", Status: models.CustomKudoTypeDismissed})
+
+	tests := []struct {
+		name  string
+		store storage.Store
+		want  []string
+	}{
+		{
+			name:  "promoted types before the workspace's, resolved ones left out",
+			store: store,
+			want:  []string{"entrega-excepcional", "rockstar-do-deploy", SavedKudoTypePrefix + "T1_hero", "custom"},
+		},
+		{
+			name:  "store failure keeps the saved types",
+			store: failingKudoTypeStore{store},
+			want:  []string{"entrega-excepcional", SavedKudoTypePrefix + "T1_hero", "custom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := KudosViewTemplate(ctx, kudoTypeTemplate, "T1", "U111111", &config.Config{Store: tt.store})
+			if values := kudoTypeOptions(t, result); !slices.Equal(values, tt.want) {
+				t.Errorf("options = %v, want %v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddOfficialKudoTypes_OptionLimit(t *testing.T) {
+	kudoTypes := make([]*models.KudoType, 0, maxKudoTypeOptions)
+	for i := range maxKudoTypeOptions {
+		kudoTypes = append(kudoTypes, &models.KudoType{ID: KudoTypeSlug(time.Duration(i).String()), Emoji: ":star:", Name: "Tipo"})
+	}
+
+	result, err := AddOfficialKudoTypes(kudoTypeTemplate, kudoTypes)
+	if err != nil {
+		t.Fatalf("AddOfficialKudoTypes() unexpected error = %v", err)
+	}
+	values := kudoTypeOptions(t, result)
+	if len(values) != maxKudoTypeOptions || values[len(values)-1] != "custom" {
+		t.Errorf("options = %d ending with %s, want %d ending with custom", len(values), values[len(values)-1], maxKudoTypeOptions)
+	}
+}
+
+func TestParseKudoTheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageURL string
+		wantErr  bool
+	}{
+		{name: "no image", imageURL: ""},
+		{name: "https image", imageURL: " https://example.com/guitarra.png "},
+		{name: "http image", imageURL: "http://example.com/guitarra.png", wantErr: true},
+		{name: "not an address", imageURL: "guitarra.png", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldErrors := make(map[string]string)
+			theme := ParseKudoTheme(map[string]map[string]slack.BlockAction{
+				"kudo_type_header":    {"kudo_type_header": {Value: " 🎸 Rockstar! 🎸 "}},
+				"kudo_type_image_url": {"kudo_type_image_url": {Value: tt.imageURL}},
+				"kudo_type_footer":    {"kudo_type_footer": {Value: "Deploy sem sustos"}},
+			}, fieldErrors)
+
+			if _, gotErr := fieldErrors["kudo_type_image_url"]; gotErr != tt.wantErr {
+				t.Errorf("image error = %v, want %v", fieldErrors, tt.wantErr)
+			}
+			if theme.Header != "🎸 Rockstar! 🎸" || theme.Footer != "Deploy sem sustos" || theme.Banner != "" {
+				t.Errorf("theme = %+v, want the trimmed header and footer", theme)
+			}
+		})
+	}
+}
+
+func TestKudoTypeTheme(t *testing.T) {
+	store := storage.NewMemoryStore()
+	store.SaveKudoType(context.Background(), &models.KudoType{ID: "rockstar-do-deploy", Name: "Rockstar do Deploy", Theme: models.KudoTheme{Banner: "🎸🎸🎸"}})
+	cfg := &config.Config{Store: store}
+
+	tests := []struct {
+		id   string
+		want models.KudoTheme
+	}{
+		{id: "conquista-do-time", want: models.KudoThemes["conquista-do-time"]},
+		{id: "rockstar-do-deploy", want: models.KudoTheme{Banner: "🎸🎸🎸"}},
+		{id: "removido", want: models.KudoTheme{}},
+		{id: "custom", want: models.KudoTheme{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := KudoTypeTheme(context.Background(), tt.id, cfg); got != tt.want {
+				t.Errorf("KudoTypeTheme(%q) = %+v, want %+v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
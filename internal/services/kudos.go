@@ -1,60 +1,112 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // InviteUsersToChannel invites users to the channel if they're not already members
-func InviteUsersToChannel(recipientIDs []string, cfg *config.Config) {
+func InviteUsersToChannel(ctx context.Context, recipientIDs []string, cfg *config.Config) {
+	ctx, span := tracing.Start(ctx, "services.InviteUsersToChannel")
+	defer span.End()
+
 	for _, userID := range recipientIDs {
-		_, err := cfg.SlackAPI.InviteUsersToConversation(cfg.SlackChannelID, userID)
+		_, err := cfg.SlackAPI.InviteUsersToConversationContext(ctx, cfg.SlackChannelID, userID)
 		if err != nil {
 			// Ignore "already_in_channel" errors
 			if strings.Contains(err.Error(), "already_in_channel") {
-				log.Printf("User %s is already in channel %s", userID, cfg.SlackChannelID)
+				slog.DebugContext(ctx, "User is already in channel", "user_id", userID, "channel_id", cfg.SlackChannelID)
 				continue
 			}
 			// Log other errors but don't fail the operation
-			log.Printf("Warning: could not invite user %s to channel %s: %v", userID, cfg.SlackChannelID, err)
+			slog.WarnContext(ctx, "Could not invite user to channel", "user_id", userID, "channel_id", cfg.SlackChannelID, "error", err)
 		} else {
-			log.Printf("Successfully invited user %s to channel %s", userID, cfg.SlackChannelID)
+			slog.InfoContext(ctx, "Invited user to channel", "user_id", userID, "channel_id", cfg.SlackChannelID)
 		}
 	}
 }
 
-// PostKudos sends a kudos message to Slack channel
-func PostKudos(senderID string, recipientIDs []string, kudoTypeEmoji, kudoTypeText, message string, cfg *config.Config) error {
+// PostKudos sends a kudos message to Slack channel and records it in the store
+// A kudo is posted at most once, repeats return ErrDuplicateKudo (see claimKudo), and kudos the sender
+// can't afford return ErrInsufficientPoints (see reservePoints)
+func PostKudos(ctx context.Context, kudo *models.Kudo, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.PostKudos")
+	defer span.End()
+
+	release, err := claimKudo(ctx, kudo, time.Now(), cfg)
+	if err != nil {
+		return err
+	}
+	refund, err := reservePoints(ctx, kudo, cfg)
+	if err != nil {
+		release()
+		return err
+	}
+
 	// Invite recipients to channel first
-	InviteUsersToChannel(recipientIDs, cfg)
-	blocks := FormatKudosAsBlocks(senderID, recipientIDs, kudoTypeEmoji, kudoTypeText, message)
+	InviteUsersToChannel(ctx, kudo.RecipientIDs, cfg)
+	blocks, err := RenderKudoBlocks(ctx, kudo, cfg)
+	if err != nil {
+		slog.WarnContext(ctx, "Falling back to the classic kudos message", "error", err)
+		blocks = FormatKudosAsBlocks(kudo)
+	}
 
-	usersString := FormatUsersForSlack(recipientIDs)
+	usersString := FormatUsersForSlack(kudo.RecipientIDs)
 	fallbackText := fmt.Sprintf(
 		"%s elogiou %s: %s %s",
-		fmt.Sprintf("<@%s>", senderID),
+		fmt.Sprintf("<@%s>", kudo.SenderID),
 		usersString,
-		kudoTypeEmoji,
-		kudoTypeText,
+		EscapeMrkdwn(kudo.TypeEmoji),
+		EscapeMrkdwn(kudo.TypeText),
 	)
+	if kudo.Points > 0 {
+		fallbackText += fmt.Sprintf(" (+%d pontos)", kudo.Points)
+	}
 
-	respChannelID, timestamp, err := cfg.SlackAPI.PostMessage(
+	respChannelID, timestamp, err := cfg.SlackAPI.PostMessageContext(ctx,
 		cfg.SlackChannelID,
 		slack.MsgOptionBlocks(blocks...),
 		slack.MsgOptionText(fallbackText, false),
 	)
 	if err != nil {
+		// Let a retry post it
+		refund()
+		release()
 		return fmt.Errorf("error posting message: %w", err)
 	}
 
-	log.Printf("Message posted to channel %s at %s", respChannelID, timestamp)
+	slog.InfoContext(ctx, "Kudos posted", "channel_id", respChannelID, "message_ts", timestamp)
+	metrics.KudoPosted(ctx, kudo.TypeID)
+
+	kudo.ChannelID = respChannelID
+	kudo.MessageTS = timestamp
+	recordKudo(ctx, kudo, cfg)
 	return nil
 }
 
+// recordKudo persists a posted kudo
+// Failures are only logged since the message is already visible in the channel
+func recordKudo(ctx context.Context, kudo *models.Kudo, cfg *config.Config) {
+	if cfg.Store == nil {
+		return
+	}
+	if kudo.CreatedAt.IsZero() {
+		kudo.CreatedAt = time.Now()
+	}
+	if err := cfg.Store.SaveKudo(ctx, kudo); err != nil {
+		slog.WarnContext(ctx, "Could not record kudo", "sender_id", kudo.SenderID, "error", err)
+	}
+}
+
 // ParseKudoTypeText splits emoji and text from kudo type full text
 // Example: ":zap: Resolvedor(a) de Problemas" -> (":zap:", "Resolvedor(a) de Problemas")
 func ParseKudoTypeText(kudoTypeFullText string) (emoji, text string) {
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
 )
 
 // MockSlackClient is a mock implementation of config.SlackClient
@@ -14,30 +18,36 @@ type MockSlackClient struct {
 	InviteUsersToConversationFunc func(channelID string, users ...string) (*slack.Channel, error)
 	GetUsersInConversationFunc    func(params *slack.GetUsersInConversationParameters) ([]string, string, error)
 	GetUserInfoFunc               func(user string) (*slack.User, error)
+	UploadFileV2Func              func(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	OpenConversationFunc          func(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	GetUserProfileFunc            func(params *slack.GetUserProfileParameters) (*slack.UserProfile, error)
+	UpdateMessageFunc             func(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	GetUserGroupMembersFunc       func(userGroup string) ([]string, error)
+	GetEmojiFunc                  func() (map[string]string, error)
 }
 
-func (m *MockSlackClient) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+func (m *MockSlackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
 	if m.PostMessageFunc != nil {
 		return m.PostMessageFunc(channelID, options...)
 	}
 	return "C123456", "1234567890.123456", nil
 }
 
-func (m *MockSlackClient) InviteUsersToConversation(channelID string, users ...string) (*slack.Channel, error) {
+func (m *MockSlackClient) InviteUsersToConversationContext(ctx context.Context, channelID string, users ...string) (*slack.Channel, error) {
 	if m.InviteUsersToConversationFunc != nil {
 		return m.InviteUsersToConversationFunc(channelID, users...)
 	}
 	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: channelID}}}, nil
 }
 
-func (m *MockSlackClient) GetUsersInConversation(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+func (m *MockSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
 	if m.GetUsersInConversationFunc != nil {
 		return m.GetUsersInConversationFunc(params)
 	}
 	return []string{"U123456", "U789012"}, "", nil
 }
 
-func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
+func (m *MockSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	if m.GetUserInfoFunc != nil {
 		return m.GetUserInfoFunc(user)
 	}
@@ -48,6 +58,48 @@ func (m *MockSlackClient) GetUserInfo(user string) (*slack.User, error) {
 	}, nil
 }
 
+func (m *MockSlackClient) UploadFileV2Context(ctx context.Context, params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	if m.UploadFileV2Func != nil {
+		return m.UploadFileV2Func(params)
+	}
+	return &slack.FileSummary{ID: "F123456", Title: params.Title}, nil
+}
+
+func (m *MockSlackClient) OpenConversationContext(ctx context.Context, params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	if m.OpenConversationFunc != nil {
+		return m.OpenConversationFunc(params)
+	}
+	return &slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "D123456"}}}, false, false, nil
+}
+
+func (m *MockSlackClient) GetUserProfileContext(ctx context.Context, params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+	if m.GetUserProfileFunc != nil {
+		return m.GetUserProfileFunc(params)
+	}
+	return &slack.UserProfile{}, nil
+}
+
+func (m *MockSlackClient) UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	if m.UpdateMessageFunc != nil {
+		return m.UpdateMessageFunc(channelID, timestamp, options...)
+	}
+	return channelID, timestamp, "", nil
+}
+
+func (m *MockSlackClient) GetUserGroupMembersContext(ctx context.Context, userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	if m.GetUserGroupMembersFunc != nil {
+		return m.GetUserGroupMembersFunc(userGroup)
+	}
+	return []string{}, nil
+}
+
+func (m *MockSlackClient) GetEmojiContext(ctx context.Context) (map[string]string, error) {
+	if m.GetEmojiFunc != nil {
+		return m.GetEmojiFunc()
+	}
+	return map[string]string{}, nil
+}
+
 func TestPostKudos(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -138,14 +190,13 @@ func TestPostKudos(t *testing.T) {
 				SlackAPI:       mockSlack,
 			}
 
-			err := PostKudos(
-				tt.senderID,
-				tt.recipientIDs,
-				tt.kudoTypeEmoji,
-				tt.kudoTypeText,
-				tt.message,
-				cfg,
-			)
+			err := PostKudos(context.Background(), &models.Kudo{
+				SenderID:     tt.senderID,
+				RecipientIDs: tt.recipientIDs,
+				TypeEmoji:    tt.kudoTypeEmoji,
+				TypeText:     tt.kudoTypeText,
+				Message:      tt.message,
+			}, cfg)
 
 			if tt.wantErr {
 				if err == nil {
@@ -261,14 +312,13 @@ func TestPostKudos_FallbackText(t *testing.T) {
 		SlackAPI:       mockSlack,
 	}
 
-	err := PostKudos(
-		"U111111",
-		[]string{"U222222", "U333333"},
-		":zap:",
-		"Resolvedor(a) de Problemas",
-		"Ótimo trabalho!",
-		cfg,
-	)
+	err := PostKudos(context.Background(), &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222", "U333333"},
+		TypeEmoji:    ":zap:",
+		TypeText:     "Resolvedor(a) de Problemas",
+		Message:      "Ótimo trabalho!",
+	}, cfg)
 
 	if err != nil {
 		t.Fatalf("PostKudos() unexpected error = %v", err)
@@ -358,7 +408,7 @@ func TestInviteUsersToChannel(t *testing.T) {
 			}
 
 			// This function doesn't return errors, it only logs them
-			InviteUsersToChannel(tt.recipientIDs, cfg)
+			InviteUsersToChannel(context.Background(), tt.recipientIDs, cfg)
 
 			if callCount != tt.expectCalls {
 				t.Errorf("expected %d calls to InviteUsersToConversation, got %d", tt.expectCalls, callCount)
@@ -380,4 +430,124 @@ func containsHelper(s, substr string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+func TestPostKudos_RecordsKudo(t *testing.T) {
+	store := storage.NewMemoryStore()
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI:       &MockSlackClient{},
+		Store:          store,
+	}
+
+	err := PostKudos(context.Background(), &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222"},
+		TypeID:       "ideia-brilhante",
+		TypeEmoji:    ":bulb:",
+		TypeText:     "Ideia Brilhante",
+		Message:      "Ótima ideia!",
+		Points:       5,
+	}, cfg)
+	if err != nil {
+		t.Fatalf("PostKudos() unexpected error = %v", err)
+	}
+
+	kudos, err := store.ListKudos(context.Background(), storage.KudoQuery{SenderID: "U111111"})
+	if err != nil {
+		t.Fatalf("ListKudos() unexpected error = %v", err)
+	}
+	if len(kudos) != 1 {
+		t.Fatalf("expected 1 recorded kudo, got %d", len(kudos))
+	}
+
+	recorded := kudos[0]
+	if recorded.Points != 5 {
+		t.Errorf("recorded points = %d, want 5", recorded.Points)
+	}
+	if recorded.ChannelID != "C123456" || recorded.MessageTS != "1234567890.123456" {
+		t.Errorf("recorded message reference = %s/%s, want C123456/1234567890.123456", recorded.ChannelID, recorded.MessageTS)
+	}
+	if recorded.CreatedAt.IsZero() {
+		t.Error("recorded kudo should have a creation time")
+	}
+}
+
+func TestPostKudos_DoesNotRecordOnError(t *testing.T) {
+	store := storage.NewMemoryStore()
+	cfg := &config.Config{
+		SlackChannelID: "C123456",
+		SlackAPI: &MockSlackClient{
+			PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+				return "", "", errors.New("channel_not_found")
+			},
+		},
+		Store: store,
+	}
+
+	if err := PostKudos(context.Background(), &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}}, cfg); err == nil {
+		t.Fatal("PostKudos() expected error, got nil")
+	}
+
+	kudos, _ := store.ListKudos(context.Background(), storage.KudoQuery{})
+	if len(kudos) != 0 {
+		t.Errorf("expected no recorded kudos, got %d", len(kudos))
+	}
+}
+
+func TestPostKudos_Points(t *testing.T) {
+	tests := []struct {
+		name         string
+		alreadySpent int
+		postErr      error
+		wantErr      error
+		wantRecorded int
+	}{
+		{name: "within the allowance", alreadySpent: 80, wantRecorded: 2},
+		{name: "balance spent by another kudo", alreadySpent: 90, wantErr: ErrInsufficientPoints, wantRecorded: 1},
+		{name: "post failure refunds the points", alreadySpent: 80, postErr: errors.New("channel_not_found"), wantRecorded: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			store.SaveKudo(ctx, &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U333333"}, Points: tt.alreadySpent, CreatedAt: time.Now()})
+
+			posted := false
+			cfg := &config.Config{
+				SlackChannelID: "C123456",
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						posted = true
+						if tt.postErr != nil {
+							return "", "", tt.postErr
+						}
+						return channelID, "1234567890.123456", nil
+					},
+				},
+				MonthlyPoints: 100,
+				Location:      time.UTC,
+				Store:         store,
+			}
+
+			err := PostKudos(ctx, &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U444444"}, Points: 10}, cfg)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PostKudos() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && posted {
+				t.Error("PostKudos() should not post kudos the sender can't afford")
+			}
+
+			kudos, _ := store.ListKudos(ctx, storage.KudoQuery{SenderID: "U111111"})
+			if len(kudos) != tt.wantRecorded {
+				t.Errorf("got %d recorded kudos, want %d", len(kudos), tt.wantRecorded)
+			}
+
+			// Deleting the kudo of a failed post is the only change to the kudos history, and it's audited
+			refunds, _ := store.ListAuditEntries(ctx, storage.AuditQuery{Action: models.AuditKudoRefunded})
+			if wantRefunds := tt.postErr != nil; (len(refunds) == 1) != wantRefunds || (wantRefunds && refunds[0].ActorID != "U111111") {
+				t.Errorf("refund entries = %+v, want one by U111111: %v", refunds, wantRefunds)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// managerNotificationMaxItems keeps the DM under Slack's 50 blocks limit
+const managerNotificationMaxItems = 40
+
+// managerNotificationMaxText is Slack's limit for the text of each kudo's section
+const managerNotificationMaxText = 3000
+
+// managerNotificationKeyTTL keeps a day's summary from being sent again, longer than any redelivery or re-run
+const managerNotificationKeyTTL = 90 * 24 * time.Hour
+
+// ErrManagerNotified is returned by SendManagerNotification when the manager already got the day's summary
+var ErrManagerNotified = errors.New("manager already notified")
+
+// ReportKudo is a kudo received by one or more people reporting to the same manager
+type ReportKudo struct {
+	Kudo      *models.Kudo
+	ReportIDs []string
+}
+
+// ManagerNotification lists the kudos a manager's reports received in a day
+type ManagerNotification struct {
+	ManagerID string
+	Kudos     []ReportKudo
+}
+
+// managerResolver finds each user's manager, caching results for the run
+type managerResolver struct {
+	cfg   *config.Config
+	cache map[string]string
+}
+
+// resolve returns the user's manager from the Slack profile custom field,
+// falling back to the org file. Returns "" when no manager is known
+func (r *managerResolver) resolve(ctx context.Context, userID string) string {
+	if managerID, ok := r.cache[userID]; ok {
+		return managerID
+	}
+
+	managerID := ""
+	if r.cfg.ManagerFieldID != "" {
+		profile, err := r.cfg.SlackAPI.GetUserProfileContext(ctx, &slack.GetUserProfileParameters{UserID: userID})
+		if err != nil {
+			slog.WarnContext(ctx, "Could not get user profile", "user_id", userID, "error", err)
+		} else if field, ok := profile.Fields.ToMap()[r.cfg.ManagerFieldID]; ok {
+			managerID = strings.TrimSpace(field.Value)
+		}
+	}
+	if managerID == "" {
+		managerID = r.cfg.ManagerOf(userID)
+	}
+
+	r.cache[userID] = managerID
+	return managerID
+}
+
+// DayPeriod parses a YYYY-MM-DD date into its day bounds
+// An empty date selects the day before now
+func DayPeriod(date string, now time.Time, loc *time.Location) (time.Time, time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if date == "" {
+		local := now.In(loc)
+		today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		return today.AddDate(0, 0, -1), today, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	return day, day.AddDate(0, 0, 1), nil
+}
+
+// BuildManagerNotifications groups the kudos created in [from, to) by the recipients' managers
+// Managers aren't notified about kudos they sent themselves
+func BuildManagerNotifications(ctx context.Context, from, to time.Time, cfg *config.Config) ([]ManagerNotification, error) {
+	ctx, span := tracing.Start(ctx, "services.BuildManagerNotifications")
+	defer span.End()
+
+	kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{From: from, To: to})
+	if err != nil {
+		return nil, fmt.Errorf("error listing kudos: %w", err)
+	}
+
+	resolver := &managerResolver{cfg: cfg, cache: make(map[string]string)}
+	var notifications []ManagerNotification
+	notificationIndex := make(map[string]int)
+
+	for _, kudo := range kudos {
+		reportsByManager := make(map[string][]string)
+		var managers []string
+
+		for _, recipientID := range kudo.RecipientIDs {
+			managerID := resolver.resolve(ctx, recipientID)
+			if managerID == "" || managerID == recipientID || managerID == kudo.SenderID {
+				continue
+			}
+			if _, seen := reportsByManager[managerID]; !seen {
+				managers = append(managers, managerID)
+			}
+			reportsByManager[managerID] = append(reportsByManager[managerID], recipientID)
+		}
+
+		for _, managerID := range managers {
+			index, ok := notificationIndex[managerID]
+			if !ok {
+				index = len(notifications)
+				notificationIndex[managerID] = index
+				notifications = append(notifications, ManagerNotification{ManagerID: managerID})
+			}
+			notifications[index].Kudos = append(notifications[index].Kudos, ReportKudo{
+				Kudo:      kudo,
+				ReportIDs: reportsByManager[managerID],
+			})
+		}
+	}
+
+	return notifications, nil
+}
+
+// ManagerNotificationIdempotencyKey identifies the summary of the day starting at day sent to the manager
+func ManagerNotificationIdempotencyKey(day time.Time, managerID string) string {
+	return fmt.Sprintf("manager-notify:%s:%s", day.Format("2006-01-02"), managerID)
+}
+
+// SendManagerNotification sends the daily summary DM to the manager
+// A day's summary is sent once per manager, Pub/Sub redeliveries return ErrManagerNotified
+func SendManagerNotification(ctx context.Context, notification ManagerNotification, day time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.SendManagerNotification")
+	defer span.End()
+
+	key := ManagerNotificationIdempotencyKey(day, notification.ManagerID)
+	claimed := false
+	if cfg.Store != nil {
+		now := time.Now()
+		ok, err := cfg.Store.ClaimKey(ctx, key, now, now.Add(managerNotificationKeyTTL))
+		switch {
+		case err != nil:
+			slog.WarnContext(ctx, "Could not check whether the manager was notified", "key", key, "error", err)
+		case !ok:
+			return fmt.Errorf("%w: %s", ErrManagerNotified, key)
+		default:
+			claimed = true
+		}
+	}
+
+	_, _, err := cfg.SlackAPI.PostMessageContext(ctx,
+		notification.ManagerID,
+		slack.MsgOptionBlocks(FormatManagerNotificationBlocks(notification, day)...),
+		slack.MsgOptionText(fmt.Sprintf("Seu time recebeu %d elogio(s) em %s", len(notification.Kudos), day.Format("02/01/2006")), false),
+	)
+	if err != nil {
+		// Let a retry send it
+		if claimed {
+			if err := cfg.Store.ReleaseKey(ctx, key); err != nil {
+				slog.WarnContext(ctx, "Could not release idempotency key", "key", key, "error", err)
+			}
+		}
+		return fmt.Errorf("failed to send manager notification to %s: %w", notification.ManagerID, err)
+	}
+	return nil
+}
+
+// FormatManagerNotificationBlocks creates the Block Kit structure for the manager's daily summary
+func FormatManagerNotificationBlocks(notification ManagerNotification, day time.Time) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(
+			&slack.TextBlockObject{
+				Type: slack.PlainTextType,
+				Text: "📬 Reconhecimentos do seu time",
+			},
+		),
+
+		slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: fmt.Sprintf("Seu time recebeu *%d elogio(s)* em %s:", len(notification.Kudos), day.Format("02/01/2006")),
+			},
+			nil,
+			nil,
+		),
+
+		slack.NewDividerBlock(),
+	}
+
+	for i, item := range notification.Kudos {
+		if i == managerNotificationMaxItems {
+			blocks = append(blocks, slack.NewSectionBlock(
+				&slack.TextBlockObject{
+					Type: slack.MarkdownType,
+					Text: fmt.Sprintf("_…e mais %d elogio(s) no canal de elogios._", len(notification.Kudos)-managerNotificationMaxItems),
+				},
+				nil,
+				nil,
+			))
+			break
+		}
+
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: formatReportKudo(item),
+			},
+			nil,
+			nil,
+		))
+	}
+
+	blocks = append(blocks,
+		slack.NewContextBlock(
+			"manager_notification_context",
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: "🔒 Esta mensagem é visível apenas para você.",
+			},
+		),
+	)
+
+	return blocks
+}
+
+// formatReportKudo renders a single kudo of the manager's summary
+// Long messages are cut to keep the section within managerNotificationMaxText
+func formatReportKudo(item ReportKudo) string {
+	mentions := make([]string, len(item.ReportIDs))
+	for i, userID := range item.ReportIDs {
+		mentions[i] = fmt.Sprintf("<@%s>", userID)
+	}
+
+	text := fmt.Sprintf("%s recebeu %s *%s* de <@%s>",
+		strings.Join(mentions, ", "), EscapeMrkdwn(item.Kudo.TypeEmoji), EscapeMrkdwn(item.Kudo.TypeText), item.Kudo.SenderID)
+
+	// The message is cut before sanitizing, so mentions and links are never split
+	message := []rune(item.Kudo.Message)
+	for len(message) > 0 {
+		quote := "\n>" + strings.ReplaceAll(SanitizeMrkdwn(string(message)), "\n", "\n>")
+		if len(message) < utf8.RuneCountInString(item.Kudo.Message) {
+			quote += "…"
+		}
+		excess := utf8.RuneCountInString(text+quote) - managerNotificationMaxText
+		if excess <= 0 {
+			return text + quote
+		}
+		message = message[:max(len(message)-excess, 0)]
+	}
+	return text
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestDayPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		date         string
+		expectedFrom time.Time
+		wantErr      bool
+	}{
+		{name: "empty date selects previous day", date: "", expectedFrom: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "explicit date", date: "2026-02-28", expectedFrom: time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "invalid date", date: "28/02/2026", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := DayPeriod(tt.date, now, time.UTC)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("DayPeriod() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DayPeriod() unexpected error = %v", err)
+			}
+			if !from.Equal(tt.expectedFrom) {
+				t.Errorf("DayPeriod() from = %v, want %v", from, tt.expectedFrom)
+			}
+			if !to.Equal(tt.expectedFrom.AddDate(0, 0, 1)) {
+				t.Errorf("DayPeriod() to = %v, want the next day", to)
+			}
+		})
+	}
+}
+
+func TestBuildManagerNotifications(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	store := storage.NewMemoryStore()
+	for _, kudo := range []*models.Kudo{
+		{SenderID: "U900", RecipientIDs: []string{"U111", "U222"}, TypeEmoji: ":rocket:", TypeText: "Entrega", CreatedAt: day.Add(9 * time.Hour)},
+		{SenderID: "U900", RecipientIDs: []string{"U333"}, TypeEmoji: ":bulb:", TypeText: "Ideia", CreatedAt: day.Add(10 * time.Hour)},
+		// Sent by the manager, not notified back
+		{SenderID: "UMGR1", RecipientIDs: []string{"U111"}, CreatedAt: day.Add(11 * time.Hour)},
+		// Outside the day
+		{SenderID: "U900", RecipientIDs: []string{"U111"}, CreatedAt: day.AddDate(0, 0, -1)},
+	} {
+		if err := store.SaveKudo(context.Background(), kudo); err != nil {
+			t.Fatalf("SaveKudo() error = %v", err)
+		}
+	}
+
+	teams := []models.Team{
+		{Name: "Plataforma", ManagerID: "UMGR1", MemberIDs: []string{"U111", "U222", "U333"}},
+	}
+
+	tests := []struct {
+		name           string
+		managerFieldID string
+		profiles       map[string]string // user ID -> manager field value
+		profileErr     error
+		expected       map[string][]string // manager ID -> reports of each kudo, joined
+	}{
+		{
+			name: "resolves managers from org file",
+			expected: map[string][]string{
+				"UMGR1": {"U111,U222", "U333"},
+			},
+		},
+		{
+			name:           "profile field takes precedence over org file",
+			managerFieldID: "Xf01MANAGER",
+			profiles:       map[string]string{"U333": "UMGR2"},
+			expected: map[string][]string{
+				"UMGR1": {"U111,U222"},
+				"UMGR2": {"U333"},
+			},
+		},
+		{
+			name:           "falls back to org file when profile lookup fails",
+			managerFieldID: "Xf01MANAGER",
+			profileErr:     errors.New("user_not_found"),
+			expected: map[string][]string{
+				"UMGR1": {"U111,U222", "U333"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profileCalls := make(map[string]int)
+			mockSlack := &MockSlackClient{
+				GetUserProfileFunc: func(params *slack.GetUserProfileParameters) (*slack.UserProfile, error) {
+					profileCalls[params.UserID]++
+					if tt.profileErr != nil {
+						return nil, tt.profileErr
+					}
+					profile := &slack.UserProfile{}
+					if managerID, ok := tt.profiles[params.UserID]; ok {
+						profile.Fields.SetMap(map[string]slack.UserProfileCustomField{
+							tt.managerFieldID: {Value: managerID},
+						})
+					}
+					return profile, nil
+				},
+			}
+
+			cfg := &config.Config{
+				ManagerFieldID: tt.managerFieldID,
+				Teams:          teams,
+				SlackAPI:       mockSlack,
+				Store:          store,
+			}
+
+			notifications, err := BuildManagerNotifications(context.Background(), day, day.AddDate(0, 0, 1), cfg)
+			if err != nil {
+				t.Fatalf("BuildManagerNotifications() unexpected error = %v", err)
+			}
+
+			if len(notifications) != len(tt.expected) {
+				t.Fatalf("BuildManagerNotifications() returned %d notifications, want %d", len(notifications), len(tt.expected))
+			}
+			for _, notification := range notifications {
+				expected, ok := tt.expected[notification.ManagerID]
+				if !ok {
+					t.Errorf("unexpected notification for %s", notification.ManagerID)
+					continue
+				}
+				var got []string
+				for _, item := range notification.Kudos {
+					got = append(got, strings.Join(item.ReportIDs, ","))
+				}
+				if strings.Join(got, "|") != strings.Join(expected, "|") {
+					t.Errorf("notification for %s = %v, want %v", notification.ManagerID, got, expected)
+				}
+			}
+
+			for userID, calls := range profileCalls {
+				if calls > 1 {
+					t.Errorf("profile of %s fetched %d times, want once", userID, calls)
+				}
+			}
+		})
+	}
+}
+
+func TestSendManagerNotification(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	errSlack := errors.New("channel_not_found")
+	notification := ManagerNotification{
+		ManagerID: "UMGR1",
+		Kudos:     []ReportKudo{{Kudo: &models.Kudo{SenderID: "U900"}, ReportIDs: []string{"U111"}}},
+	}
+
+	tests := []struct {
+		name        string
+		claimed     bool // the day's summary was already sent to the manager
+		postErr     error
+		expectError error
+		wantPosted  bool
+		wantClaimed bool
+	}{
+		{name: "sends DM to manager", wantPosted: true, wantClaimed: true},
+		{name: "post error releases the key", postErr: errSlack, expectError: errSlack, wantPosted: true},
+		{name: "redelivery is skipped", claimed: true, expectError: ErrManagerNotified, wantClaimed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			key := ManagerNotificationIdempotencyKey(day, "UMGR1")
+			if tt.claimed {
+				store.ClaimKey(ctx, key, time.Now(), time.Now().Add(time.Hour))
+			}
+
+			var postedTo string
+			cfg := &config.Config{
+				Store: store,
+				SlackAPI: &MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						postedTo = channelID
+						return channelID, "1234567890.123456", tt.postErr
+					},
+				},
+			}
+
+			err := SendManagerNotification(ctx, notification, day, cfg)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("SendManagerNotification() error = %v, want %v", err, tt.expectError)
+			}
+			if (postedTo == "UMGR1") != tt.wantPosted {
+				t.Errorf("SendManagerNotification() posted to %q, want posted %v", postedTo, tt.wantPosted)
+			}
+			claimed, _ := store.ClaimKey(ctx, key, time.Now(), time.Now().Add(time.Hour))
+			if claimed == tt.wantClaimed {
+				t.Errorf("key claimed = %v, want %v", !claimed, tt.wantClaimed)
+			}
+		})
+	}
+}
+
+func TestFormatManagerNotificationBlocks(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	kudo := &models.Kudo{
+		SenderID:  "U900",
+		TypeEmoji: ":rocket:",
+		TypeText:  "Entrega Excepcional",
+		Message:   "Primeira linha\nSegunda linha",
+	}
+
+	t.Run("renders each kudo", func(t *testing.T) {
+		blocks := FormatManagerNotificationBlocks(ManagerNotification{
+			ManagerID: "UMGR1",
+			Kudos:     []ReportKudo{{Kudo: kudo, ReportIDs: []string{"U111", "U222"}}},
+		}, day)
+
+		// header, intro, divider, kudo and context
+		if len(blocks) != 5 {
+			t.Fatalf("FormatManagerNotificationBlocks() returned %d blocks, want 5", len(blocks))
+		}
+
+		intro := blocks[1].(*slack.SectionBlock)
+		if !strings.Contains(intro.Text.Text, "10/03/2026") {
+			t.Errorf("intro = %q, want it to mention the day", intro.Text.Text)
+		}
+
+		item := blocks[3].(*slack.SectionBlock).Text.Text
+		for _, want := range []string{"<@U111>, <@U222> recebeu", ":rocket: *Entrega Excepcional*", "de <@U900>", ">Primeira linha\n>Segunda linha"} {
+			if !strings.Contains(item, want) {
+				t.Errorf("kudo section = %q, want it to contain %q", item, want)
+			}
+		}
+	})
+
+	t.Run("cuts long messages", func(t *testing.T) {
+		long := &models.Kudo{
+			SenderID:  "U900",
+			TypeEmoji: ":rocket:",
+			TypeText:  "Entrega Excepcional",
+			Message:   strings.Repeat("Valeu <@U111> & time!\n", 200),
+		}
+		blocks := FormatManagerNotificationBlocks(ManagerNotification{
+			ManagerID: "UMGR1",
+			Kudos:     []ReportKudo{{Kudo: long, ReportIDs: []string{"U111"}}},
+		}, day)
+
+		item := blocks[3].(*slack.SectionBlock).Text.Text
+		if n := utf8.RuneCountInString(item); n > managerNotificationMaxText {
+			t.Errorf("kudo section has %d characters, Slack allows %d", n, managerNotificationMaxText)
+		}
+		if !strings.HasPrefix(item, "<@U111> recebeu") || !strings.HasSuffix(item, "…") {
+			t.Errorf("kudo section = %q, want the header line and the message cut with …", item)
+		}
+		if strings.Count(item, "<@U111>") != strings.Count(item, ">Valeu <@U111>")+1 {
+			t.Errorf("kudo section = %q, want no mention split", item)
+		}
+	})
+
+	t.Run("caps the number of kudos", func(t *testing.T) {
+		var items []ReportKudo
+		for range managerNotificationMaxItems + 5 {
+			items = append(items, ReportKudo{Kudo: kudo, ReportIDs: []string{"U111"}})
+		}
+
+		blocks := FormatManagerNotificationBlocks(ManagerNotification{ManagerID: "UMGR1", Kudos: items}, day)
+
+		if len(blocks) > 50 {
+			t.Errorf("FormatManagerNotificationBlocks() returned %d blocks, Slack allows 50", len(blocks))
+		}
+		overflow := blocks[len(blocks)-2].(*slack.SectionBlock).Text.Text
+		if !strings.Contains(overflow, "e mais 5") {
+			t.Errorf("overflow section = %q, want it to mention the 5 remaining kudos", overflow)
+		}
+	})
+}
@@ -2,18 +2,31 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 
+	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
-	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/metrics"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // OpenModal opens a Slack modal using the views.open API
-func OpenModal(triggerID, viewTemplate string, cfg *config.Config) error {
+func OpenModal(ctx context.Context, triggerID, viewTemplate string, cfg *config.Config) (err error) {
+	ctx, span := tracing.Start(ctx, "services.OpenModal")
+
+	// Errors reported by Slack are only logged, but still count as failed opens
+	var apiErr error
+	defer func() {
+		metrics.ModalOpened(ctx, errors.Join(err, apiErr))
+		tracing.End(span, errors.Join(err, apiErr))
+	}()
+
 	var viewRequest map[string]interface{}
 	if err := json.Unmarshal([]byte(viewTemplate), &viewRequest); err != nil {
 		return fmt.Errorf("error parsing view template: %w", err)
@@ -26,7 +39,7 @@ func OpenModal(triggerID, viewTemplate string, cfg *config.Config) error {
 		return fmt.Errorf("error marshaling view request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://slack.com/api/views.open", bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.SlackURL("views.open"), bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
@@ -45,12 +58,29 @@ func OpenModal(triggerID, viewTemplate string, cfg *config.Config) error {
 		return fmt.Errorf("error reading response body: %w", err)
 	}
 
-	log.Printf("Modal opened - Status: %s, Response: %s", resp.Status, string(body))
+	var slackResp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	if json.Unmarshal(body, &slackResp) == nil && !slackResp.OK {
+		apiErr = fmt.Errorf("slack API error: %s", slackResp.Error)
+		slog.WarnContext(ctx, "Slack rejected views.open", "status", resp.Status, "error", apiErr)
+		return nil
+	}
+
+	slog.InfoContext(ctx, "Modal opened", "status", resp.Status)
 	return nil
 }
 
 // UpdateModal updates an existing Slack modal using views.update API
-func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate string, cfg *config.Config) error {
+// messageValue prefills the rich text message field, nil leaves it as is
+func UpdateModal(ctx context.Context, viewID, hash, selectedKudoType string, messageValue *slack.RichTextBlock, viewTemplate string, cfg *config.Config) (err error) {
+	ctx, span := tracing.Start(ctx, "services.UpdateModal")
+	defer func() {
+		metrics.ModalUpdated(ctx, err)
+		tracing.End(span, err)
+	}()
+
 	var viewData map[string]interface{}
 	if err := json.Unmarshal([]byte(viewTemplate), &viewData); err != nil {
 		return fmt.Errorf("error parsing view template: %w", err)
@@ -68,6 +98,7 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 
 	kudoTypeIndex := -1
 	descriptionBlockIndex := -1
+	emojiBlockIndex := -1
 
 	for i, block := range blocks {
 		blockMap, ok := block.(map[string]interface{})
@@ -83,9 +114,13 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 			descriptionBlockIndex = i
 		}
 
+		if blockMap["block_id"] == "kudo_emoji" {
+			emojiBlockIndex = i
+		}
+
 		if blockMap["block_id"] == "kudo_message" {
 			element, ok := blockMap["element"].(map[string]interface{})
-			if ok && messageValue != "" {
+			if ok && messageValue != nil {
 				element["initial_value"] = messageValue
 			}
 		}
@@ -116,7 +151,7 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 		}
 
 		// Preserve existing value if switching back to custom
-		if messageValue != "" {
+		if messageValue != nil {
 			// Check if there's an existing custom description value in the blocks
 			if descriptionBlockIndex != -1 {
 				existingBlock, ok := blocks[descriptionBlockIndex].(map[string]interface{})
@@ -133,7 +168,10 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 		}
 	} else {
 		// Regular kudo type - use context block with description
-		description := models.KudoDescriptions[selectedKudoType]
+		description := KudoTypeDescription(ctx, selectedKudoType, cfg)
+		if IsSavedKudoType(selectedKudoType) {
+			description = "Tipo de elogio criado no workspace"
+		}
 		if description == "" {
 			description = "Tipo de elogio selecionado"
 		}
@@ -161,6 +199,21 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 		blocks[descriptionBlockIndex] = descriptionBlock
 	}
 
+	// Custom types also pick their emoji, right below their name
+	if selectedKudoType == "custom" && emojiBlockIndex == -1 {
+		blocks, _ = view["blocks"].([]interface{})
+		for i, block := range blocks {
+			if blockMap, ok := block.(map[string]interface{}); ok && blockMap["block_id"] == "kudo_description" {
+				newBlocks := make([]interface{}, 0, len(blocks)+1)
+				newBlocks = append(newBlocks, blocks[:i+1]...)
+				newBlocks = append(newBlocks, customEmojiBlock())
+				newBlocks = append(newBlocks, blocks[i+1:]...)
+				view["blocks"] = newBlocks
+				break
+			}
+		}
+	}
+
 	updateRequest := map[string]interface{}{
 		"view_id": viewID,
 		"hash":    hash,
@@ -172,7 +225,7 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 		return fmt.Errorf("error marshaling update request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://slack.com/api/views.update", bytes.NewReader(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.SlackURL("views.update"), bytes.NewReader(jsonBody))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
@@ -203,6 +256,78 @@ func UpdateModal(viewID, hash, selectedKudoType, messageValue, viewTemplate stri
 		return fmt.Errorf("slack API error: %s", slackResp.Error)
 	}
 
-	log.Printf("View updated successfully for kudo type: %s", selectedKudoType)
+	slog.InfoContext(ctx, "View updated", "kudo_type", selectedKudoType)
 	return nil
 }
+
+// customEmojiBlock is the emoji select of custom kudo types
+// Its options are loaded by Slack with block_suggestion requests, see EmojiOptions
+func customEmojiBlock() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "input",
+		"block_id": "kudo_emoji",
+		"optional": true,
+		"label": map[string]interface{}{
+			"type":  "plain_text",
+			"text":  "Emoji",
+			"emoji": true,
+		},
+		"hint": map[string]interface{}{
+			"type":  "plain_text",
+			"text":  "Digite para buscar também os emoji do workspace. Sem escolha, o tipo usa ✏️",
+			"emoji": true,
+		},
+		"element": map[string]interface{}{
+			"type":             "external_select",
+			"action_id":        "kudo_emoji",
+			"min_query_length": 0,
+			"placeholder": map[string]interface{}{
+				"type":  "plain_text",
+				"text":  "Escolha um emoji",
+				"emoji": true,
+			},
+		},
+	}
+}
+
+// PrefillRecipients sets the initially selected users of the kudos modal
+func PrefillRecipients(viewTemplate string, userIDs []string) (string, error) {
+	var viewData map[string]interface{}
+	if err := json.Unmarshal([]byte(viewTemplate), &viewData); err != nil {
+		return "", fmt.Errorf("error parsing view template: %w", err)
+	}
+
+	view, ok := viewData["view"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid view structure in template")
+	}
+
+	blocks, ok := view["blocks"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid blocks structure in template")
+	}
+
+	found := false
+	for _, block := range blocks {
+		blockMap, ok := block.(map[string]interface{})
+		if !ok || blockMap["block_id"] != "kudo_users" {
+			continue
+		}
+		element, ok := blockMap["element"].(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("invalid kudo_users element in template")
+		}
+		element["initial_users"] = userIDs
+		found = true
+	}
+
+	if !found {
+		return "", fmt.Errorf("kudo_users block not found in template")
+	}
+
+	result, err := json.Marshal(viewData)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling view template: %w", err)
+	}
+	return string(result), nil
+}
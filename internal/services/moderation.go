@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// DefaultModerationRules apply until rules are saved to the store
+var DefaultModerationRules = models.ModerationRules{
+	MaxLength:   2000,
+	MaxMentions: 10,
+	Locale:      LocalePortuguese,
+}
+
+// mentionPattern matches user, group and broadcast mentions in mrkdwn
+var mentionPattern = regexp.MustCompile(`<(?:@[UW][A-Z0-9]+|!subteam\^[A-Z0-9]+|!(?:here|channel|everyone))[|>]`)
+
+// moderationWording holds the errors shown in the modal in one locale
+type moderationWording struct {
+	TooShort        string // minimum length
+	TooLong         string // maximum length
+	BlockedWord     string // blocked word
+	TooManyMentions string // maximum mentions
+	TypeBlockedWord string // blocked word
+	TypeMentions    string
+}
+
+var moderationTexts = map[string]moderationWording{
+	LocalePortuguese: {
+		TooShort:        "A mensagem precisa ter pelo menos %d caracteres",
+		TooLong:         "A mensagem pode ter no máximo %d caracteres",
+		BlockedWord:     "A mensagem contém um termo não permitido: %q",
+		TooManyMentions: "Mencione no máximo %d pessoas ou grupos na mensagem",
+		TypeBlockedWord: "O nome do tipo de elogio contém um termo não permitido: %q",
+		TypeMentions:    "O nome do tipo de elogio não pode mencionar pessoas ou grupos",
+	},
+	LocaleEnglish: {
+		TooShort:        "The message must have at least %d characters",
+		TooLong:         "The message can have at most %d characters",
+		BlockedWord:     "The message contains a term that isn't allowed: %q",
+		TooManyMentions: "Mention at most %d people or groups in the message",
+		TypeBlockedWord: "The kudo type name contains a term that isn't allowed: %q",
+		TypeMentions:    "The kudo type name can't mention people or groups",
+	},
+}
+
+// moderationWordingFor returns the errors in the rules' locale, Portuguese when it's unknown
+func moderationWordingFor(rules models.ModerationRules) moderationWording {
+	if wording, ok := moderationTexts[rules.Locale]; ok {
+		return wording
+	}
+	return moderationTexts[LocalePortuguese]
+}
+
+// LoadModerationRules returns the rules saved in the store, or DefaultModerationRules
+// Store failures fall back to the defaults so kudos can still be sent
+func LoadModerationRules(ctx context.Context, cfg *config.Config) models.ModerationRules {
+	if cfg.Store == nil {
+		return DefaultModerationRules
+	}
+
+	rules, err := cfg.Store.GetModerationRules(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load moderation rules, using the defaults", "error", err)
+		return DefaultModerationRules
+	}
+	if rules == nil {
+		return DefaultModerationRules
+	}
+	return *rules
+}
+
+// ModerateMessage checks a kudo message against the rules
+// Returns the error to show on the message field, or an empty string when the message is allowed
+func ModerateMessage(rules models.ModerationRules, message string) string {
+	wording := moderationWordingFor(rules)
+
+	length := utf8.RuneCountInString(strings.TrimSpace(message))
+	if rules.MinLength > 0 && length < rules.MinLength {
+		return fmt.Sprintf(wording.TooShort, rules.MinLength)
+	}
+	if rules.MaxLength > 0 && length > rules.MaxLength {
+		return fmt.Sprintf(wording.TooLong, rules.MaxLength)
+	}
+	if word := findBlockedWord(rules.BlockedWords, message); word != "" {
+		return fmt.Sprintf(wording.BlockedWord, word)
+	}
+	if rules.MaxMentions > 0 && len(mentionPattern.FindAllString(message, -1)) > rules.MaxMentions {
+		return fmt.Sprintf(wording.TooManyMentions, rules.MaxMentions)
+	}
+	return ""
+}
+
+// ModerateTypeName checks a custom kudo type name against the blocked words
+// Type names are shown in the header of the kudo, so they can't mention anyone
+func ModerateTypeName(rules models.ModerationRules, name string) string {
+	wording := moderationWordingFor(rules)
+
+	if word := findBlockedWord(rules.BlockedWords, name); word != "" {
+		return fmt.Sprintf(wording.TypeBlockedWord, word)
+	}
+	if mentionPattern.MatchString(name) {
+		return wording.TypeMentions
+	}
+	return ""
+}
+
+// findBlockedWord returns the first blocked word or phrase found in the text
+// Matching ignores case and punctuation, and only whole words match
+func findBlockedWord(blocked []string, text string) string {
+	words := " " + strings.Join(splitWords(text), " ") + " "
+	for _, entry := range blocked {
+		phrase := strings.Join(splitWords(entry), " ")
+		if phrase != "" && strings.Contains(words, " "+phrase+" ") {
+			return strings.TrimSpace(entry)
+		}
+	}
+	return ""
+}
+
+// splitWords lowercases the text and splits it into words
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
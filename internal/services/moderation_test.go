@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestModerateMessage(t *testing.T) {
+	rules := models.ModerationRules{
+		MinLength:    10,
+		MaxLength:    40,
+		BlockedWords: []string{"idiota", "Cala a boca"},
+		MaxMentions:  2,
+	}
+
+	tests := []struct {
+		name     string
+		rules    models.ModerationRules
+		message  string
+		expected string
+	}{
+		{
+			name:    "allowed message",
+			rules:   rules,
+			message: "Valeu pela ajuda no deploy!",
+		},
+		{
+			name:     "too short",
+			rules:    rules,
+			message:  "  Valeu!  ",
+			expected: "A mensagem precisa ter pelo menos 10 caracteres",
+		},
+		{
+			name:     "too long",
+			rules:    rules,
+			message:  "Obrigado por tudo o que você fez pelo time neste trimestre!",
+			expected: "A mensagem pode ter no máximo 40 caracteres",
+		},
+		{
+			name:    "length counts characters, not bytes",
+			rules:   models.ModerationRules{MaxLength: 10},
+			message: "Ótimo!!! 🎉",
+		},
+		{
+			name:     "blocked word in another case",
+			rules:    rules,
+			message:  "Você não é IDIOTA, é gênio",
+			expected: `A mensagem contém um termo não permitido: "idiota"`,
+		},
+		{
+			name:     "blocked phrase across punctuation",
+			rules:    rules,
+			message:  "Nunca diga cala, a boca!",
+			expected: `A mensagem contém um termo não permitido: "Cala a boca"`,
+		},
+		{
+			name:    "blocked word inside another word",
+			rules:   rules,
+			message: "Os idiotismos do português",
+		},
+		{
+			name:     "too many mentions",
+			rules:    rules,
+			message:  "<@U111> <@U222> <!subteam^S333>",
+			expected: "Mencione no máximo 2 pessoas ou grupos na mensagem",
+		},
+		{
+			name:     "broadcasts count as mentions",
+			rules:    models.ModerationRules{MaxMentions: 1},
+			message:  "<!here> e <@U111>",
+			expected: "Mencione no máximo 1 pessoas ou grupos na mensagem",
+		},
+		{
+			name:     "english errors",
+			rules:    models.ModerationRules{MinLength: 10, Locale: LocaleEnglish},
+			message:  "Thanks!",
+			expected: "The message must have at least 10 characters",
+		},
+		{
+			name:     "unknown locale falls back to portuguese",
+			rules:    models.ModerationRules{MinLength: 10, Locale: "fr"},
+			message:  "Merci!",
+			expected: "A mensagem precisa ter pelo menos 10 caracteres",
+		},
+		{
+			name:    "zero rules allow everything",
+			message: "<@U1> <@U2> idiota",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ModerateMessage(tt.rules, tt.message); result != tt.expected {
+				t.Errorf("ModerateMessage() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModerateTypeName(t *testing.T) {
+	rules := models.ModerationRules{BlockedWords: []string{"idiota"}, MinLength: 50, Locale: LocaleEnglish}
+
+	tests := []struct {
+		name     string
+		typeName string
+		expected string
+	}{
+		// The message length rules don't apply to type names
+		{name: "allowed name", typeName: "Super Colaborador"},
+		{name: "blocked word", typeName: "Idiota do Ano", expected: `The kudo type name contains a term that isn't allowed: "idiota"`},
+		{name: "mention", typeName: "Fã do <!channel>", expected: "The kudo type name can't mention people or groups"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ModerateTypeName(rules, tt.typeName); result != tt.expected {
+				t.Errorf("ModerateTypeName() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// failingModerationStore fails to read the moderation rules
+type failingModerationStore struct {
+	storage.Store
+}
+
+func (failingModerationStore) GetModerationRules(context.Context) (*models.ModerationRules, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestLoadModerationRules(t *testing.T) {
+	saved := storage.NewMemoryStore()
+	if err := saved.SaveModerationRules(context.Background(), &models.ModerationRules{MaxLength: 100}); err != nil {
+		t.Fatalf("SaveModerationRules() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		store         storage.Store
+		wantMaxLength int
+	}{
+		{name: "no store", store: nil, wantMaxLength: DefaultModerationRules.MaxLength},
+		{name: "nothing saved", store: storage.NewMemoryStore(), wantMaxLength: DefaultModerationRules.MaxLength},
+		{name: "saved rules", store: saved, wantMaxLength: 100},
+		{name: "store failure", store: failingModerationStore{Store: storage.NewMemoryStore()}, wantMaxLength: DefaultModerationRules.MaxLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := LoadModerationRules(context.Background(), &config.Config{Store: tt.store})
+			if rules.MaxLength != tt.wantMaxLength {
+				t.Errorf("MaxLength = %d, want %d", rules.MaxLength, tt.wantMaxLength)
+			}
+		})
+	}
+}
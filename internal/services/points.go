@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// ErrInsufficientPoints is returned by PostKudos when the kudo gives more points than the sender has left
+var ErrInsufficientPoints = errors.New("insufficient points")
+
+// MonthPeriod returns the start (inclusive) and end (exclusive) of the calendar month containing t
+func MonthPeriod(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// RemainingPoints returns how many points the sender can still give in the current month
+func RemainingPoints(ctx context.Context, senderID string, now time.Time, cfg *config.Config) (int, error) {
+	ctx, span := tracing.Start(ctx, "services.RemainingPoints")
+	defer span.End()
+
+	start, end := MonthPeriod(now, cfg.Location)
+
+	kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{
+		SenderID: senderID,
+		From:     start,
+		To:       end,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error listing kudos for %s: %w", senderID, err)
+	}
+
+	spent := 0
+	for _, kudo := range kudos {
+		spent += kudo.TotalPoints()
+	}
+
+	remaining := cfg.MonthlyPoints - spent
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, nil
+}
+
+// reservePoints saves a kudo giving points before it's posted, checking the sender's balance in the same transaction
+// so concurrent submissions can't overspend. The returned function deletes it when posting fails, recorded in the audit log
+func reservePoints(ctx context.Context, kudo *models.Kudo, cfg *config.Config) (func(), error) {
+	if cfg.Store == nil || !cfg.PointsEnabled() || kudo.TotalPoints() == 0 {
+		return func() {}, nil
+	}
+	if kudo.CreatedAt.IsZero() {
+		kudo.CreatedAt = time.Now()
+	}
+
+	start, end := MonthPeriod(kudo.CreatedAt, cfg.Location)
+	saved, err := cfg.Store.SaveKudoWithinAllowance(ctx, kudo, storage.KudoQuery{
+		SenderID: kudo.SenderID,
+		From:     start,
+		To:       end,
+	}, cfg.MonthlyPoints)
+	if err != nil {
+		return nil, fmt.Errorf("error reserving points for %s: %w", kudo.SenderID, err)
+	}
+	if !saved {
+		return nil, fmt.Errorf("%w: %d points for %s", ErrInsufficientPoints, kudo.TotalPoints(), kudo.SenderID)
+	}
+
+	return func() {
+		if err := cfg.Store.DeleteKudo(ctx, kudo.ID); err != nil {
+			slog.WarnContext(ctx, "Could not release reserved points", "kudo_id", kudo.ID, "error", err)
+			return
+		}
+		RecordAudit(ctx, models.AuditKudoRefunded, kudo.SenderID, kudo.ID, kudo, nil, cfg)
+	}, nil
+}
+
+// FormatBalanceMessage creates the reply for the /elogie saldo command
+func FormatBalanceMessage(remaining, allowance int, periodEnd time.Time) string {
+	return fmt.Sprintf(
+		"🪙 Você tem *%d* de %d pontos disponíveis este mês.\nSeu saldo será renovado em %s.",
+		remaining,
+		allowance,
+		periodEnd.Format("02/01/2006"),
+	)
+}
+
+// AddPointsInput inserts the points field into the kudos modal template
+// The hint shows the sender's remaining balance for the month
+func AddPointsInput(viewTemplate string, remaining int) (string, error) {
+	var viewData map[string]interface{}
+	if err := json.Unmarshal([]byte(viewTemplate), &viewData); err != nil {
+		return "", fmt.Errorf("error parsing view template: %w", err)
+	}
+
+	view, ok := viewData["view"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid view structure in template")
+	}
+
+	blocks, ok := view["blocks"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("invalid blocks structure in template")
+	}
+
+	for _, block := range blocks {
+		if blockMap, ok := block.(map[string]interface{}); ok && blockMap["block_id"] == "kudo_points" {
+			return viewTemplate, nil
+		}
+	}
+
+	view["blocks"] = append(blocks, map[string]interface{}{
+		"type":     "input",
+		"block_id": "kudo_points",
+		"optional": true,
+		"label": map[string]interface{}{
+			"type":  "plain_text",
+			"text":  "Pontos",
+			"emoji": true,
+		},
+		"hint": map[string]interface{}{
+			"type": "plain_text",
+			"text": fmt.Sprintf("Você tem %d pontos disponíveis este mês. Cada pessoa elogiada recebe os pontos informados.", remaining),
+		},
+		"element": map[string]interface{}{
+			"type":               "number_input",
+			"action_id":          "kudo_points",
+			"is_decimal_allowed": false,
+			"min_value":          "0",
+			"max_value":          strconv.Itoa(remaining),
+			"placeholder": map[string]interface{}{
+				"type": "plain_text",
+				"text": "0",
+			},
+		},
+	})
+
+	result, err := json.Marshal(viewData)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling view template: %w", err)
+	}
+	return string(result), nil
+}
+
+// KudosViewTemplate returns the modal template for a sender
+// The types promoted by admins and the workspace's saved custom types are added to the type options, and when the points system
+// is enabled the points field is added with the sender's balance
+func KudosViewTemplate(ctx context.Context, viewTemplate, teamID, senderID string, cfg *config.Config) string {
+	ctx, span := tracing.Start(ctx, "services.KudosViewTemplate")
+	defer span.End()
+
+	viewTemplate = withOfficialKudoTypes(ctx, viewTemplate, cfg)
+	viewTemplate = withSavedKudoTypes(ctx, viewTemplate, teamID, cfg)
+	if !cfg.PointsEnabled() {
+		return viewTemplate
+	}
+
+	remaining, err := RemainingPoints(ctx, senderID, time.Now(), cfg)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load points balance", "user_id", senderID, "error", err)
+		return viewTemplate
+	}
+
+	withPoints, err := AddPointsInput(viewTemplate, remaining)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not add points field to modal", "error", err)
+		return viewTemplate
+	}
+	return withPoints
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestMonthPeriod(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("LoadLocation() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		t             time.Time
+		loc           *time.Location
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "middle of the month",
+			t:             time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC),
+			loc:           time.UTC,
+			expectedStart: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "december rolls over to next year",
+			t:             time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC),
+			loc:           time.UTC,
+			expectedStart: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "uses the configured timezone",
+			t:             time.Date(2026, 4, 1, 2, 0, 0, 0, time.UTC), // still March 31st in São Paulo
+			loc:           saoPaulo,
+			expectedStart: time.Date(2026, 3, 1, 0, 0, 0, 0, saoPaulo),
+			expectedEnd:   time.Date(2026, 4, 1, 0, 0, 0, 0, saoPaulo),
+		},
+		{
+			name:          "nil location defaults to UTC",
+			t:             time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+			loc:           nil,
+			expectedStart: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := MonthPeriod(tt.t, tt.loc)
+			if !start.Equal(tt.expectedStart) {
+				t.Errorf("start = %v, want %v", start, tt.expectedStart)
+			}
+			if !end.Equal(tt.expectedEnd) {
+				t.Errorf("end = %v, want %v", end, tt.expectedEnd)
+			}
+		})
+	}
+}
+
+func TestRemainingPoints(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		kudos    []*models.Kudo
+		expected int
+	}{
+		{
+			name:     "full balance without kudos",
+			expected: 100,
+		},
+		{
+			name: "points multiplied by recipients",
+			kudos: []*models.Kudo{
+				{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, Points: 10, CreatedAt: now},
+			},
+			expected: 80,
+		},
+		{
+			name: "ignores kudos from previous month",
+			kudos: []*models.Kudo{
+				{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Points: 50, CreatedAt: now.AddDate(0, -1, 0)},
+				{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Points: 5, CreatedAt: now},
+			},
+			expected: 95,
+		},
+		{
+			name: "ignores kudos from other senders",
+			kudos: []*models.Kudo{
+				{SenderID: "U999999", RecipientIDs: []string{"U222222"}, Points: 50, CreatedAt: now},
+			},
+			expected: 100,
+		},
+		{
+			name: "never negative",
+			kudos: []*models.Kudo{
+				{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Points: 150, CreatedAt: now},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemoryStore()
+			for _, kudo := range tt.kudos {
+				store.SaveKudo(context.Background(), kudo)
+			}
+
+			cfg := &config.Config{
+				MonthlyPoints: 100,
+				Location:      time.UTC,
+				Store:         store,
+			}
+
+			remaining, err := RemainingPoints(context.Background(), "U111111", now, cfg)
+			if err != nil {
+				t.Fatalf("RemainingPoints() unexpected error = %v", err)
+			}
+			if remaining != tt.expected {
+				t.Errorf("RemainingPoints() = %d, want %d", remaining, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatBalanceMessage(t *testing.T) {
+	message := FormatBalanceMessage(40, 100, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	if !strings.Contains(message, "*40* de 100 pontos") {
+		t.Errorf("message should contain balance, got %q", message)
+	}
+	if !strings.Contains(message, "01/04/2026") {
+		t.Errorf("message should contain renewal date, got %q", message)
+	}
+}
+
+func TestAddPointsInput(t *testing.T) {
+	template := `{"view":{"type":"modal","blocks":[{"type":"input","block_id":"kudo_message"}]}}`
+
+	result, err := AddPointsInput(template, 30)
+	if err != nil {
+		t.Fatalf("AddPointsInput() unexpected error = %v", err)
+	}
+
+	var viewData map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &viewData); err != nil {
+		t.Fatalf("result is not valid JSON: %v", err)
+	}
+
+	blocks := viewData["view"].(map[string]interface{})["blocks"].([]interface{})
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(blocks))
+	}
+
+	pointsBlock := blocks[1].(map[string]interface{})
+	if pointsBlock["block_id"] != "kudo_points" {
+		t.Errorf("last block should be kudo_points, got %v", pointsBlock["block_id"])
+	}
+	element := pointsBlock["element"].(map[string]interface{})
+	if element["type"] != "number_input" {
+		t.Errorf("points element should be number_input, got %v", element["type"])
+	}
+	if element["max_value"] != "30" {
+		t.Errorf("points element max_value = %v, want the remaining balance", element["max_value"])
+	}
+	hint := pointsBlock["hint"].(map[string]interface{})
+	if !strings.Contains(hint["text"].(string), "30 pontos") {
+		t.Errorf("hint should show remaining balance, got %v", hint["text"])
+	}
+
+	// Adding twice must not duplicate the block
+	again, err := AddPointsInput(result, 30)
+	if err != nil {
+		t.Fatalf("AddPointsInput() unexpected error = %v", err)
+	}
+	if strings.Count(again, "kudo_points") != strings.Count(result, "kudo_points") {
+		t.Error("AddPointsInput() should not add the points block twice")
+	}
+}
+
+func TestAddPointsInput_InvalidTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "invalid JSON", template: `{invalid`},
+		{name: "missing view", template: `{"trigger_id":"123"}`},
+		{name: "missing blocks", template: `{"view":{"type":"modal"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := AddPointsInput(tt.template, 10); err == nil {
+				t.Error("AddPointsInput() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestKudosViewTemplate(t *testing.T) {
+	template := `{"view":{"type":"modal","blocks":[]}}`
+
+	t.Run("disabled points returns template unchanged", func(t *testing.T) {
+		cfg := &config.Config{}
+		if result := KudosViewTemplate(context.Background(), template, "T111111", "U111111", cfg); result != template {
+			t.Errorf("KudosViewTemplate() = %s, want unchanged template", result)
+		}
+	})
+
+	t.Run("enabled points adds the field", func(t *testing.T) {
+		cfg := &config.Config{
+			MonthlyPoints: 50,
+			Location:      time.UTC,
+			Store:         storage.NewMemoryStore(),
+		}
+		result := KudosViewTemplate(context.Background(), template, "T111111", "U111111", cfg)
+		if !strings.Contains(result, "kudo_points") {
+			t.Errorf("KudosViewTemplate() should add kudo_points block, got %s", result)
+		}
+		if !strings.Contains(result, "50 pontos") {
+			t.Errorf("KudosViewTemplate() should show the full balance, got %s", result)
+		}
+	})
+}
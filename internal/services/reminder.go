@@ -1,16 +1,23 @@
 package services
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
 )
 
 // GetChannelMembers retrieves all active members from a Slack channel
-// It handles pagination and filters out bots and deleted users
-func GetChannelMembers(client config.SlackClient, channelID string) ([]string, error) {
+// It handles pagination and filters out bots, deleted users and the excluded users
+func GetChannelMembers(ctx context.Context, client config.SlackClient, channelID string, exclude map[string]bool) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "services.GetChannelMembers")
+	defer span.End()
+
 	var allMembers []string
 	cursor := ""
 
@@ -21,26 +28,12 @@ func GetChannelMembers(client config.SlackClient, channelID string) ([]string, e
 			Limit:     200, // Maximum allowed by Slack API
 		}
 
-		members, nextCursor, err := client.GetUsersInConversation(params)
+		members, nextCursor, err := client.GetUsersInConversationContext(ctx, params)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get channel members: %w", err)
 		}
 
-		// Filter out bots and deleted users
-		for _, userID := range members {
-			userInfo, err := client.GetUserInfo(userID)
-			if err != nil {
-				log.Printf("Warning: could not get user info for %s: %v", userID, err)
-				continue
-			}
-
-			// Skip bots and deleted users
-			if userInfo.IsBot || userInfo.Deleted {
-				continue
-			}
-
-			allMembers = append(allMembers, userID)
-		}
+		allMembers = append(allMembers, activeUsers(ctx, client, members, exclude)...)
 
 		// Check if there are more pages
 		if nextCursor == "" {
@@ -52,14 +45,75 @@ func GetChannelMembers(client config.SlackClient, channelID string) ([]string, e
 	return allMembers, nil
 }
 
+// GetUserGroupMembers retrieves the active members of a Slack user group
+// Like GetChannelMembers it filters out bots, deleted users and the excluded users
+func GetUserGroupMembers(ctx context.Context, client config.SlackClient, userGroupID string, exclude map[string]bool) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "services.GetUserGroupMembers")
+	defer span.End()
+
+	members, err := client.GetUserGroupMembersContext(ctx, userGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user group members: %w", err)
+	}
+
+	return activeUsers(ctx, client, members, exclude), nil
+}
+
+// RecentKudoSenders returns the users who sent kudos since the given time
+func RecentKudoSenders(ctx context.Context, since time.Time, cfg *config.Config) (map[string]bool, error) {
+	ctx, span := tracing.Start(ctx, "services.RecentKudoSenders")
+	defer span.End()
+
+	kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{From: since})
+	if err != nil {
+		return nil, fmt.Errorf("error listing kudos since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	senders := make(map[string]bool)
+	for _, kudo := range kudos {
+		senders[kudo.SenderID] = true
+	}
+	return senders, nil
+}
+
+// activeUsers keeps the users who aren't excluded, bots or deleted
+func activeUsers(ctx context.Context, client config.SlackClient, userIDs []string, exclude map[string]bool) []string {
+	var active []string
+	for _, userID := range userIDs {
+		// Skip users who snoozed or opted out of reminders
+		if exclude[userID] {
+			continue
+		}
+
+		userInfo, err := client.GetUserInfoContext(ctx, userID)
+		if err != nil {
+			slog.WarnContext(ctx, "Could not get user info", "user_id", userID, "error", err)
+			continue
+		}
+
+		// Skip bots and deleted users
+		if userInfo.IsBot || userInfo.Deleted {
+			continue
+		}
+
+		active = append(active, userID)
+	}
+	return active
+}
+
 // SendReminderDM sends a kudos reminder DM to a specific user
-func SendReminderDM(client config.SlackClient, userID string) error {
-	blocks := FormatReminderBlocks()
+// A nil content sends the generic reminder
+func SendReminderDM(ctx context.Context, client config.SlackClient, userID string, style ReminderStyle, content *ReminderContent) error {
+	ctx, span := tracing.Start(ctx, "services.SendReminderDM")
+	defer span.End()
 
-	_, _, err := client.PostMessage(
+	wording, variant := style.wording()
+	blocks := FormatPersonalReminderBlocks(style, content)
+
+	_, _, err := client.PostMessageContext(ctx,
 		userID,
 		slack.MsgOptionBlocks(blocks...),
-		slack.MsgOptionText("Lembrete semanal: envie um elogio para seus colegas!", false),
+		slack.MsgOptionText(wording.Fallbacks[variant], false),
 	)
 
 	if err != nil {
@@ -69,14 +123,63 @@ func SendReminderDM(client config.SlackClient, userID string) error {
 	return nil
 }
 
+// RedirectReminderDM sends the reminder meant for userID to destination instead,
+// noting who would have received it
+// The snooze and opt-out buttons are left out, they would save the preference of whoever clicks them
+func RedirectReminderDM(ctx context.Context, client config.SlackClient, userID, destination string, style ReminderStyle, content *ReminderContent) error {
+	ctx, span := tracing.Start(ctx, "services.RedirectReminderDM")
+	defer span.End()
+
+	wording, _ := style.wording()
+	blocks := append(withoutPreferenceButtons(FormatPersonalReminderBlocks(style, content)), slack.NewContextBlock(
+		"reminder_redirect",
+		&slack.TextBlockObject{
+			Type: slack.MarkdownType,
+			Text: fmt.Sprintf(wording.Redirected, userID),
+		},
+	))
+
+	_, _, err := client.PostMessageContext(ctx,
+		destination,
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionText(fmt.Sprintf(wording.RedirectedText, userID), false),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to redirect DM for user %s to %s: %w", userID, destination, err)
+	}
+
+	return nil
+}
+
+// withoutPreferenceButtons keeps only the button that opens the kudos modal in the reminder actions
+func withoutPreferenceButtons(blocks []slack.Block) []slack.Block {
+	for i, block := range blocks {
+		actions, ok := block.(*slack.ActionBlock)
+		if !ok || actions.BlockID != "reminder_actions" {
+			continue
+		}
+		var elements []slack.BlockElement
+		for _, element := range actions.Elements.ElementSet {
+			if button, ok := element.(*slack.ButtonBlockElement); ok && button.ActionID == "open_kudos_modal" {
+				elements = append(elements, element)
+			}
+		}
+		blocks[i] = slack.NewActionBlock(actions.BlockID, elements...)
+	}
+	return blocks
+}
+
 // FormatReminderBlocks creates the Block Kit structure for the reminder message
-func FormatReminderBlocks() []slack.Block {
+func FormatReminderBlocks(style ReminderStyle) []slack.Block {
+	wording, variant := style.wording()
+
 	return []slack.Block{
 		// Header
 		slack.NewHeaderBlock(
 			&slack.TextBlockObject{
 				Type: slack.PlainTextType,
-				Text: "👋 Lembrete Semanal",
+				Text: wording.Headers[variant],
 			},
 		),
 
@@ -84,7 +187,7 @@ func FormatReminderBlocks() []slack.Block {
 		slack.NewSectionBlock(
 			&slack.TextBlockObject{
 				Type: slack.MarkdownType,
-				Text: "Esta semana alguém realizou algum trabalho excepcional?\n\nUse `/elogie` para enviar um elogio e valorizar as pessoas!",
+				Text: wording.Intros[variant],
 			},
 			nil,
 			nil,
@@ -98,9 +201,38 @@ func FormatReminderBlocks() []slack.Block {
 				"open_modal",
 				&slack.TextBlockObject{
 					Type: slack.PlainTextType,
-					Text: "📝 Elogiar agora!",
+					Text: wording.SendNow,
 				},
 			).WithStyle(slack.StylePrimary),
+			slack.NewButtonBlockElement(
+				ReminderSnoozeWeekAction,
+				"snooze_week",
+				&slack.TextBlockObject{
+					Type: slack.PlainTextType,
+					Text: wording.SnoozeWeek,
+				},
+			),
+			slack.NewButtonBlockElement(
+				ReminderSnoozeMonthAction,
+				"snooze_month",
+				&slack.TextBlockObject{
+					Type: slack.PlainTextType,
+					Text: wording.SnoozeMonth,
+				},
+			),
+			slack.NewButtonBlockElement(
+				ReminderOptOutAction,
+				"opt_out",
+				&slack.TextBlockObject{
+					Type: slack.PlainTextType,
+					Text: wording.OptOut,
+				},
+			).WithConfirm(slack.NewConfirmationBlockObject(
+				&slack.TextBlockObject{Type: slack.PlainTextType, Text: wording.OptOutTitle},
+				&slack.TextBlockObject{Type: slack.MarkdownType, Text: wording.OptOutText},
+				&slack.TextBlockObject{Type: slack.PlainTextType, Text: wording.OptOutConfirm},
+				&slack.TextBlockObject{Type: slack.PlainTextType, Text: wording.Cancel},
+			)),
 		),
 
 		// Divider
@@ -111,7 +243,7 @@ func FormatReminderBlocks() []slack.Block {
 			"reminder_context",
 			&slack.TextBlockObject{
 				Type: slack.MarkdownType,
-				Text: "💡 *Dica:* Elogios específicos e detalhados têm mais impacto!",
+				Text: wording.Tip,
 			},
 		),
 	}
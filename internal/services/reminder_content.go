@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// maxReminderSuggestions caps the colleagues suggested in a reminder
+const maxReminderSuggestions = 3
+
+// ReminderContent personalizes the reminder DM of a user
+type ReminderContent struct {
+	SentThisMonth     int
+	ReceivedThisMonth int
+	Suggestions       []string // colleagues to recognize, at most maxReminderSuggestions
+}
+
+// ReminderPlanner builds the personalized reminder content from kudos loaded once per run
+type ReminderPlanner struct {
+	kudos       []*models.Kudo // ordered by creation time
+	monthStart  time.Time
+	recentSince time.Time
+	teams       []models.Team
+}
+
+// NewReminderPlanner loads the kudos of the current month and of the last cfg.GapWeeks weeks
+func NewReminderPlanner(ctx context.Context, now time.Time, cfg *config.Config) (*ReminderPlanner, error) {
+	ctx, span := tracing.Start(ctx, "services.NewReminderPlanner")
+	defer span.End()
+
+	monthStart, _ := MonthPeriod(now, cfg.Location)
+	recentSince := now.AddDate(0, 0, -7*cfg.GapWeeks)
+
+	from := monthStart
+	if recentSince.Before(from) {
+		from = recentSince
+	}
+
+	kudos, err := cfg.Store.ListKudos(ctx, storage.KudoQuery{From: from, To: now})
+	if err != nil {
+		return nil, fmt.Errorf("error listing kudos for reminders: %w", err)
+	}
+
+	return &ReminderPlanner{
+		kudos:       kudos,
+		monthStart:  monthStart,
+		recentSince: recentSince,
+		teams:       cfg.Teams,
+	}, nil
+}
+
+// ContentFor returns the stats of the user this month and the colleagues they could recognize
+// Teammates without recent kudos come first, then people the user recently recognized
+func (p *ReminderPlanner) ContentFor(userID string) *ReminderContent {
+	content := &ReminderContent{}
+	recognized := make(map[string]bool)
+	var recentlyRecognizedByUser []string
+
+	for _, kudo := range p.kudos {
+		inMonth := !kudo.CreatedAt.Before(p.monthStart)
+		recent := !kudo.CreatedAt.Before(p.recentSince)
+
+		if inMonth && kudo.SenderID == userID {
+			content.SentThisMonth++
+		}
+		if inMonth && slices.Contains(kudo.RecipientIDs, userID) {
+			content.ReceivedThisMonth++
+		}
+		if !recent {
+			continue
+		}
+		for _, recipientID := range kudo.RecipientIDs {
+			recognized[recipientID] = true
+		}
+		if kudo.SenderID == userID {
+			recentlyRecognizedByUser = append(recentlyRecognizedByUser, kudo.RecipientIDs...)
+		}
+	}
+
+	suggest := func(candidateID string) {
+		if len(content.Suggestions) < maxReminderSuggestions && candidateID != userID && !slices.Contains(content.Suggestions, candidateID) {
+			content.Suggestions = append(content.Suggestions, candidateID)
+		}
+	}
+
+	for _, team := range p.teams {
+		if team.ManagerID != userID && !slices.Contains(team.MemberIDs, userID) {
+			continue
+		}
+		for _, memberID := range append([]string{team.ManagerID}, team.MemberIDs...) {
+			if !recognized[memberID] {
+				suggest(memberID)
+			}
+		}
+	}
+
+	// Most recent first
+	for i := len(recentlyRecognizedByUser) - 1; i >= 0; i-- {
+		suggest(recentlyRecognizedByUser[i])
+	}
+
+	return content
+}
+
+// FormatPersonalReminderBlocks creates the reminder DM with the user's stats and suggestions
+// Without content it falls back to the generic reminder
+func FormatPersonalReminderBlocks(style ReminderStyle, content *ReminderContent) []slack.Block {
+	blocks := FormatReminderBlocks(style)
+	if content == nil {
+		return blocks
+	}
+	wording, _ := style.wording()
+
+	// Header and main message come first
+	personal := append([]slack.Block{}, blocks[:2]...)
+
+	personal = append(personal, slack.NewSectionBlock(
+		&slack.TextBlockObject{
+			Type: slack.MarkdownType,
+			Text: fmt.Sprintf(wording.Stats, content.SentThisMonth, content.ReceivedThisMonth),
+		},
+		nil,
+		nil,
+	))
+
+	if len(content.Suggestions) > 0 {
+		personal = append(personal, slack.NewSectionBlock(
+			&slack.TextBlockObject{
+				Type: slack.MarkdownType,
+				Text: wording.SuggestionsTitle,
+			},
+			nil,
+			nil,
+		))
+		for _, userID := range content.Suggestions {
+			personal = append(personal, slack.NewSectionBlock(
+				&slack.TextBlockObject{
+					Type: slack.MarkdownType,
+					Text: fmt.Sprintf("<@%s>", userID),
+				},
+				nil,
+				slack.NewAccessory(
+					slack.NewButtonBlockElement(
+						"open_kudos_modal_for_user",
+						userID,
+						&slack.TextBlockObject{
+							Type: slack.PlainTextType,
+							Text: wording.Suggest,
+						},
+					),
+				),
+			))
+		}
+	}
+
+	return append(personal, blocks[2:]...)
+}
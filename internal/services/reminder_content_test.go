@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestReminderPlanner_ContentFor(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	store := storage.NewMemoryStore()
+	for _, kudo := range []*models.Kudo{
+		// Before the 4-week window
+		{SenderID: "UANA", RecipientIDs: []string{"UCAIO"}, CreatedAt: day(time.January, 10)},
+		// In the window, previous month
+		{SenderID: "UANA", RecipientIDs: []string{"UDANI"}, CreatedAt: day(time.February, 25)},
+		// This month
+		{SenderID: "UANA", RecipientIDs: []string{"UEVA", "UFABIO"}, CreatedAt: day(time.March, 2)},
+		{SenderID: "UBETO", RecipientIDs: []string{"UANA"}, CreatedAt: day(time.March, 5)},
+		{SenderID: "UANA", RecipientIDs: []string{"UGABI"}, CreatedAt: day(time.March, 18)},
+		{SenderID: "UEVA", RecipientIDs: []string{"UBETO"}, CreatedAt: day(time.March, 19)},
+	} {
+		if err := store.SaveKudo(context.Background(), kudo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Store:    store,
+		Location: time.UTC,
+		GapWeeks: 4,
+		Teams: []models.Team{
+			{Name: "Plataforma", ManagerID: "UMGR", MemberIDs: []string{"UANA", "UBETO", "UCAIO"}},
+			{Name: "Dados", ManagerID: "UOTHER", MemberIDs: []string{"UHELO"}},
+		},
+	}
+
+	planner, err := NewReminderPlanner(context.Background(), now, cfg)
+	if err != nil {
+		t.Fatalf("NewReminderPlanner() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		userID          string
+		wantSent        int
+		wantReceived    int
+		wantSuggestions []string
+	}{
+		{
+			// Teammates without recent kudos, then the people recognized most recently
+			name:            "team member",
+			userID:          "UANA",
+			wantSent:        2,
+			wantReceived:    1,
+			wantSuggestions: []string{"UMGR", "UCAIO", "UGABI"},
+		},
+		{
+			name:            "manager",
+			userID:          "UOTHER",
+			wantSuggestions: []string{"UHELO"},
+		},
+		{
+			name:            "without team",
+			userID:          "UEVA",
+			wantSent:        1,
+			wantReceived:    1,
+			wantSuggestions: []string{"UBETO"},
+		},
+		{
+			name:   "no history",
+			userID: "UNEW",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := planner.ContentFor(tt.userID)
+
+			if content.SentThisMonth != tt.wantSent || content.ReceivedThisMonth != tt.wantReceived {
+				t.Errorf("sent %d, received %d, want %d and %d", content.SentThisMonth, content.ReceivedThisMonth, tt.wantSent, tt.wantReceived)
+			}
+			if strings.Join(content.Suggestions, ",") != strings.Join(tt.wantSuggestions, ",") {
+				t.Errorf("Suggestions = %v, want %v", content.Suggestions, tt.wantSuggestions)
+			}
+		})
+	}
+}
+
+func TestNewReminderPlanner_StoreError(t *testing.T) {
+	cfg := &config.Config{Store: &failingStore{}, Location: time.UTC, GapWeeks: 4}
+
+	if _, err := NewReminderPlanner(context.Background(), time.Now(), cfg); err == nil {
+		t.Error("NewReminderPlanner() expected error, got nil")
+	}
+}
+
+func TestFormatPersonalReminderBlocks(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     *ReminderContent
+		wantBlocks  int
+		wantText    []string
+		wantButtons int
+	}{
+		{
+			name:       "generic without content",
+			content:    nil,
+			wantBlocks: 5,
+		},
+		{
+			name:       "stats without suggestions",
+			content:    &ReminderContent{SentThisMonth: 2, ReceivedThisMonth: 3},
+			wantBlocks: 6,
+			wantText:   []string{"enviou *2 elogio(s)* e recebeu *3*"},
+		},
+		{
+			name:        "stats and suggestions",
+			content:     &ReminderContent{Suggestions: []string{"U111111", "U222222"}},
+			wantBlocks:  9,
+			wantText:    []string{"enviou *0 elogio(s)*", "<@U111111>", "<@U222222>"},
+			wantButtons: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := FormatPersonalReminderBlocks(ReminderStyle{}, tt.content)
+
+			if len(blocks) != tt.wantBlocks {
+				t.Fatalf("FormatPersonalReminderBlocks() returned %d blocks, want %d", len(blocks), tt.wantBlocks)
+			}
+			if blocks[0].BlockType() != slack.MBTHeader || blocks[len(blocks)-1].BlockType() != slack.MBTContext {
+				t.Errorf("blocks should start with the header and end with the tip")
+			}
+
+			var texts []string
+			buttons := 0
+			for _, block := range blocks {
+				section, ok := block.(*slack.SectionBlock)
+				if !ok {
+					continue
+				}
+				texts = append(texts, section.Text.Text)
+				if section.Accessory != nil && section.Accessory.ButtonElement != nil {
+					if section.Accessory.ButtonElement.ActionID != "open_kudos_modal_for_user" {
+						t.Errorf("button action = %s, want open_kudos_modal_for_user", section.Accessory.ButtonElement.ActionID)
+					}
+					buttons++
+				}
+			}
+			joined := strings.Join(texts, "\n")
+			for _, want := range tt.wantText {
+				if !strings.Contains(joined, want) {
+					t.Errorf("texts = %q, want %q", joined, want)
+				}
+			}
+			if buttons != tt.wantButtons {
+				t.Errorf("%d suggestion buttons, want %d", buttons, tt.wantButtons)
+			}
+
+			// The generic reminder keeps its preference buttons
+			if body, _ := json.Marshal(blocks); !strings.Contains(string(body), ReminderOptOutAction) {
+				t.Error("blocks should keep the reminder preference buttons")
+			}
+		})
+	}
+}
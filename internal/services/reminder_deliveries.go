@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// ReminderPeriod returns the ISO week of t in loc, such as 2026-W11
+// Runs in the same week share the period, so each user is reminded once a week
+func ReminderPeriod(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	year, week := t.In(loc).ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// DeliveredReminders returns the users already reminded in the period
+func DeliveredReminders(ctx context.Context, period string, cfg *config.Config) (map[string]bool, error) {
+	ctx, span := tracing.Start(ctx, "services.DeliveredReminders")
+	defer span.End()
+
+	deliveries, err := cfg.Store.ListReminderDeliveries(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reminder deliveries for %s: %w", period, err)
+	}
+
+	delivered := make(map[string]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		delivered[delivery.UserID] = true
+	}
+	return delivered, nil
+}
+
+// RecordReminderDelivery records that the user was reminded in the period by the event's run
+func RecordReminderDelivery(ctx context.Context, period, userID, eventID string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.RecordReminderDelivery")
+	defer span.End()
+
+	err := cfg.Store.SaveReminderDelivery(ctx, &models.ReminderDelivery{
+		Period:      period,
+		UserID:      userID,
+		EventID:     eventID,
+		DeliveredAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record reminder delivery for %s: %w", userID, err)
+	}
+	return nil
+}
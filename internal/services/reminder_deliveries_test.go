@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestReminderPeriod(t *testing.T) {
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		loc      *time.Location
+		expected string
+	}{
+		{name: "mid week", t: time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC), loc: time.UTC, expected: "2026-W11"},
+		{name: "single digit week", t: time.Date(2026, 1, 7, 12, 0, 0, 0, time.UTC), loc: time.UTC, expected: "2026-W02"},
+		// Monday 01:00 UTC is still Sunday in São Paulo
+		{name: "uses the location", t: time.Date(2026, 3, 16, 1, 0, 0, 0, time.UTC), loc: saoPaulo, expected: "2026-W11"},
+		{name: "ISO year", t: time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), loc: time.UTC, expected: "2026-W53"},
+		{name: "nil location is UTC", t: time.Date(2026, 3, 16, 1, 0, 0, 0, time.UTC), expected: "2026-W12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReminderPeriod(tt.t, tt.loc); got != tt.expected {
+				t.Errorf("ReminderPeriod() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecordAndDeliveredReminders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Store: storage.NewMemoryStore()}
+
+	for _, userID := range []string{"U111111", "U222222"} {
+		if err := RecordReminderDelivery(ctx, "2026-W11", userID, "event-1", now, cfg); err != nil {
+			t.Fatalf("RecordReminderDelivery() unexpected error = %v", err)
+		}
+	}
+	if err := RecordReminderDelivery(ctx, "2026-W10", "U333333", "event-0", now, cfg); err != nil {
+		t.Fatalf("RecordReminderDelivery() unexpected error = %v", err)
+	}
+
+	delivered, err := DeliveredReminders(ctx, "2026-W11", cfg)
+	if err != nil {
+		t.Fatalf("DeliveredReminders() unexpected error = %v", err)
+	}
+	if len(delivered) != 2 || !delivered["U111111"] || !delivered["U222222"] {
+		t.Errorf("DeliveredReminders() = %v, want U111111 and U222222", delivered)
+	}
+}
+
+// failingDeliveryStore is a storage.Store whose delivery records always fail
+type failingDeliveryStore struct {
+	storage.Store
+}
+
+func (s *failingDeliveryStore) SaveReminderDelivery(ctx context.Context, delivery *models.ReminderDelivery) error {
+	return errors.New("unavailable")
+}
+
+func (s *failingDeliveryStore) ListReminderDeliveries(ctx context.Context, period string) ([]*models.ReminderDelivery, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestReminderDeliveries_StoreError(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{Store: &failingDeliveryStore{}}
+
+	if _, err := DeliveredReminders(ctx, "2026-W11", cfg); err == nil {
+		t.Error("DeliveredReminders() expected error, got nil")
+	}
+	if err := RecordReminderDelivery(ctx, "2026-W11", "U111111", "event-1", time.Now(), cfg); err == nil {
+		t.Error("RecordReminderDelivery() expected error, got nil")
+	}
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/tracing"
+)
+
+// Action IDs of the reminder preference buttons
+const (
+	ReminderSnoozeWeekAction  = "reminder_snooze_week"
+	ReminderSnoozeMonthAction = "reminder_snooze_month"
+	ReminderOptOutAction      = "reminder_opt_out"
+	ReminderResumeAction      = "reminder_resume"
+)
+
+// IsReminderPreferenceAction reports whether the action ID belongs to a reminder preference button
+func IsReminderPreferenceAction(actionID string) bool {
+	switch actionID {
+	case ReminderSnoozeWeekAction, ReminderSnoozeMonthAction, ReminderOptOutAction, ReminderResumeAction:
+		return true
+	}
+	return false
+}
+
+// NewReminderPreference builds the preference chosen with a reminder button at now
+// Snoozing for a week skips the next reminder of the schedule, see snoozeWeekEnd
+func NewReminderPreference(userID, actionID string, now time.Time, loc *time.Location, schedule models.ReminderSettings) (*models.ReminderPreference, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	preference := &models.ReminderPreference{UserID: userID, UpdatedAt: now}
+
+	switch actionID {
+	case ReminderSnoozeWeekAction:
+		preference.SnoozedUntil = snoozeWeekEnd(now, loc, schedule)
+	case ReminderSnoozeMonthAction:
+		preference.SnoozedUntil = now.AddDate(0, 1, 0)
+	case ReminderOptOutAction:
+		preference.OptedOut = true
+	case ReminderResumeAction:
+	default:
+		return nil, fmt.Errorf("unknown reminder action %q", actionID)
+	}
+
+	return preference, nil
+}
+
+// nextReminderPeriodStart returns the start of the reminder period after the one of t, the next Monday at midnight in loc
+func nextReminderPeriodStart(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	daysToMonday := (8 - int(local.Weekday())) % 7
+	if daysToMonday == 0 {
+		daysToMonday = 7
+	}
+	return time.Date(local.Year(), local.Month(), local.Day()+daysToMonday, 0, 0, 0, 0, loc)
+}
+
+// snoozeWeekEnd returns when a week snooze chosen at now ends: at the end of the period of the next reminder, so it's skipped
+// The reminder of the current period is still ahead when the schedule's weekday and hour haven't passed,
+// without a weekday the reminder was already sent and the next one is in the next period
+func snoozeWeekEnd(now time.Time, loc *time.Location, schedule models.ReminderSettings) time.Time {
+	next := nextReminderPeriodStart(now, loc)
+	if schedule.Weekday > 0 && !schedule.Due(now.In(loc)) {
+		next = now
+	}
+	return nextReminderPeriodStart(next, loc)
+}
+
+// SkippedReminderUsers returns the users who shouldn't be reminded at now
+func SkippedReminderUsers(ctx context.Context, now time.Time, cfg *config.Config) (map[string]bool, error) {
+	ctx, span := tracing.Start(ctx, "services.SkippedReminderUsers")
+	defer span.End()
+
+	preferences, err := cfg.Store.ListReminderPreferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reminder preferences: %w", err)
+	}
+
+	skipped := make(map[string]bool)
+	for _, preference := range preferences {
+		if preference.Skips(now) {
+			skipped[preference.UserID] = true
+		}
+	}
+	return skipped, nil
+}
+
+// FormatReminderPreferenceBlocks creates the message replacing the reminder DM once a choice is made
+func FormatReminderPreferenceBlocks(preference *models.ReminderPreference, actionID string, loc *time.Location) []slack.Block {
+	if loc == nil {
+		loc = time.UTC
+	}
+	var text string
+	switch {
+	case preference.OptedOut:
+		text = "🔕 Pronto, você não receberá mais lembretes semanais.\n\nVocê ainda pode usar `/elogie` sempre que quiser."
+	case actionID == ReminderSnoozeWeekAction:
+		text = fmt.Sprintf("👍 Combinado! Pularemos o próximo lembrete, até *%s*.", preference.SnoozedUntil.In(loc).Format("02/01/2006"))
+	case !preference.SnoozedUntil.IsZero():
+		text = fmt.Sprintf("⏸️ Lembretes pausados até *%s*.", preference.SnoozedUntil.In(loc).Format("02/01/2006"))
+	default:
+		text = "🔔 Lembretes reativados! Você voltará a receber o lembrete semanal."
+	}
+
+	elements := []slack.BlockElement{
+		slack.NewButtonBlockElement(
+			"open_kudos_modal",
+			"open_modal",
+			&slack.TextBlockObject{Type: slack.PlainTextType, Text: "📝 Elogiar agora!"},
+		),
+	}
+	if preference.OptedOut || actionID == ReminderSnoozeMonthAction {
+		elements = append(elements, slack.NewButtonBlockElement(
+			ReminderResumeAction,
+			"resume",
+			&slack.TextBlockObject{Type: slack.PlainTextType, Text: "🔔 Voltar a receber lembretes"},
+		))
+	}
+
+	return []slack.Block{
+		slack.NewSectionBlock(
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: text},
+			nil,
+			nil,
+		),
+		slack.NewActionBlock("reminder_actions", elements...),
+	}
+}
+
+// SaveReminderPreference records the user's choice and updates the reminder DM in place
+func SaveReminderPreference(ctx context.Context, userID, actionID, channelID, timestamp string, now time.Time, cfg *config.Config) error {
+	ctx, span := tracing.Start(ctx, "services.SaveReminderPreference")
+	defer span.End()
+
+	// The week snooze follows the schedule admins set
+	settings := LoadSettings(ctx, cfg)
+	preference, err := NewReminderPreference(userID, actionID, now, cfg.Location, settings.Reminder)
+	if err != nil {
+		return err
+	}
+
+	if err := cfg.Store.SaveReminderPreference(ctx, preference); err != nil {
+		return fmt.Errorf("failed to save reminder preference: %w", err)
+	}
+
+	blocks := FormatReminderPreferenceBlocks(preference, actionID, cfg.Location)
+	_, _, _, err = cfg.SlackAPI.UpdateMessageContext(ctx,
+		channelID,
+		timestamp,
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionText("Preferência de lembretes atualizada", false),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update reminder message: %w", err)
+	}
+
+	return nil
+}
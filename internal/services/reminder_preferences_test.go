@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+func TestNewReminderPreference(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+	// Wednesday
+	now := time.Date(2026, 3, 11, 15, 0, 0, 0, loc)
+
+	tests := []struct {
+		name          string
+		actionID      string
+		now           time.Time
+		schedule      models.ReminderSettings
+		wantOptedOut  bool
+		wantSnoozedTo time.Time
+		wantErr       bool
+	}{
+		{
+			name:          "next week skips next week's reminder",
+			actionID:      ReminderSnoozeWeekAction,
+			now:           now,
+			wantSnoozedTo: time.Date(2026, 3, 23, 0, 0, 0, 0, loc),
+		},
+		{
+			name:          "next week on a Monday",
+			actionID:      ReminderSnoozeWeekAction,
+			now:           time.Date(2026, 3, 9, 10, 5, 0, 0, loc),
+			wantSnoozedTo: time.Date(2026, 3, 23, 0, 0, 0, 0, loc),
+		},
+		{
+			name:          "next week on a Sunday",
+			actionID:      ReminderSnoozeWeekAction,
+			now:           time.Date(2026, 3, 15, 22, 0, 0, 0, loc),
+			wantSnoozedTo: time.Date(2026, 3, 23, 0, 0, 0, 0, loc),
+		},
+		{
+			name:          "next week before this week's scheduled reminder",
+			actionID:      ReminderSnoozeWeekAction,
+			now:           now,
+			schedule:      models.ReminderSettings{Weekday: 5, Hour: 14},
+			wantSnoozedTo: time.Date(2026, 3, 16, 0, 0, 0, 0, loc),
+		},
+		{
+			name:          "next week after this week's scheduled reminder",
+			actionID:      ReminderSnoozeWeekAction,
+			now:           now,
+			schedule:      models.ReminderSettings{Weekday: 3, Hour: 9},
+			wantSnoozedTo: time.Date(2026, 3, 23, 0, 0, 0, 0, loc),
+		},
+		{
+			name:          "pause for a month",
+			actionID:      ReminderSnoozeMonthAction,
+			now:           now,
+			wantSnoozedTo: time.Date(2026, 4, 11, 15, 0, 0, 0, loc),
+		},
+		{
+			name:         "opt out",
+			actionID:     ReminderOptOutAction,
+			now:          now,
+			wantOptedOut: true,
+		},
+		{
+			name:     "resume clears everything",
+			actionID: ReminderResumeAction,
+			now:      now,
+		},
+		{
+			name:     "unknown action",
+			actionID: "open_kudos_modal",
+			now:      now,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preference, err := NewReminderPreference("U111111", tt.actionID, tt.now, loc, tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewReminderPreference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if preference.UserID != "U111111" || !preference.UpdatedAt.Equal(tt.now) {
+				t.Errorf("preference = %+v, want U111111 updated at %v", preference, tt.now)
+			}
+			if preference.OptedOut != tt.wantOptedOut {
+				t.Errorf("OptedOut = %v, want %v", preference.OptedOut, tt.wantOptedOut)
+			}
+			if !preference.SnoozedUntil.Equal(tt.wantSnoozedTo) {
+				t.Errorf("SnoozedUntil = %v, want %v", preference.SnoozedUntil, tt.wantSnoozedTo)
+			}
+		})
+	}
+}
+
+func TestSnoozeWeek_SkipsOneReminder(t *testing.T) {
+	loc, _ := time.LoadLocation("America/Sao_Paulo")
+
+	tests := []struct {
+		name     string
+		schedule models.ReminderSettings
+		runs     []time.Time // reminder runs after the snooze, in consecutive periods
+		snoozeAt time.Time
+	}{
+		{
+			name:     "weekly runs on Monday",
+			runs:     []time.Time{time.Date(2026, 3, 16, 9, 0, 0, 0, loc), time.Date(2026, 3, 23, 9, 0, 0, 0, loc)},
+			snoozeAt: time.Date(2026, 3, 9, 9, 30, 0, 0, loc),
+		},
+		{
+			name:     "scheduled on Friday, snoozed after the reminder",
+			schedule: models.ReminderSettings{Weekday: 5, Hour: 14},
+			runs:     []time.Time{time.Date(2026, 3, 20, 14, 0, 0, 0, loc), time.Date(2026, 3, 27, 14, 0, 0, 0, loc)},
+			snoozeAt: time.Date(2026, 3, 13, 16, 0, 0, 0, loc),
+		},
+		{
+			name:     "scheduled on Friday, snoozed from an older reminder",
+			schedule: models.ReminderSettings{Weekday: 5, Hour: 14},
+			runs:     []time.Time{time.Date(2026, 3, 13, 14, 0, 0, 0, loc), time.Date(2026, 3, 20, 14, 0, 0, 0, loc)},
+			snoozeAt: time.Date(2026, 3, 11, 10, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storage.NewMemoryStore()
+			store.SaveSettings(ctx, &models.Settings{Reminder: tt.schedule})
+			cfg := &config.Config{Store: store, Location: loc, SlackAPI: &MockSlackClient{}}
+
+			if err := SaveReminderPreference(ctx, "U111111", ReminderSnoozeWeekAction, "D111111", "1700000000.000001", tt.snoozeAt, cfg); err != nil {
+				t.Fatalf("SaveReminderPreference() unexpected error = %v", err)
+			}
+
+			// The next run skips the user, the one in the following period reminds them again
+			for i, run := range tt.runs {
+				skipped, err := SkippedReminderUsers(ctx, run, cfg)
+				if err != nil {
+					t.Fatalf("SkippedReminderUsers() unexpected error = %v", err)
+				}
+				if want := i == 0; skipped["U111111"] != want {
+					t.Errorf("run on %s (%s) skipped = %v, want %v", run.Format("02/01"), ReminderPeriod(run, loc), skipped["U111111"], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSkippedReminderUsers(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+	for _, preference := range []*models.ReminderPreference{
+		{UserID: "UOUT", OptedOut: true},
+		{UserID: "USNOOZED", SnoozedUntil: now.Add(time.Hour)},
+		{UserID: "UEXPIRED", SnoozedUntil: now},
+		{UserID: "URESUMED"},
+	} {
+		if err := store.SaveReminderPreference(context.Background(), preference); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	skipped, err := SkippedReminderUsers(context.Background(), now, &config.Config{Store: store})
+	if err != nil {
+		t.Fatalf("SkippedReminderUsers() unexpected error = %v", err)
+	}
+	if len(skipped) != 2 || !skipped["UOUT"] || !skipped["USNOOZED"] {
+		t.Errorf("SkippedReminderUsers() = %v, want UOUT and USNOOZED", skipped)
+	}
+}
+
+// failingPreferenceStore is a storage.Store whose preference queries always fail
+type failingPreferenceStore struct {
+	storage.Store
+}
+
+func (s *failingPreferenceStore) ListReminderPreferences(ctx context.Context) ([]*models.ReminderPreference, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestSkippedReminderUsers_StoreError(t *testing.T) {
+	if _, err := SkippedReminderUsers(context.Background(), time.Now(), &config.Config{Store: &failingPreferenceStore{}}); err == nil {
+		t.Error("SkippedReminderUsers() expected error, got nil")
+	}
+}
+
+func TestFormatReminderPreferenceBlocks(t *testing.T) {
+	until := time.Date(2026, 4, 11, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		preference *models.ReminderPreference
+		actionID   string
+		wantText   string
+		wantResume bool
+	}{
+		{
+			name:       "next week",
+			preference: &models.ReminderPreference{SnoozedUntil: until},
+			actionID:   ReminderSnoozeWeekAction,
+			wantText:   "próximo lembrete",
+		},
+		{
+			name:       "paused",
+			preference: &models.ReminderPreference{SnoozedUntil: until},
+			actionID:   ReminderSnoozeMonthAction,
+			wantText:   "11/04/2026",
+			wantResume: true,
+		},
+		{
+			name:       "opted out",
+			preference: &models.ReminderPreference{OptedOut: true},
+			actionID:   ReminderOptOutAction,
+			wantText:   "não receberá mais",
+			wantResume: true,
+		},
+		{
+			name:       "resumed",
+			preference: &models.ReminderPreference{},
+			actionID:   ReminderResumeAction,
+			wantText:   "reativados",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := FormatReminderPreferenceBlocks(tt.preference, tt.actionID, time.UTC)
+
+			body, _ := json.Marshal(blocks)
+			if !strings.Contains(string(body), tt.wantText) {
+				t.Errorf("blocks = %s, want text containing %q", body, tt.wantText)
+			}
+			if resume := strings.Contains(string(body), ReminderResumeAction); resume != tt.wantResume {
+				t.Errorf("resume button = %v, want %v", resume, tt.wantResume)
+			}
+		})
+	}
+}
+
+func TestFormatReminderBlocks_PreferenceButtons(t *testing.T) {
+	actionBlock, ok := FormatReminderBlocks(ReminderStyle{})[2].(*slack.ActionBlock)
+	if !ok {
+		t.Fatal("Third block is not an ActionBlock")
+	}
+
+	var actionIDs []string
+	for _, element := range actionBlock.Elements.ElementSet {
+		if button, ok := element.(*slack.ButtonBlockElement); ok {
+			actionIDs = append(actionIDs, button.ActionID)
+		}
+	}
+
+	want := "open_kudos_modal,reminder_snooze_week,reminder_snooze_month,reminder_opt_out"
+	if strings.Join(actionIDs, ",") != want {
+		t.Errorf("buttons = %v, want %s", actionIDs, want)
+	}
+	for _, actionID := range actionIDs[1:] {
+		if !IsReminderPreferenceAction(actionID) {
+			t.Errorf("IsReminderPreferenceAction(%s) = false, want true", actionID)
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
 )
 
 // ExtendedMockSlackClient extends the MockSlackClient with reminder-specific methods
@@ -14,14 +20,14 @@ type ExtendedMockSlackClient struct {
 	GetUserInfoFunc            func(user string) (*slack.User, error)
 }
 
-func (m *ExtendedMockSlackClient) GetUsersInConversation(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+func (m *ExtendedMockSlackClient) GetUsersInConversationContext(ctx context.Context, params *slack.GetUsersInConversationParameters) ([]string, string, error) {
 	if m.GetUsersInConversationFunc != nil {
 		return m.GetUsersInConversationFunc(params)
 	}
 	return []string{"U123456", "U789012"}, "", nil
 }
 
-func (m *ExtendedMockSlackClient) GetUserInfo(user string) (*slack.User, error) {
+func (m *ExtendedMockSlackClient) GetUserInfoContext(ctx context.Context, user string) (*slack.User, error) {
 	if m.GetUserInfoFunc != nil {
 		return m.GetUserInfoFunc(user)
 	}
@@ -37,6 +43,7 @@ func TestGetChannelMembers(t *testing.T) {
 		name                string
 		mockUsersFunc       func(params *slack.GetUsersInConversationParameters) ([]string, string, error)
 		mockUserInfoFunc    func(user string) (*slack.User, error)
+		exclude             map[string]bool
 		expectedMembers     []string
 		expectedMemberCount int
 		wantErr             bool
@@ -167,6 +174,21 @@ func TestGetChannelMembers(t *testing.T) {
 			expectedMemberCount: 2, // UBAD222 is skipped
 			wantErr:             false,
 		},
+		{
+			name: "skip excluded users",
+			mockUsersFunc: func(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
+				return []string{"U111111", "USNOOZE", "U333333"}, "", nil
+			},
+			mockUserInfoFunc: func(user string) (*slack.User, error) {
+				if user == "USNOOZE" {
+					t.Error("excluded users should not be looked up")
+				}
+				return &slack.User{ID: user}, nil
+			},
+			exclude:             map[string]bool{"USNOOZE": true},
+			expectedMemberCount: 2,
+			wantErr:             false,
+		},
 		{
 			name: "empty channel",
 			mockUsersFunc: func(params *slack.GetUsersInConversationParameters) ([]string, string, error) {
@@ -184,7 +206,7 @@ func TestGetChannelMembers(t *testing.T) {
 				GetUserInfoFunc:            tt.mockUserInfoFunc,
 			}
 
-			members, err := GetChannelMembers(mockSlack, "C123456")
+			members, err := GetChannelMembers(context.Background(), mockSlack, "C123456", tt.exclude)
 
 			if tt.wantErr {
 				if err == nil {
@@ -204,6 +226,81 @@ func TestGetChannelMembers(t *testing.T) {
 	}
 }
 
+func TestGetUserGroupMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []string
+		err      error
+		exclude  map[string]bool
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "filters bots and excluded users",
+			members:  []string{"U111111", "UBOT123", "U333333"},
+			exclude:  map[string]bool{"U333333": true},
+			expected: []string{"U111111"},
+		},
+		{
+			name:    "Slack API error",
+			err:     errors.New("no_such_subteam"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSlack := &ExtendedMockSlackClient{
+				MockSlackClient: MockSlackClient{
+					GetUserGroupMembersFunc: func(userGroup string) ([]string, error) {
+						if userGroup != "S0DEVS" {
+							t.Errorf("user group = %s, want S0DEVS", userGroup)
+						}
+						return tt.members, tt.err
+					},
+				},
+				GetUserInfoFunc: func(user string) (*slack.User, error) {
+					return &slack.User{ID: user, IsBot: user == "UBOT123"}, nil
+				},
+			}
+
+			members, err := GetUserGroupMembers(context.Background(), mockSlack, "S0DEVS", tt.exclude)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetUserGroupMembers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(members, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("GetUserGroupMembers() = %v, want %v", members, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecentKudoSenders(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	store := storage.NewMemoryStore()
+	for _, kudo := range []*models.Kudo{
+		{SenderID: "U111111", RecipientIDs: []string{"U222222"}, CreatedAt: now.AddDate(0, 0, -20)},
+		{SenderID: "U222222", RecipientIDs: []string{"U111111"}, CreatedAt: now.AddDate(0, 0, -2)},
+		{SenderID: "U333333", RecipientIDs: []string{"U111111"}, CreatedAt: now.AddDate(0, 0, -1)},
+	} {
+		if err := store.SaveKudo(context.Background(), kudo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	senders, err := RecentKudoSenders(context.Background(), now.AddDate(0, 0, -7), &config.Config{Store: store})
+	if err != nil {
+		t.Fatalf("RecentKudoSenders() unexpected error = %v", err)
+	}
+	if len(senders) != 2 || !senders["U222222"] || !senders["U333333"] {
+		t.Errorf("RecentKudoSenders() = %v, want U222222 and U333333", senders)
+	}
+
+	if _, err := RecentKudoSenders(context.Background(), now, &config.Config{Store: &failingStore{}}); err == nil {
+		t.Error("RecentKudoSenders() expected error, got nil")
+	}
+}
+
 func TestSendReminderDM(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -251,7 +348,7 @@ func TestSendReminderDM(t *testing.T) {
 				},
 			}
 
-			err := SendReminderDM(mockSlack, tt.userID)
+			err := SendReminderDM(context.Background(), mockSlack, tt.userID, ReminderStyle{}, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -268,13 +365,65 @@ func TestSendReminderDM(t *testing.T) {
 	}
 }
 
+func TestRedirectReminderDM(t *testing.T) {
+	tests := []struct {
+		name    string
+		postErr error
+		wantErr bool
+	}{
+		{name: "posts to the destination"},
+		{name: "Slack API error", postErr: errors.New("channel_not_found"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotChannel string
+			var gotOptions []slack.MsgOption
+			mockSlack := &ExtendedMockSlackClient{
+				MockSlackClient: MockSlackClient{
+					PostMessageFunc: func(channelID string, options ...slack.MsgOption) (string, string, error) {
+						gotChannel = channelID
+						gotOptions = options
+						return channelID, "1234567890.123456", tt.postErr
+					},
+				},
+			}
+
+			err := RedirectReminderDM(context.Background(), mockSlack, "U123456", "C0TESTS", ReminderStyle{}, nil)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RedirectReminderDM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotChannel != "C0TESTS" {
+				t.Errorf("posted to %s, want C0TESTS", gotChannel)
+			}
+
+			_, values, err := slack.UnsafeApplyMsgOptions("xoxb-test", gotChannel, "https://slack.com/api/", gotOptions...)
+			if err != nil {
+				t.Fatalf("UnsafeApplyMsgOptions() unexpected error = %v", err)
+			}
+			if !contains(values.Get("blocks"), "reminder_redirect") || !contains(values.Get("text"), "<@U123456>") {
+				t.Errorf("text = %q, want the redirect note for the original recipient", values.Get("text"))
+			}
+			for _, actionID := range []string{ReminderSnoozeWeekAction, ReminderSnoozeMonthAction, ReminderOptOutAction} {
+				if contains(values.Get("blocks"), actionID) {
+					t.Errorf("blocks = %s, want no %s button, it would save the tester's preference", values.Get("blocks"), actionID)
+				}
+			}
+			if !contains(values.Get("blocks"), "open_kudos_modal") {
+				t.Errorf("blocks = %s, want the button that opens the kudos modal", values.Get("blocks"))
+			}
+		})
+	}
+}
+
 func TestFormatReminderBlocks(t *testing.T) {
-	blocks := FormatReminderBlocks()
+	blocks := FormatReminderBlocks(ReminderStyle{})
 
 	// Verify we have the expected number of blocks
 	expectedBlockCount := 5 // Header, Section, Action, Divider, Context
 	if len(blocks) != expectedBlockCount {
-		t.Errorf("FormatReminderBlocks() returned %d blocks, want %d", len(blocks), expectedBlockCount)
+		t.Errorf("FormatReminderBlocks(ReminderStyle{}) returned %d blocks, want %d", len(blocks), expectedBlockCount)
 	}
 
 	// Verify first block is a header
@@ -304,7 +453,7 @@ func TestFormatReminderBlocks(t *testing.T) {
 }
 
 func TestFormatReminderBlocks_Content(t *testing.T) {
-	blocks := FormatReminderBlocks()
+	blocks := FormatReminderBlocks(ReminderStyle{})
 
 	// Check header content
 	headerBlock, ok := blocks[0].(*slack.HeaderBlock)
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Locales the reminder DM can be written in
+const (
+	LocalePortuguese = "pt-BR"
+	LocaleEnglish    = "en"
+)
+
+// Reminder message variants
+const (
+	ReminderVariantWeekly   = "weekly"
+	ReminderVariantMonthEnd = "month_end"
+)
+
+// ReminderStyle selects the wording of a reminder DM
+// The zero value is the weekly reminder in Portuguese
+type ReminderStyle struct {
+	Variant string
+	Locale  string
+}
+
+// Validate reports an unknown variant or locale
+func (s ReminderStyle) Validate() error {
+	if s.Variant != "" && !slices.Contains([]string{ReminderVariantWeekly, ReminderVariantMonthEnd}, s.Variant) {
+		return fmt.Errorf("unknown reminder variant %q", s.Variant)
+	}
+	if s.Locale != "" {
+		if _, ok := reminderTexts[s.Locale]; !ok {
+			return fmt.Errorf("unsupported reminder locale %q", s.Locale)
+		}
+	}
+	return nil
+}
+
+// reminderWording holds the texts of the reminder DM in one locale
+type reminderWording struct {
+	Headers          map[string]string // by variant
+	Intros           map[string]string // by variant
+	Fallbacks        map[string]string // notification text by variant
+	SendNow          string
+	SnoozeWeek       string
+	SnoozeMonth      string
+	OptOut           string
+	OptOutTitle      string
+	OptOutText       string
+	OptOutConfirm    string
+	Cancel           string
+	Tip              string
+	Stats            string // sent and received counts
+	SuggestionsTitle string
+	Suggest          string
+	Redirected       string // original recipient
+	RedirectedText   string // original recipient
+}
+
+var reminderTexts = map[string]reminderWording{
+	LocalePortuguese: {
+		Headers: map[string]string{
+			ReminderVariantWeekly:   "👋 Lembrete Semanal",
+			ReminderVariantMonthEnd: "📅 O mês está acabando",
+		},
+		Intros: map[string]string{
+			ReminderVariantWeekly:   "Esta semana alguém realizou algum trabalho excepcional?\n\nUse `/elogie` para enviar um elogio e valorizar as pessoas!",
+			ReminderVariantMonthEnd: "Antes de o mês acabar, que tal reconhecer quem fez a diferença?\n\nUse `/elogie` para enviar um elogio e valorizar as pessoas!",
+		},
+		Fallbacks: map[string]string{
+			ReminderVariantWeekly:   "Lembrete semanal: envie um elogio para seus colegas!",
+			ReminderVariantMonthEnd: "O mês está acabando: envie um elogio para seus colegas!",
+		},
+		SendNow:          "📝 Elogiar agora!",
+		SnoozeWeek:       "Lembrar na próxima semana",
+		SnoozeMonth:      "Pausar por 1 mês",
+		OptOut:           "Não quero lembretes",
+		OptOutTitle:      "Parar os lembretes?",
+		OptOutText:       "Você não receberá mais o lembrete semanal. Dá para voltar atrás pela própria mensagem.",
+		OptOutConfirm:    "Parar lembretes",
+		Cancel:           "Cancelar",
+		Tip:              "💡 *Dica:* Elogios específicos e detalhados têm mais impacto!",
+		Stats:            "📊 Este mês você enviou *%d elogio(s)* e recebeu *%d*.",
+		SuggestionsTitle: "*Que tal reconhecer uma destas pessoas?*",
+		Suggest:          "📝 Elogiar",
+		Redirected:       "🧪 *Redirecionado:* este lembrete seria enviado para <@%s>",
+		RedirectedText:   "Lembrete semanal para <@%s> (redirecionado)",
+	},
+	LocaleEnglish: {
+		Headers: map[string]string{
+			ReminderVariantWeekly:   "👋 Weekly Reminder",
+			ReminderVariantMonthEnd: "📅 The month is almost over",
+		},
+		Intros: map[string]string{
+			ReminderVariantWeekly:   "Did someone do outstanding work this week?\n\nUse `/elogie` to send kudos and show your appreciation!",
+			ReminderVariantMonthEnd: "Before the month ends, how about recognizing who made a difference?\n\nUse `/elogie` to send kudos and show your appreciation!",
+		},
+		Fallbacks: map[string]string{
+			ReminderVariantWeekly:   "Weekly reminder: send kudos to your colleagues!",
+			ReminderVariantMonthEnd: "The month is almost over: send kudos to your colleagues!",
+		},
+		SendNow:          "📝 Send kudos now!",
+		SnoozeWeek:       "Remind me next week",
+		SnoozeMonth:      "Pause for 1 month",
+		OptOut:           "No more reminders",
+		OptOutTitle:      "Stop reminders?",
+		OptOutText:       "You won't receive the weekly reminder anymore. You can undo it from the message itself.",
+		OptOutConfirm:    "Stop reminders",
+		Cancel:           "Cancel",
+		Tip:              "💡 *Tip:* Specific and detailed kudos have more impact!",
+		Stats:            "📊 This month you sent *%d kudo(s)* and received *%d*.",
+		SuggestionsTitle: "*How about recognizing one of these people?*",
+		Suggest:          "📝 Send kudos",
+		Redirected:       "🧪 *Redirected:* this reminder would be sent to <@%s>",
+		RedirectedText:   "Weekly reminder for <@%s> (redirected)",
+	},
+}
+
+// wording returns the texts of the style, defaulting to the weekly reminder in Portuguese
+func (s ReminderStyle) wording() (reminderWording, string) {
+	wording, ok := reminderTexts[s.Locale]
+	if !ok {
+		wording = reminderTexts[LocalePortuguese]
+	}
+	variant := s.Variant
+	if _, ok := wording.Headers[variant]; !ok {
+		variant = ReminderVariantWeekly
+	}
+	return wording, variant
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestReminderStyle_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   ReminderStyle
+		wantErr bool
+	}{
+		{name: "defaults", style: ReminderStyle{}},
+		{name: "month end in English", style: ReminderStyle{Variant: ReminderVariantMonthEnd, Locale: LocaleEnglish}},
+		{name: "weekly in Portuguese", style: ReminderStyle{Variant: ReminderVariantWeekly, Locale: LocalePortuguese}},
+		{name: "unknown variant", style: ReminderStyle{Variant: "daily"}, wantErr: true},
+		{name: "unsupported locale", style: ReminderStyle{Locale: "es"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.style.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatReminderBlocks_Styles(t *testing.T) {
+	tests := []struct {
+		name       string
+		style      ReminderStyle
+		wantHeader string
+		wantButton string
+	}{
+		{name: "default", style: ReminderStyle{}, wantHeader: "👋 Lembrete Semanal", wantButton: "📝 Elogiar agora!"},
+		{name: "month end", style: ReminderStyle{Variant: ReminderVariantMonthEnd}, wantHeader: "📅 O mês está acabando", wantButton: "📝 Elogiar agora!"},
+		{name: "English", style: ReminderStyle{Locale: LocaleEnglish}, wantHeader: "👋 Weekly Reminder", wantButton: "📝 Send kudos now!"},
+		{name: "unknown values fall back", style: ReminderStyle{Variant: "daily", Locale: "es"}, wantHeader: "👋 Lembrete Semanal", wantButton: "📝 Elogiar agora!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := FormatReminderBlocks(tt.style)
+
+			if header := blocks[0].(*slack.HeaderBlock).Text.Text; header != tt.wantHeader {
+				t.Errorf("header = %q, want %q", header, tt.wantHeader)
+			}
+			button := blocks[2].(*slack.ActionBlock).Elements.ElementSet[0].(*slack.ButtonBlockElement)
+			if button.Text.Text != tt.wantButton {
+				t.Errorf("button = %q, want %q", button.Text.Text, tt.wantButton)
+			}
+		})
+	}
+}
+
+func TestReminderTexts_Complete(t *testing.T) {
+	for locale, wording := range reminderTexts {
+		for _, variant := range []string{ReminderVariantWeekly, ReminderVariantMonthEnd} {
+			if wording.Headers[variant] == "" || wording.Intros[variant] == "" || wording.Fallbacks[variant] == "" {
+				t.Errorf("%s is missing texts for the %s variant", locale, variant)
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// PlainRichText wraps plain text in a rich_text block, e.g. to prefill a rich_text_input
+func PlainRichText(text string) *slack.RichTextBlock {
+	return slack.NewRichTextBlock("",
+		slack.NewRichTextSection(slack.NewRichTextSectionTextElement(text, nil)),
+	)
+}
+
+// MessageInputRichText returns the content of the modal's message field as rich text
+// Modals opened before the field became a rich_text_input send plain text, which is wrapped
+// Returns nil when the field is empty
+func MessageInputRichText(input slack.BlockAction) *slack.RichTextBlock {
+	if len(input.RichTextValue.Elements) > 0 {
+		if strings.TrimSpace(RichTextToMrkdwn(&input.RichTextValue)) == "" {
+			return nil
+		}
+		return &input.RichTextValue
+	}
+	if input.Value != "" {
+		return PlainRichText(input.Value)
+	}
+	return nil
+}
+
+// ParseMessageInput returns the modal's message as mrkdwn and, when it was typed as rich text,
+// the rich_text block JSON posted with the kudo
+func ParseMessageInput(input slack.BlockAction) (message, richText string) {
+	if len(input.RichTextValue.Elements) == 0 {
+		return input.Value, ""
+	}
+
+	message = strings.TrimSpace(RichTextToMrkdwn(&input.RichTextValue))
+	if message == "" || !richTextSupported(input.RichTextValue.Elements) {
+		return message, ""
+	}
+	data, err := json.Marshal(input.RichTextValue)
+	if err != nil {
+		return message, ""
+	}
+	return message, string(data)
+}
+
+// QuotedRichText prepares a rich_text block typed in the modal to be posted in the kudos message
+// Paragraphs are shown as quotes like FormatAsSlackQuote, lists and code blocks are kept as typed
+func QuotedRichText(data string) (*slack.RichTextBlock, error) {
+	var block slack.RichTextBlock
+	if err := json.Unmarshal([]byte(data), &block); err != nil {
+		return nil, fmt.Errorf("error parsing rich text message: %w", err)
+	}
+	if !richTextSupported(block.Elements) {
+		return nil, fmt.Errorf("rich text message has unsupported elements")
+	}
+
+	elements := make([]slack.RichTextElement, 0, len(block.Elements))
+	for _, element := range block.Elements {
+		if section, ok := element.(*slack.RichTextSection); ok {
+			quote := slack.RichTextQuote(*section)
+			quote.Type = slack.RTEQuote
+			element = &quote
+		}
+		elements = append(elements, element)
+	}
+	return slack.NewRichTextBlock("kudo_message", elements...), nil
+}
+
+// richTextSupported reports whether every element is known to slack-go, so the block can be posted again
+// Unknown elements don't survive a JSON round trip
+func richTextSupported(elements []slack.RichTextElement) bool {
+	for _, element := range elements {
+		switch e := element.(type) {
+		case *slack.RichTextSection:
+			if !sectionSupported(e.Elements) {
+				return false
+			}
+		case *slack.RichTextQuote:
+			if !sectionSupported(e.Elements) {
+				return false
+			}
+		case *slack.RichTextPreformatted:
+			if !sectionSupported(e.Elements) {
+				return false
+			}
+		case *slack.RichTextList:
+			if !richTextSupported(e.Elements) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func sectionSupported(elements []slack.RichTextSectionElement) bool {
+	for _, element := range elements {
+		if _, ok := element.(*slack.RichTextSectionUnknownElement); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RichTextToMrkdwn converts a rich_text block into Slack mrkdwn
+// Bold, italic, strikethrough, inline code, links, mentions, emoji, lists, quotes and code blocks are kept
+func RichTextToMrkdwn(block *slack.RichTextBlock) string {
+	var parts []string
+	for _, element := range block.Elements {
+		if text := richTextElementToMrkdwn(element); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func richTextElementToMrkdwn(element slack.RichTextElement) string {
+	switch e := element.(type) {
+	case *slack.RichTextSection:
+		return strings.TrimSuffix(sectionToMrkdwn(e.Elements), "\n")
+	case *slack.RichTextQuote:
+		text := strings.TrimSuffix(sectionToMrkdwn(e.Elements), "\n")
+		return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+	case *slack.RichTextPreformatted:
+		return "```\n" + strings.TrimSuffix(preformattedText(e.Elements), "\n") + "\n```"
+	case *slack.RichTextList:
+		return listToMrkdwn(e)
+	default:
+		return ""
+	}
+}
+
+// listToMrkdwn writes one line per item, indented by the list's nesting level
+func listToMrkdwn(list *slack.RichTextList) string {
+	indent := strings.Repeat("    ", list.Indent)
+	var lines []string
+	for i, item := range list.Elements {
+		marker := "•"
+		if list.Style == slack.RTEListOrdered {
+			marker = fmt.Sprintf("%d.", list.Offset+i+1)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", indent, marker, richTextElementToMrkdwn(item)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// preformattedText returns the raw text of a code block, where formatting doesn't apply
+func preformattedText(elements []slack.RichTextSectionElement) string {
+	var b strings.Builder
+	for _, element := range elements {
+		switch e := element.(type) {
+		case *slack.RichTextSectionTextElement:
+			b.WriteString(e.Text)
+		case *slack.RichTextSectionLinkElement:
+			b.WriteString(e.URL)
+		default:
+			b.WriteString(inlineToMrkdwn(element))
+		}
+	}
+	return b.String()
+}
+
+func sectionToMrkdwn(elements []slack.RichTextSectionElement) string {
+	var b strings.Builder
+	for _, element := range elements {
+		b.WriteString(inlineToMrkdwn(element))
+	}
+	return b.String()
+}
+
+func inlineToMrkdwn(element slack.RichTextSectionElement) string {
+	switch e := element.(type) {
+	case *slack.RichTextSectionTextElement:
+		return styleMrkdwn(EscapeMrkdwn(e.Text), e.Style)
+	case *slack.RichTextSectionLinkElement:
+		if e.Text == "" || e.Text == e.URL {
+			return styleMrkdwn(fmt.Sprintf("<%s>", e.URL), e.Style)
+		}
+		return styleMrkdwn(fmt.Sprintf("<%s|%s>", e.URL, EscapeMrkdwn(e.Text)), e.Style)
+	case *slack.RichTextSectionUserElement:
+		return fmt.Sprintf("<@%s>", e.UserID)
+	case *slack.RichTextSectionChannelElement:
+		return fmt.Sprintf("<#%s>", e.ChannelID)
+	case *slack.RichTextSectionUserGroupElement:
+		return fmt.Sprintf("<!subteam^%s>", e.UsergroupID)
+	case *slack.RichTextSectionBroadcastElement:
+		return fmt.Sprintf("<!%s>", e.Range)
+	case *slack.RichTextSectionEmojiElement:
+		return fmt.Sprintf(":%s:", e.Name)
+	case *slack.RichTextSectionDateElement:
+		if e.Fallback != nil {
+			return fmt.Sprintf("<!date^%d^%s|%s>", e.Timestamp.Time().Unix(), e.Format, mrkdwnEscaper.Replace(*e.Fallback))
+		}
+		return fmt.Sprintf("<!date^%d^%s|%s>", e.Timestamp.Time().Unix(), e.Format, e.Timestamp.Time().UTC().Format("2006-01-02"))
+	case *slack.RichTextSectionColorElement:
+		return e.Value
+	default:
+		return ""
+	}
+}
+
+// styleMrkdwn wraps text in the mrkdwn markers of its style
+// Slack ignores markers next to spaces, so leading and trailing spaces stay outside them
+func styleMrkdwn(text string, style *slack.RichTextSectionTextStyle) string {
+	if style == nil {
+		return text
+	}
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	start := strings.Index(text, core)
+	prefix, suffix := text[:start], text[start+len(core):]
+
+	if style.Code {
+		core = "`" + core + "`"
+	}
+	if style.Strike {
+		core = "~" + core + "~"
+	}
+	if style.Italic {
+		core = "_" + core + "_"
+	}
+	if style.Bold {
+		core = "*" + core + "*"
+	}
+	return prefix + core + suffix
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+// richText decodes a rich_text block as sent by a rich_text_input
+func richText(t *testing.T, elements string) slack.RichTextBlock {
+	t.Helper()
+
+	var block slack.RichTextBlock
+	if err := json.Unmarshal([]byte(`{"type": "rich_text", "elements": [`+elements+`]}`), &block); err != nil {
+		t.Fatalf("error decoding rich text: %v", err)
+	}
+	return block
+}
+
+func TestRichTextToMrkdwn(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements string
+		expected string
+	}{
+		{
+			name:     "plain text",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "text", "text": "Valeu pela ajuda!\n"}]}`,
+			expected: "Valeu pela ajuda!",
+		},
+		{
+			name: "styles",
+			elements: `{"type": "rich_text_section", "elements": [
+				{"type": "text", "text": "Foi "},
+				{"type": "text", "text": "incrível ", "style": {"bold": true}},
+				{"type": "text", "text": "mesmo", "style": {"italic": true, "strike": true}},
+				{"type": "text", "text": " com ", "style": {"code": true}},
+				{"type": "text", "text": "go test", "style": {"code": true}}
+			]}`,
+			expected: "Foi *incrível* _~mesmo~_ `com` `go test`",
+		},
+		{
+			name: "links and mentions",
+			elements: `{"type": "rich_text_section", "elements": [
+				{"type": "user", "user_id": "U222222"},
+				{"type": "text", "text": " e "},
+				{"type": "usergroup", "usergroup_id": "S123"},
+				{"type": "text", "text": " em "},
+				{"type": "channel", "channel_id": "C123"},
+				{"type": "text", "text": ": "},
+				{"type": "link", "url": "https://example.com/pr/1", "text": "o PR"},
+				{"type": "text", "text": " "},
+				{"type": "link", "url": "https://example.com"},
+				{"type": "text", "text": " "},
+				{"type": "emoji", "name": "tada"}
+			]}`,
+			expected: "<@U222222> e <!subteam^S123> em <#C123>: <https://example.com/pr/1|o PR> <https://example.com> :tada:",
+		},
+		{
+			name:     "escapes reserved characters",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "text", "text": "a < b & c > d"}]}`,
+			expected: "a &lt; b &amp; c &gt; d",
+		},
+		{
+			name: "lists",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "text", "text": "Entregas:\n"}]},
+				{"type": "rich_text_list", "style": "bullet", "indent": 0, "elements": [
+					{"type": "rich_text_section", "elements": [{"type": "text", "text": "API"}]},
+					{"type": "rich_text_section", "elements": [{"type": "text", "text": "Docs", "style": {"bold": true}}]}
+				]},
+				{"type": "rich_text_list", "style": "ordered", "indent": 1, "offset": 2, "elements": [
+					{"type": "rich_text_section", "elements": [{"type": "text", "text": "Guia"}]}
+				]}`,
+			expected: "Entregas:\n• API\n• *Docs*\n    3. Guia",
+		},
+		{
+			name: "code block keeps the text as typed",
+			elements: `{"type": "rich_text_preformatted", "elements": [
+				{"type": "text", "text": "if a < b {\n\treturn *p\n}"}
+			]}`,
+			expected: "```\nif a < b {\n\treturn *p\n}\n```",
+		},
+		{
+			name:     "quote",
+			elements: `{"type": "rich_text_quote", "elements": [{"type": "text", "text": "Primeira\nSegunda"}]}`,
+			expected: "> Primeira\n> Segunda",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := richText(t, tt.elements)
+			if result := RichTextToMrkdwn(&block); result != tt.expected {
+				t.Errorf("RichTextToMrkdwn() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMessageInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        func(t *testing.T) slack.BlockAction
+		wantMessage  string
+		wantRichText bool
+	}{
+		{
+			name:        "plain text from an older modal",
+			input:       func(t *testing.T) slack.BlockAction { return slack.BlockAction{Value: "Valeu!"} },
+			wantMessage: "Valeu!",
+		},
+		{
+			name: "rich text",
+			input: func(t *testing.T) slack.BlockAction {
+				return slack.BlockAction{RichTextValue: richText(t, `{"type": "rich_text_section", "elements": [{"type": "text", "text": "Valeu!", "style": {"bold": true}}]}`)}
+			},
+			wantMessage:  "*Valeu!*",
+			wantRichText: true,
+		},
+		{
+			name: "empty rich text",
+			input: func(t *testing.T) slack.BlockAction {
+				return slack.BlockAction{RichTextValue: richText(t, `{"type": "rich_text_section", "elements": [{"type": "text", "text": "\n"}]}`)}
+			},
+		},
+		{
+			name: "unsupported element is only kept as mrkdwn",
+			input: func(t *testing.T) slack.BlockAction {
+				return slack.BlockAction{RichTextValue: richText(t, `{"type": "rich_text_section", "elements": [{"type": "text", "text": "Valeu "}, {"type": "sparkle"}]}`)}
+			},
+			wantMessage: "Valeu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, rich := ParseMessageInput(tt.input(t))
+			if message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", message, tt.wantMessage)
+			}
+			if (rich != "") != tt.wantRichText {
+				t.Errorf("rich text = %q, want present = %v", rich, tt.wantRichText)
+			}
+		})
+	}
+}
+
+func TestMessageInputRichText(t *testing.T) {
+	if block := MessageInputRichText(slack.BlockAction{}); block != nil {
+		t.Errorf("MessageInputRichText() = %+v, want nil for an empty field", block)
+	}
+
+	block := MessageInputRichText(slack.BlockAction{Value: "Valeu!"})
+	if block == nil || RichTextToMrkdwn(block) != "Valeu!" {
+		t.Errorf("MessageInputRichText() = %+v, want the plain text wrapped", block)
+	}
+}
+
+func TestQuotedRichText(t *testing.T) {
+	_, rich := ParseMessageInput(slack.BlockAction{RichTextValue: richText(t, `
+		{"type": "rich_text_section", "elements": [{"type": "text", "text": "Entregas:\n"}]},
+		{"type": "rich_text_list", "style": "bullet", "elements": [{"type": "rich_text_section", "elements": [{"type": "text", "text": "API"}]}]},
+		{"type": "rich_text_preformatted", "elements": [{"type": "text", "text": "make deploy"}]}`)})
+
+	block, err := QuotedRichText(rich)
+	if err != nil {
+		t.Fatalf("QuotedRichText() unexpected error = %v", err)
+	}
+
+	var types []string
+	for _, element := range block.Elements {
+		types = append(types, string(element.RichTextElementType()))
+	}
+	if got := strings.Join(types, " "); got != "rich_text_quote rich_text_list rich_text_preformatted" {
+		t.Errorf("element types = %s, want the paragraph quoted and the list and code kept", got)
+	}
+
+	if _, err := QuotedRichText("{"); err == nil {
+		t.Error("QuotedRichText() expected error for invalid JSON")
+	}
+}
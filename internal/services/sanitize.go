@@ -0,0 +1,180 @@
+package services
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// mrkdwnTokenPattern matches the <...> tokens Slack parses in mrkdwn: mentions, links and special commands
+var mrkdwnTokenPattern = regexp.MustCompile(`<([^<>]*)>`)
+
+// urlLikePattern matches labels that read as an address, e.g. https://intranet, www.example.com or rh@example.com
+var urlLikePattern = regexp.MustCompile(`(?i)^(?:[a-z][a-z0-9+.-]*://|www\.)\S+$|^(?:[^\s@]+@)?[a-z0-9-]+(?:\.[a-z0-9-]+)+(?:[/:?#]\S*)?$`)
+
+// userMentionPattern and channelMentionPattern match the mentions kept in user-supplied mrkdwn
+var (
+	userMentionPattern    = regexp.MustCompile(`^@[UW][A-Z0-9]+(?:\|[^|]*)?$`)
+	channelMentionPattern = regexp.MustCompile(`^#C[A-Z0-9]+(?:\|[^|]*)?$`)
+	datePattern           = regexp.MustCompile(`^!date\^\d+\^[^|]*\|[^|]*$`)
+)
+
+// broadcastRanges are the special mentions that notify a whole channel or workspace
+var broadcastRanges = map[string]bool{"here": true, "channel": true, "everyone": true}
+
+// defaultGroupLabel replaces user group mentions that carry no name
+const defaultGroupLabel = "@grupo"
+
+// mrkdwnEscaper escapes the characters Slack reserves in mrkdwn, & for entities and < and > for mentions and links
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// mrkdwnUnescaper reverts mrkdwnEscaper
+var mrkdwnUnescaper = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">")
+
+// EscapeMrkdwn escapes plain text, e.g. a custom kudo type name, so Slack can't parse mentions, links or entities in it
+func EscapeMrkdwn(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
+
+// escapeMrkdwnText escapes the text between the tokens of user-supplied mrkdwn, keeping the entities it already has
+func escapeMrkdwnText(text string) string {
+	return EscapeMrkdwn(mrkdwnUnescaper.Replace(text))
+}
+
+// SanitizeMrkdwn neutralizes the parts of user-supplied mrkdwn that shouldn't be rendered as typed
+// Broadcast and user group mentions become plain text, links whose label shows another address show
+// their real address, and unknown <...> tokens are escaped. User and channel mentions, dates and
+// formatting are kept
+func SanitizeMrkdwn(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range mrkdwnTokenPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(escapeMrkdwnText(text[last:match[0]]))
+		b.WriteString(sanitizeToken(text[match[2]:match[3]]))
+		last = match[1]
+	}
+	b.WriteString(escapeMrkdwnText(text[last:]))
+	return b.String()
+}
+
+// sanitizeToken returns the mrkdwn posted in place of the <token>
+func sanitizeToken(token string) string {
+	switch {
+	case userMentionPattern.MatchString(token), channelMentionPattern.MatchString(token), datePattern.MatchString(token):
+		return "<" + token + ">"
+	case strings.HasPrefix(token, "!"):
+		command, label, _ := strings.Cut(token[1:], "|")
+		if broadcastRanges[command] {
+			return "@" + command
+		}
+		if strings.HasPrefix(command, "subteam^") {
+			return groupLabel(label)
+		}
+	default:
+		target, label, _ := strings.Cut(token, "|")
+		if safeLinkTarget(target) {
+			if label == "" || spoofedLabel(target, label) {
+				return "<" + target + ">"
+			}
+			return "<" + target + "|" + label + ">"
+		}
+	}
+	return "&lt;" + escapeMrkdwnText(token) + "&gt;"
+}
+
+// groupLabel shows a user group mention as its name without notifying the group
+func groupLabel(label string) string {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return defaultGroupLabel
+	}
+	return "@" + strings.TrimPrefix(label, "@")
+}
+
+// safeLinkTarget reports whether a link points to a web page or an email address
+func safeLinkTarget(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return parsed.Opaque != ""
+	default:
+		return false
+	}
+}
+
+// spoofedLabel reports whether a link label reads as an address on another host than the link's
+func spoofedLabel(target, label string) bool {
+	label = strings.TrimSpace(label)
+	if !urlLikePattern.MatchString(label) {
+		return false
+	}
+	return linkHost(label) != linkHost(target)
+}
+
+// linkHost returns the lowercased host of an address, adding a scheme to bare ones like www.example.com
+func linkHost(address string) string {
+	if !strings.Contains(address, "://") && !strings.HasPrefix(strings.ToLower(address), "mailto:") {
+		address = "https://" + address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return address
+	}
+	if parsed.Scheme == "mailto" {
+		_, host, _ := strings.Cut(parsed.Opaque, "@")
+		return strings.ToLower(host)
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// SanitizeRichText applies SanitizeMrkdwn's rules to a rich_text block, in place
+// Broadcast and user group mentions become text and spoofed link labels are dropped
+func SanitizeRichText(block *slack.RichTextBlock) {
+	for _, element := range block.Elements {
+		sanitizeRichTextElement(element)
+	}
+}
+
+func sanitizeRichTextElement(element slack.RichTextElement) {
+	switch e := element.(type) {
+	case *slack.RichTextSection:
+		e.Elements = sanitizeSectionElements(e.Elements)
+	case *slack.RichTextQuote:
+		e.Elements = sanitizeSectionElements(e.Elements)
+	case *slack.RichTextPreformatted:
+		e.Elements = sanitizeSectionElements(e.Elements)
+	case *slack.RichTextList:
+		for _, item := range e.Elements {
+			sanitizeRichTextElement(item)
+		}
+	}
+}
+
+func sanitizeSectionElements(elements []slack.RichTextSectionElement) []slack.RichTextSectionElement {
+	sanitized := make([]slack.RichTextSectionElement, 0, len(elements))
+	for _, element := range elements {
+		switch e := element.(type) {
+		case *slack.RichTextSectionBroadcastElement:
+			element = slack.NewRichTextSectionTextElement("@"+e.Range, nil)
+		case *slack.RichTextSectionUserGroupElement:
+			element = slack.NewRichTextSectionTextElement(defaultGroupLabel, nil)
+		case *slack.RichTextSectionLinkElement:
+			if !safeLinkTarget(e.URL) {
+				element = slack.NewRichTextSectionTextElement(e.URL, e.Style)
+			} else if e.Text != "" && spoofedLabel(e.URL, e.Text) {
+				link := *e
+				link.Text = ""
+				element = &link
+			}
+		}
+		sanitized = append(sanitized, element)
+	}
+	return sanitized
+}
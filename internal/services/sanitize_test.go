@@ -0,0 +1,213 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+func TestSanitizeMrkdwn(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain text", input: "Valeu pela ajuda!", expected: "Valeu pela ajuda!"},
+		{name: "channel broadcast", input: "Atenção <!channel>", expected: "Atenção @channel"},
+		{name: "here broadcast", input: "<!here> olhem isso", expected: "@here olhem isso"},
+		{name: "everyone broadcast", input: "Oi <!everyone>", expected: "Oi @everyone"},
+		{name: "labeled broadcast", input: "<!here|here> agora", expected: "@here agora"},
+		{name: "user group", input: "Valeu <!subteam^S123>", expected: "Valeu @grupo"},
+		{name: "labeled user group", input: "Valeu <!subteam^S123|@plataforma>", expected: "Valeu @plataforma"},
+		{name: "unknown special command", input: "<!foo^bar>", expected: "&lt;!foo^bar&gt;"},
+		{
+			name:     "link disguised as another address",
+			input:    "Veja <https://evil.example.com|https://intranet.example.com>",
+			expected: "Veja <https://evil.example.com>",
+		},
+		{
+			name:     "link disguised as a bare domain",
+			input:    "<https://evil.example.com/login|intranet.example.com>",
+			expected: "<https://evil.example.com/login>",
+		},
+		{
+			name:     "mailto disguised as another address",
+			input:    "<mailto:evil@evil.example.com|rh@example.com>",
+			expected: "<mailto:evil@evil.example.com>",
+		},
+		{
+			name:     "link labeled with its own host",
+			input:    "<https://github.com/org/repo/pull/1|github.com/org/repo>",
+			expected: "<https://github.com/org/repo/pull/1|github.com/org/repo>",
+		},
+		{
+			name:     "link with a text label",
+			input:    "<https://example.com/pr/1|o PR>",
+			expected: "<https://example.com/pr/1|o PR>",
+		},
+		{
+			name:     "javascript link",
+			input:    "<javascript:alert(1)|clique aqui>",
+			expected: "&lt;javascript:alert(1)|clique aqui&gt;",
+		},
+		{
+			name:     "user and channel mentions",
+			input:    "<@U123> e <@W456|ana> em <#C789|geral>",
+			expected: "<@U123> e <@W456|ana> em <#C789|geral>",
+		},
+		{
+			name:     "date",
+			input:    "Em <!date^1700000000^{date_short}|14/11/2023>",
+			expected: "Em <!date^1700000000^{date_short}|14/11/2023>",
+		},
+		{name: "stray brackets", input: "a < b e c > d", expected: "a &lt; b e c &gt; d"},
+		{name: "escaped text", input: "1 &lt; 2 &amp; 3", expected: "1 &lt; 2 &amp; 3"},
+		{name: "raw ampersand", input: "Time de P&D", expected: "Time de P&amp;D"},
+		{name: "unknown token with ampersand", input: "<foo&bar>", expected: "&lt;foo&amp;bar&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeMrkdwn(tt.input); got != tt.expected {
+				t.Errorf("SanitizeMrkdwn(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeMrkdwn(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain name", input: "Ideia Brilhante", expected: "Ideia Brilhante"},
+		{name: "broadcast", input: "<!channel> Herói", expected: "&lt;!channel&gt; Herói"},
+		{name: "user group", input: "<!subteam^S123>", expected: "&lt;!subteam^S123&gt;"},
+		{name: "link", input: "<https://evil.example.com|Clique>", expected: "&lt;https://evil.example.com|Clique&gt;"},
+		{name: "ampersand", input: "Acima & Além", expected: "Acima &amp; Além"},
+		{name: "typed entity", input: "&lt;Ana&gt;", expected: "&amp;lt;Ana&amp;gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeMrkdwn(tt.input); got != tt.expected {
+				t.Errorf("EscapeMrkdwn(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeRichText(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements string
+		expected string
+	}{
+		{
+			name:     "broadcast",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "broadcast", "range": "channel"}, {"type": "text", "text": " valeu"}]}`,
+			expected: "@channel valeu",
+		},
+		{
+			name:     "user group",
+			elements: `{"type": "rich_text_quote", "elements": [{"type": "usergroup", "usergroup_id": "S123"}]}`,
+			expected: "> @grupo",
+		},
+		{
+			name: "broadcast in a list",
+			elements: `{"type": "rich_text_list", "style": "bullet", "elements": [
+				{"type": "rich_text_section", "elements": [{"type": "broadcast", "range": "here"}]}
+			]}`,
+			expected: "• @here",
+		},
+		{
+			name:     "disguised link",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "link", "url": "https://evil.example.com", "text": "https://intranet.example.com"}]}`,
+			expected: "<https://evil.example.com>",
+		},
+		{
+			name:     "javascript link",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "link", "url": "javascript:alert(1)", "text": "clique"}]}`,
+			expected: "javascript:alert(1)",
+		},
+		{
+			name:     "link with a text label",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "link", "url": "https://example.com/pr/1", "text": "o PR"}]}`,
+			expected: "<https://example.com/pr/1|o PR>",
+		},
+		{
+			name:     "user mention",
+			elements: `{"type": "rich_text_section", "elements": [{"type": "user", "user_id": "U222222"}]}`,
+			expected: "<@U222222>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := richText(t, tt.elements)
+			SanitizeRichText(&block)
+			if got := RichTextToMrkdwn(&block); got != tt.expected {
+				t.Errorf("RichTextToMrkdwn() after SanitizeRichText = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatKudosAsBlocks_Sanitized(t *testing.T) {
+	_, rich := ParseMessageInput(slack.BlockAction{RichTextValue: richText(t, `
+		{"type": "rich_text_section", "elements": [
+			{"type": "broadcast", "range": "everyone"},
+			{"type": "text", "text": " "},
+			{"type": "link", "url": "https://evil.example.com", "text": "https://intranet.example.com"}
+		]}`)})
+
+	tests := []struct {
+		name string
+		kudo *models.Kudo
+	}{
+		{
+			name: "plain message and custom type",
+			kudo: &models.Kudo{
+				SenderID:     "U111111",
+				RecipientIDs: []string{"U222222"},
+				TypeEmoji:    ":star:",
+				TypeText:     "<!channel> Herói",
+				Message:      "<!here> <!subteam^S123> <https://evil.example.com|https://intranet.example.com>",
+			},
+		},
+		{
+			name: "rich text message",
+			kudo: &models.Kudo{
+				SenderID:        "U111111",
+				RecipientIDs:    []string{"U222222"},
+				TypeEmoji:       ":star:",
+				TypeText:        "Herói",
+				Message:         "<!everyone> <https://evil.example.com|https://intranet.example.com>",
+				MessageRichText: rich,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Keep < and > readable, json.Marshal would escape them
+			var buf strings.Builder
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(FormatKudosAsBlocks(tt.kudo)); err != nil {
+				t.Fatalf("error encoding blocks: %v", err)
+			}
+			posted := buf.String()
+
+			for _, injected := range []string{"<!channel>", "<!here>", "<!everyone>", "<!subteam^", `"type":"broadcast"`, "|https://intranet"} {
+				if strings.Contains(posted, injected) {
+					t.Errorf("blocks contain %q: %s", injected, posted)
+				}
+			}
+		})
+	}
+}
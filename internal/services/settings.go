@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// IsAdmin reports whether the user is listed in KUDOS_ADMINS or belongs to KUDOS_ADMIN_GROUP
+// Failures to load the group deny access
+func IsAdmin(ctx context.Context, userID string, cfg *config.Config) bool {
+	if cfg.IsAdmin(userID) {
+		return true
+	}
+	if cfg.AdminGroupID == "" || userID == "" {
+		return false
+	}
+
+	members, err := cfg.SlackAPI.GetUserGroupMembersContext(ctx, cfg.AdminGroupID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load admin group members", "usergroup_id", cfg.AdminGroupID, "error", err)
+		return false
+	}
+	return slices.Contains(members, userID)
+}
+
+// AdminUserIDs returns the users of KUDOS_ADMINS and the members of KUDOS_ADMIN_GROUP, without repetitions
+// Failures to load the group are logged and leave out its members
+func AdminUserIDs(ctx context.Context, cfg *config.Config) []string {
+	admins := slices.Clone(cfg.AdminUserIDs)
+	if cfg.AdminGroupID == "" {
+		return admins
+	}
+
+	members, err := cfg.SlackAPI.GetUserGroupMembersContext(ctx, cfg.AdminGroupID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load admin group members", "usergroup_id", cfg.AdminGroupID, "error", err)
+		return admins
+	}
+	for _, userID := range members {
+		if !slices.Contains(admins, userID) {
+			admins = append(admins, userID)
+		}
+	}
+	return admins
+}
+
+// LoadSettings returns the settings saved with /elogie admin, empty until admins save them
+// Store failures fall back to the function configuration
+func LoadSettings(ctx context.Context, cfg *config.Config) models.Settings {
+	if cfg.Store == nil {
+		return models.Settings{}
+	}
+
+	settings, err := cfg.Store.GetSettings(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not load settings, using the configuration", "error", err)
+		return models.Settings{}
+	}
+	if settings == nil {
+		return models.Settings{}
+	}
+	return *settings
+}
+
+// ApplySettings returns a copy of cfg posting to the kudos channel saved by admins
+// cfg is left untouched since it's shared by the requests of the function
+func ApplySettings(ctx context.Context, cfg *config.Config) (*config.Config, models.Settings) {
+	settings := LoadSettings(ctx, cfg)
+	applied := *cfg
+	if settings.KudosChannelID != "" {
+		applied.SlackChannelID = settings.KudosChannelID
+	}
+	return &applied, settings
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/vyper/my-matter/internal/config"
+	"github.com/vyper/my-matter/internal/models"
+	"github.com/vyper/my-matter/internal/storage"
+)
+
+// failingSettingsStore fails to load the settings
+type failingSettingsStore struct {
+	storage.Store
+}
+
+func (s failingSettingsStore) GetSettings(ctx context.Context) (*models.Settings, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		groupID  string
+		groupErr error
+		expected bool
+	}{
+		{name: "listed admin", userID: "UADMIN1", expected: true},
+		{name: "group member", userID: "UGROUP1", groupID: "S0ADMINS", expected: true},
+		{name: "not in the group", userID: "U111111", groupID: "S0ADMINS"},
+		{name: "no group", userID: "UGROUP1"},
+		{name: "group failure", userID: "UGROUP1", groupID: "S0ADMINS", groupErr: errors.New("no_such_subteam")},
+		{name: "empty user", groupID: "S0ADMINS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				SlackAPI: &MockSlackClient{
+					GetUserGroupMembersFunc: func(userGroup string) ([]string, error) {
+						if userGroup != "S0ADMINS" {
+							t.Errorf("user group = %s, want S0ADMINS", userGroup)
+						}
+						return []string{"UGROUP1", "UADMIN1"}, tt.groupErr
+					},
+				},
+				AdminUserIDs: []string{"UADMIN1"},
+				AdminGroupID: tt.groupID,
+			}
+
+			if got := IsAdmin(context.Background(), tt.userID, cfg); got != tt.expected {
+				t.Errorf("IsAdmin(%q) = %v, want %v", tt.userID, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAdminUserIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		groupID  string
+		groupErr error
+		expected []string
+	}{
+		{name: "listed admins only", expected: []string{"UADMIN1"}},
+		{name: "group members added once", groupID: "S0ADMINS", expected: []string{"UADMIN1", "UGROUP1"}},
+		{name: "group failure", groupID: "S0ADMINS", groupErr: errors.New("no_such_subteam"), expected: []string{"UADMIN1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				SlackAPI: &MockSlackClient{
+					GetUserGroupMembersFunc: func(userGroup string) ([]string, error) {
+						return []string{"UGROUP1", "UADMIN1"}, tt.groupErr
+					},
+				},
+				AdminUserIDs: []string{"UADMIN1"},
+				AdminGroupID: tt.groupID,
+			}
+
+			if got := AdminUserIDs(context.Background(), cfg); !slices.Equal(got, tt.expected) {
+				t.Errorf("AdminUserIDs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	ctx := context.Background()
+	saved := storage.NewMemoryStore()
+	saved.SaveSettings(ctx, &models.Settings{KudosChannelID: "C0KUDOS", DigestChannelID: "C0DIGEST"})
+
+	tests := []struct {
+		name         string
+		store        storage.Store
+		wantChannel  string
+		wantDigestTo string
+	}{
+		{name: "saved settings", store: saved, wantChannel: "C0KUDOS", wantDigestTo: "C0DIGEST"},
+		{name: "no settings", store: storage.NewMemoryStore(), wantChannel: "C123456"},
+		{name: "store failure", store: failingSettingsStore{saved}, wantChannel: "C123456"},
+		{name: "no store", wantChannel: "C123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{SlackChannelID: "C123456", Store: tt.store}
+
+			applied, settings := ApplySettings(ctx, cfg)
+			if applied.SlackChannelID != tt.wantChannel {
+				t.Errorf("SlackChannelID = %s, want %s", applied.SlackChannelID, tt.wantChannel)
+			}
+			if settings.DigestChannelID != tt.wantDigestTo {
+				t.Errorf("DigestChannelID = %s, want %s", settings.DigestChannelID, tt.wantDigestTo)
+			}
+			if cfg.SlackChannelID != "C123456" {
+				t.Errorf("cfg.SlackChannelID = %s, want the shared config untouched", cfg.SlackChannelID)
+			}
+		})
+	}
+}
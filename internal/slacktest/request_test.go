@@ -0,0 +1,41 @@
+package slacktest
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestNewSignedRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{name: "same secret", secret: "signing-secret"},
+		{name: "other secret", secret: "other-secret", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewSignedRequest("signing-secret", url.Values{"command": {"/elogie"}})
+
+			verifier, err := slack.NewSecretsVerifier(req.Header, tt.secret)
+			if err != nil {
+				t.Fatalf("NewSecretsVerifier() unexpected error = %v", err)
+			}
+			body, _ := io.ReadAll(req.Body)
+			verifier.Write(body)
+
+			if err := verifier.Ensure(); (err != nil) != tt.wantErr {
+				t.Errorf("Ensure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if req.Method != http.MethodPost {
+				t.Errorf("Method = %s, want POST", req.Method)
+			}
+		})
+	}
+}
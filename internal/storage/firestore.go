@@ -0,0 +1,559 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/vyper/my-matter/internal/models"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	kudosCollection               = "kudos"
+	reminderPreferencesCollection = "reminder_preferences"
+	reminderDeliveriesCollection  = "reminder_deliveries"
+	idempotencyKeysCollection     = "idempotency_keys"
+	settingsCollection            = "settings"
+	customKudoTypesCollection     = "custom_kudo_types"
+	kudoTypesCollection           = "kudo_types"
+	auditLogCollection            = "audit_log"
+)
+
+// moderationDoc is the settings document holding the moderation rules
+const moderationDoc = "moderation"
+
+// adminSettingsDoc is the settings document holding the settings changed with /elogie admin
+const adminSettingsDoc = "admin"
+
+// idempotencyKey is the document reserving a key
+type idempotencyKey struct {
+	Key       string    `firestore:"key"`
+	ClaimedAt time.Time `firestore:"claimed_at"`
+	ExpiresAt time.Time `firestore:"expires_at"`
+}
+
+// FirestoreStore is a Store backed by Google Cloud Firestore
+type FirestoreStore struct {
+	client *firestore.Client
+}
+
+// NewFirestoreStore creates a Firestore backed store for the given project and database
+// An empty databaseID uses the project's default database
+func NewFirestoreStore(ctx context.Context, projectID, databaseID string) (*FirestoreStore, error) {
+	if databaseID == "" {
+		databaseID = firestore.DefaultDatabaseID
+	}
+
+	client, err := firestore.NewClientWithDatabase(ctx, projectID, databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("error creating firestore client: %w", err)
+	}
+
+	return &FirestoreStore{client: client}, nil
+}
+
+// SaveKudo stores the kudo as a document in the kudos collection
+func (s *FirestoreStore) SaveKudo(ctx context.Context, kudo *models.Kudo) error {
+	if kudo.ID == "" {
+		kudo.ID = uuid.NewString()
+	}
+
+	if _, err := s.client.Collection(kudosCollection).Doc(kudo.ID).Set(ctx, kudo); err != nil {
+		return fmt.Errorf("error saving kudo %s: %w", kudo.ID, err)
+	}
+	return nil
+}
+
+// SaveKudoWithinAllowance sums the points spent in the query and saves the kudo in a single transaction
+func (s *FirestoreStore) SaveKudoWithinAllowance(ctx context.Context, kudo *models.Kudo, query KudoQuery, allowance int) (bool, error) {
+	if kudo.ID == "" {
+		kudo.ID = uuid.NewString()
+	}
+
+	saved := false
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		saved = false
+
+		docs, err := tx.Documents(s.kudoQuery(query)).GetAll()
+		if err != nil {
+			return err
+		}
+		spent := 0
+		for _, doc := range docs {
+			var existing models.Kudo
+			if err := doc.DataTo(&existing); err != nil {
+				return fmt.Errorf("error decoding kudo %s: %w", doc.Ref.ID, err)
+			}
+			spent += existing.TotalPoints()
+		}
+		if spent+kudo.TotalPoints() > allowance {
+			return nil
+		}
+
+		saved = true
+		return tx.Set(s.client.Collection(kudosCollection).Doc(kudo.ID), kudo)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error saving kudo %s within allowance: %w", kudo.ID, err)
+	}
+	return saved, nil
+}
+
+// DeleteKudo deletes the kudo document
+func (s *FirestoreStore) DeleteKudo(ctx context.Context, id string) error {
+	if _, err := s.client.Collection(kudosCollection).Doc(id).Delete(ctx); err != nil {
+		return fmt.Errorf("error deleting kudo %s: %w", id, err)
+	}
+	return nil
+}
+
+// kudoQuery builds the Firestore query of the kudos collection matching query
+func (s *FirestoreStore) kudoQuery(query KudoQuery) firestore.Query {
+	q := s.client.Collection(kudosCollection).Query
+	if query.SenderID != "" {
+		q = q.Where("sender_id", "==", query.SenderID)
+	}
+	if query.RecipientID != "" {
+		q = q.Where("recipient_ids", "array-contains", query.RecipientID)
+	}
+	if !query.From.IsZero() {
+		q = q.Where("created_at", ">=", query.From)
+	}
+	if !query.To.IsZero() {
+		q = q.Where("created_at", "<", query.To)
+	}
+	q = q.OrderBy("created_at", firestore.Asc)
+	if query.Limit > 0 {
+		q = q.Limit(query.Limit)
+	}
+	return q
+}
+
+// ListKudos queries the kudos collection
+// Combining filters with a date range requires composite indexes (see README)
+func (s *FirestoreStore) ListKudos(ctx context.Context, query KudoQuery) ([]*models.Kudo, error) {
+	iter := s.kudoQuery(query).Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.Kudo
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing kudos: %w", err)
+		}
+
+		var kudo models.Kudo
+		if err := doc.DataTo(&kudo); err != nil {
+			return nil, fmt.Errorf("error decoding kudo %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &kudo)
+	}
+
+	return result, nil
+}
+
+// SaveReminderPreference stores the preference as a document keyed by user ID
+func (s *FirestoreStore) SaveReminderPreference(ctx context.Context, preference *models.ReminderPreference) error {
+	if _, err := s.client.Collection(reminderPreferencesCollection).Doc(preference.UserID).Set(ctx, preference); err != nil {
+		return fmt.Errorf("error saving reminder preference for %s: %w", preference.UserID, err)
+	}
+	return nil
+}
+
+// ListReminderPreferences reads the whole reminder_preferences collection
+func (s *FirestoreStore) ListReminderPreferences(ctx context.Context) ([]*models.ReminderPreference, error) {
+	iter := s.client.Collection(reminderPreferencesCollection).Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.ReminderPreference
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing reminder preferences: %w", err)
+		}
+
+		var preference models.ReminderPreference
+		if err := doc.DataTo(&preference); err != nil {
+			return nil, fmt.Errorf("error decoding reminder preference %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &preference)
+	}
+
+	return result, nil
+}
+
+// SaveReminderDelivery stores the delivery as a document keyed by period and user ID
+func (s *FirestoreStore) SaveReminderDelivery(ctx context.Context, delivery *models.ReminderDelivery) error {
+	id := reminderDeliveryID(delivery.Period, delivery.UserID)
+	if err := checkDocumentID(id); err != nil {
+		return fmt.Errorf("error saving reminder delivery: %w", err)
+	}
+	if _, err := s.client.Collection(reminderDeliveriesCollection).Doc(id).Set(ctx, delivery); err != nil {
+		return fmt.Errorf("error saving reminder delivery %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListReminderDeliveries queries the reminder_deliveries collection by period
+func (s *FirestoreStore) ListReminderDeliveries(ctx context.Context, period string) ([]*models.ReminderDelivery, error) {
+	iter := s.client.Collection(reminderDeliveriesCollection).Where("period", "==", period).Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.ReminderDelivery
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing reminder deliveries: %w", err)
+		}
+
+		var delivery models.ReminderDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			return nil, fmt.Errorf("error decoding reminder delivery %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &delivery)
+	}
+
+	return result, nil
+}
+
+// ClaimKey reserves the key in a transaction, so concurrent claims can't both succeed
+// Keys may contain any character but /, which Firestore document IDs reject
+func (s *FirestoreStore) ClaimKey(ctx context.Context, key string, now, expiresAt time.Time) (bool, error) {
+	if err := checkDocumentID(key); err != nil {
+		return false, fmt.Errorf("error claiming key: %w", err)
+	}
+	doc := s.client.Collection(idempotencyKeysCollection).Doc(key)
+
+	claimed := false
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		claimed = false
+
+		snapshot, err := tx.Get(doc)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var existing idempotencyKey
+			if err := snapshot.DataTo(&existing); err != nil {
+				return err
+			}
+			if now.Before(existing.ExpiresAt) {
+				return nil
+			}
+		}
+
+		claimed = true
+		return tx.Set(doc, idempotencyKey{Key: key, ClaimedAt: now, ExpiresAt: expiresAt})
+	})
+	if err != nil {
+		return false, fmt.Errorf("error claiming key %s: %w", key, err)
+	}
+	return claimed, nil
+}
+
+// ReleaseKey deletes the key document
+func (s *FirestoreStore) ReleaseKey(ctx context.Context, key string) error {
+	if _, err := s.client.Collection(idempotencyKeysCollection).Doc(key).Delete(ctx); err != nil {
+		return fmt.Errorf("error releasing key %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetModerationRules reads the moderation document of the settings collection
+func (s *FirestoreStore) GetModerationRules(ctx context.Context) (*models.ModerationRules, error) {
+	doc, err := s.client.Collection(settingsCollection).Doc(moderationDoc).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading moderation rules: %w", err)
+	}
+
+	var rules models.ModerationRules
+	if err := doc.DataTo(&rules); err != nil {
+		return nil, fmt.Errorf("error decoding moderation rules: %w", err)
+	}
+	return &rules, nil
+}
+
+// SaveModerationRules replaces the moderation document of the settings collection
+func (s *FirestoreStore) SaveModerationRules(ctx context.Context, rules *models.ModerationRules) error {
+	if _, err := s.client.Collection(settingsCollection).Doc(moderationDoc).Set(ctx, rules); err != nil {
+		return fmt.Errorf("error saving moderation rules: %w", err)
+	}
+	return nil
+}
+
+// GetCustomKudoType reads a document of the custom_kudo_types collection
+func (s *FirestoreStore) GetCustomKudoType(ctx context.Context, id string) (*models.CustomKudoType, error) {
+	doc, err := s.client.Collection(customKudoTypesCollection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading custom kudo type %s: %w", id, err)
+	}
+
+	var kudoType models.CustomKudoType
+	if err := doc.DataTo(&kudoType); err != nil {
+		return nil, fmt.Errorf("error decoding custom kudo type %s: %w", id, err)
+	}
+	return &kudoType, nil
+}
+
+// SaveCustomKudoType stores the type as a document keyed by its ID
+func (s *FirestoreStore) SaveCustomKudoType(ctx context.Context, kudoType *models.CustomKudoType) error {
+	if _, err := s.client.Collection(customKudoTypesCollection).Doc(kudoType.ID).Set(ctx, kudoType); err != nil {
+		return fmt.Errorf("error saving custom kudo type %s: %w", kudoType.ID, err)
+	}
+	return nil
+}
+
+// RecordCustomKudoTypeUse reads and updates the type document in a transaction, so concurrent kudos are all counted
+func (s *FirestoreStore) RecordCustomKudoTypeUse(ctx context.Context, use *models.CustomKudoType) (*models.CustomKudoType, error) {
+	doc := s.client.Collection(customKudoTypesCollection).Doc(use.ID)
+
+	var kudoType models.CustomKudoType
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		snapshot, err := tx.Get(doc)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			kudoType = models.CustomKudoType{}
+			if err := snapshot.DataTo(&kudoType); err != nil {
+				return err
+			}
+		} else {
+			kudoType = *use
+			kudoType.UsageCount = 0
+		}
+
+		applyCustomKudoTypeUse(&kudoType, use)
+		return tx.Set(doc, &kudoType)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error recording use of custom kudo type %s: %w", use.ID, err)
+	}
+	return &kudoType, nil
+}
+
+// UpdateCustomKudoTypeStatus checks the status and updates the review fields in a transaction
+// usage_count isn't written, so uses recorded meanwhile are kept
+func (s *FirestoreStore) UpdateCustomKudoTypeStatus(ctx context.Context, id string, decision CustomKudoTypeDecision) (*models.CustomKudoType, error) {
+	doc := s.client.Collection(customKudoTypesCollection).Doc(id)
+
+	var updated *models.CustomKudoType
+	err := s.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		updated = nil
+
+		snapshot, err := tx.Get(doc)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var kudoType models.CustomKudoType
+		if err := snapshot.DataTo(&kudoType); err != nil {
+			return err
+		}
+		if kudoType.Status != decision.From {
+			return nil
+		}
+
+		updates := []firestore.Update{{Path: "status", Value: decision.To}}
+		if decision.ResolvedBy != "" {
+			updates = append(updates,
+				firestore.Update{Path: "resolved_type_id", Value: decision.ResolvedTypeID},
+				firestore.Update{Path: "resolved_by", Value: decision.ResolvedBy},
+				firestore.Update{Path: "resolved_at", Value: decision.ResolvedAt},
+			)
+		}
+		applyCustomKudoTypeDecision(&kudoType, decision)
+		updated = &kudoType
+		return tx.Update(doc, updates)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating status of custom kudo type %s: %w", id, err)
+	}
+	return updated, nil
+}
+
+// ListCustomKudoTypes queries the workspace's custom kudo types
+// They are sorted in memory, so no composite index is needed
+func (s *FirestoreStore) ListCustomKudoTypes(ctx context.Context, teamID string) ([]*models.CustomKudoType, error) {
+	iter := s.client.Collection(customKudoTypesCollection).Where("team_id", "==", teamID).Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.CustomKudoType
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing custom kudo types: %w", err)
+		}
+
+		var kudoType models.CustomKudoType
+		if err := doc.DataTo(&kudoType); err != nil {
+			return nil, fmt.Errorf("error decoding custom kudo type %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &kudoType)
+	}
+
+	sortByLastUse(result)
+	return result, nil
+}
+
+// GetKudoType reads a document of the kudo_types collection
+func (s *FirestoreStore) GetKudoType(ctx context.Context, id string) (*models.KudoType, error) {
+	doc, err := s.client.Collection(kudoTypesCollection).Doc(id).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading kudo type %s: %w", id, err)
+	}
+
+	var kudoType models.KudoType
+	if err := doc.DataTo(&kudoType); err != nil {
+		return nil, fmt.Errorf("error decoding kudo type %s: %w", id, err)
+	}
+	return &kudoType, nil
+}
+
+// SaveKudoType stores the official type as a document keyed by its ID
+func (s *FirestoreStore) SaveKudoType(ctx context.Context, kudoType *models.KudoType) error {
+	if _, err := s.client.Collection(kudoTypesCollection).Doc(kudoType.ID).Set(ctx, kudoType); err != nil {
+		return fmt.Errorf("error saving kudo type %s: %w", kudoType.ID, err)
+	}
+	return nil
+}
+
+// ListKudoTypes reads every document of the kudo_types collection
+func (s *FirestoreStore) ListKudoTypes(ctx context.Context) ([]*models.KudoType, error) {
+	iter := s.client.Collection(kudoTypesCollection).Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.KudoType
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing kudo types: %w", err)
+		}
+
+		var kudoType models.KudoType
+		if err := doc.DataTo(&kudoType); err != nil {
+			return nil, fmt.Errorf("error decoding kudo type %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &kudoType)
+	}
+
+	sortByCreation(result)
+	return result, nil
+}
+
+// DeleteKudoType deletes a document of the kudo_types collection
+func (s *FirestoreStore) DeleteKudoType(ctx context.Context, id string) error {
+	if _, err := s.client.Collection(kudoTypesCollection).Doc(id).Delete(ctx); err != nil {
+		return fmt.Errorf("error deleting kudo type %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetSettings reads the admin document of the settings collection
+func (s *FirestoreStore) GetSettings(ctx context.Context) (*models.Settings, error) {
+	doc, err := s.client.Collection(settingsCollection).Doc(adminSettingsDoc).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading settings: %w", err)
+	}
+
+	var settings models.Settings
+	if err := doc.DataTo(&settings); err != nil {
+		return nil, fmt.Errorf("error decoding settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SaveSettings replaces the admin document of the settings collection
+func (s *FirestoreStore) SaveSettings(ctx context.Context, settings *models.Settings) error {
+	if _, err := s.client.Collection(settingsCollection).Doc(adminSettingsDoc).Set(ctx, settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	return nil
+}
+
+// AppendAuditEntry creates a document of the audit_log collection, failing if it already exists
+func (s *FirestoreStore) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+
+	if _, err := s.client.Collection(auditLogCollection).Doc(entry.ID).Create(ctx, entry); err != nil {
+		return fmt.Errorf("error appending audit entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// ListAuditEntries queries the audit_log collection
+// Combining filters with a date range requires composite indexes (see README)
+func (s *FirestoreStore) ListAuditEntries(ctx context.Context, query AuditQuery) ([]*models.AuditEntry, error) {
+	q := s.client.Collection(auditLogCollection).Query
+	if query.ActorID != "" {
+		q = q.Where("actor_id", "==", query.ActorID)
+	}
+	if query.Action != "" {
+		q = q.Where("action", "==", query.Action)
+	}
+	if !query.From.IsZero() {
+		q = q.Where("created_at", ">=", query.From)
+	}
+	if !query.To.IsZero() {
+		q = q.Where("created_at", "<", query.To)
+	}
+	q = q.OrderBy("created_at", firestore.Asc)
+
+	iter := q.Documents(ctx)
+	defer iter.Stop()
+
+	var result []*models.AuditEntry
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error listing audit entries: %w", err)
+		}
+
+		var entry models.AuditEntry
+		if err := doc.DataTo(&entry); err != nil {
+			return nil, fmt.Errorf("error decoding audit entry %s: %w", doc.Ref.ID, err)
+		}
+		result = append(result, &entry)
+	}
+
+	return result, nil
+}
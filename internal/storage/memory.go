@@ -0,0 +1,412 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// MemoryStore is an in-process Store used for local development and tests
+// Data is lost when the process exits
+type MemoryStore struct {
+	mu          sync.Mutex
+	kudos       []*models.Kudo
+	preferences map[string]models.ReminderPreference
+	deliveries  map[string]models.ReminderDelivery
+	keys        map[string]time.Time // idempotency keys and their expiry
+	moderation  *models.ModerationRules
+	customTypes map[string]models.CustomKudoType
+	kudoTypes   map[string]models.KudoType
+	settings    *models.Settings
+	auditLog    []models.AuditEntry
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// SaveKudo stores a copy of the kudo
+func (s *MemoryStore) SaveKudo(ctx context.Context, kudo *models.Kudo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.saveKudo(kudo)
+	return nil
+}
+
+// SaveKudoWithinAllowance sums the points spent in the query and saves the kudo while holding the lock
+func (s *MemoryStore) SaveKudoWithinAllowance(ctx context.Context, kudo *models.Kudo, query KudoQuery, allowance int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spent := 0
+	for _, existing := range s.kudos {
+		if query.matches(existing) {
+			spent += existing.TotalPoints()
+		}
+	}
+	if spent+kudo.TotalPoints() > allowance {
+		return false, nil
+	}
+
+	s.saveKudo(kudo)
+	return true, nil
+}
+
+// DeleteKudo removes the kudo with the ID, missing kudos are ignored
+func (s *MemoryStore) DeleteKudo(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.kudos = slices.DeleteFunc(s.kudos, func(kudo *models.Kudo) bool { return kudo.ID == id })
+	return nil
+}
+
+// saveKudo stores a copy of the kudo, replacing the one with the same ID
+// Callers hold the lock
+func (s *MemoryStore) saveKudo(kudo *models.Kudo) {
+	if kudo.ID == "" {
+		kudo.ID = uuid.NewString()
+	}
+
+	stored := *kudo
+	stored.RecipientIDs = append([]string(nil), kudo.RecipientIDs...)
+	for i, existing := range s.kudos {
+		if existing.ID == kudo.ID {
+			s.kudos[i] = &stored
+			return
+		}
+	}
+	s.kudos = append(s.kudos, &stored)
+}
+
+// ListKudos returns copies of the kudos matching the query
+func (s *MemoryStore) ListKudos(ctx context.Context, query KudoQuery) ([]*models.Kudo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.Kudo
+	for _, kudo := range s.kudos {
+		if !query.matches(kudo) {
+			continue
+		}
+		found := *kudo
+		found.RecipientIDs = append([]string(nil), kudo.RecipientIDs...)
+		result = append(result, &found)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result, nil
+}
+
+// SaveReminderPreference stores a copy of the preference keyed by user
+func (s *MemoryStore) SaveReminderPreference(ctx context.Context, preference *models.ReminderPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.preferences == nil {
+		s.preferences = make(map[string]models.ReminderPreference)
+	}
+	s.preferences[preference.UserID] = *preference
+	return nil
+}
+
+// ListReminderPreferences returns copies of the stored preferences ordered by user
+func (s *MemoryStore) ListReminderPreferences(ctx context.Context) ([]*models.ReminderPreference, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.ReminderPreference
+	for _, preference := range s.preferences {
+		found := preference
+		result = append(result, &found)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UserID < result[j].UserID
+	})
+	return result, nil
+}
+
+// SaveReminderDelivery stores a copy of the delivery keyed by period and user
+func (s *MemoryStore) SaveReminderDelivery(ctx context.Context, delivery *models.ReminderDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := reminderDeliveryID(delivery.Period, delivery.UserID)
+	if err := checkDocumentID(id); err != nil {
+		return fmt.Errorf("error saving reminder delivery: %w", err)
+	}
+	if s.deliveries == nil {
+		s.deliveries = make(map[string]models.ReminderDelivery)
+	}
+	s.deliveries[id] = *delivery
+	return nil
+}
+
+// ListReminderDeliveries returns copies of the period's deliveries ordered by user
+func (s *MemoryStore) ListReminderDeliveries(ctx context.Context, period string) ([]*models.ReminderDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.ReminderDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Period != period {
+			continue
+		}
+		found := delivery
+		result = append(result, &found)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UserID < result[j].UserID
+	})
+	return result, nil
+}
+
+// ClaimKey reserves the key unless it's reserved and not expired
+func (s *MemoryStore) ClaimKey(ctx context.Context, key string, now, expiresAt time.Time) (bool, error) {
+	if err := checkDocumentID(key); err != nil {
+		return false, fmt.Errorf("error claiming key: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiry, ok := s.keys[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	if s.keys == nil {
+		s.keys = make(map[string]time.Time)
+	}
+	s.keys[key] = expiresAt
+	return true, nil
+}
+
+// ReleaseKey forgets the key
+func (s *MemoryStore) ReleaseKey(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+// GetModerationRules returns a copy of the saved rules
+func (s *MemoryStore) GetModerationRules(ctx context.Context) (*models.ModerationRules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.moderation == nil {
+		return nil, nil
+	}
+	found := *s.moderation
+	found.BlockedWords = append([]string(nil), s.moderation.BlockedWords...)
+	return &found, nil
+}
+
+// SaveModerationRules stores a copy of the rules
+func (s *MemoryStore) SaveModerationRules(ctx context.Context, rules *models.ModerationRules) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *rules
+	stored.BlockedWords = append([]string(nil), rules.BlockedWords...)
+	s.moderation = &stored
+	return nil
+}
+
+// GetCustomKudoType returns a copy of the saved type
+func (s *MemoryStore) GetCustomKudoType(ctx context.Context, id string) (*models.CustomKudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kudoType, ok := s.customTypes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &kudoType, nil
+}
+
+// SaveCustomKudoType stores a copy of the type keyed by ID
+func (s *MemoryStore) SaveCustomKudoType(ctx context.Context, kudoType *models.CustomKudoType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.customTypes == nil {
+		s.customTypes = make(map[string]models.CustomKudoType)
+	}
+	s.customTypes[kudoType.ID] = *kudoType
+	return nil
+}
+
+// RecordCustomKudoTypeUse counts the use under the lock and returns a copy of the saved type
+func (s *MemoryStore) RecordCustomKudoTypeUse(ctx context.Context, use *models.CustomKudoType) (*models.CustomKudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.customTypes == nil {
+		s.customTypes = make(map[string]models.CustomKudoType)
+	}
+	kudoType, ok := s.customTypes[use.ID]
+	if !ok {
+		kudoType = *use
+		kudoType.UsageCount = 0
+	}
+	applyCustomKudoTypeUse(&kudoType, use)
+	s.customTypes[use.ID] = kudoType
+	return &kudoType, nil
+}
+
+// UpdateCustomKudoTypeStatus applies the decision under the lock and returns a copy of the updated type
+func (s *MemoryStore) UpdateCustomKudoTypeStatus(ctx context.Context, id string, decision CustomKudoTypeDecision) (*models.CustomKudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kudoType, ok := s.customTypes[id]
+	if !ok || kudoType.Status != decision.From {
+		return nil, nil
+	}
+	applyCustomKudoTypeDecision(&kudoType, decision)
+	s.customTypes[id] = kudoType
+	return &kudoType, nil
+}
+
+// ListCustomKudoTypes returns copies of the workspace's types, most recently used first
+func (s *MemoryStore) ListCustomKudoTypes(ctx context.Context, teamID string) ([]*models.CustomKudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.CustomKudoType
+	for _, kudoType := range s.customTypes {
+		if kudoType.TeamID != teamID {
+			continue
+		}
+		found := kudoType
+		result = append(result, &found)
+	}
+
+	sortByLastUse(result)
+	return result, nil
+}
+
+// GetKudoType returns a copy of the saved official type
+func (s *MemoryStore) GetKudoType(ctx context.Context, id string) (*models.KudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kudoType, ok := s.kudoTypes[id]
+	if !ok {
+		return nil, nil
+	}
+	return &kudoType, nil
+}
+
+// SaveKudoType stores a copy of the official type keyed by ID
+func (s *MemoryStore) SaveKudoType(ctx context.Context, kudoType *models.KudoType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.kudoTypes == nil {
+		s.kudoTypes = make(map[string]models.KudoType)
+	}
+	s.kudoTypes[kudoType.ID] = *kudoType
+	return nil
+}
+
+// ListKudoTypes returns copies of the official types, oldest first
+func (s *MemoryStore) ListKudoTypes(ctx context.Context) ([]*models.KudoType, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*models.KudoType, 0, len(s.kudoTypes))
+	for _, kudoType := range s.kudoTypes {
+		found := kudoType
+		result = append(result, &found)
+	}
+
+	sortByCreation(result)
+	return result, nil
+}
+
+// DeleteKudoType removes the official type
+func (s *MemoryStore) DeleteKudoType(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.kudoTypes, id)
+	return nil
+}
+
+// GetSettings returns a copy of the saved settings
+func (s *MemoryStore) GetSettings(ctx context.Context) (*models.Settings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.settings == nil {
+		return nil, nil
+	}
+	settings := *s.settings
+	settings.Reminder.ExcludeUserIDs = append([]string(nil), s.settings.Reminder.ExcludeUserIDs...)
+	return &settings, nil
+}
+
+// SaveSettings stores a copy of the settings
+func (s *MemoryStore) SaveSettings(ctx context.Context, settings *models.Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *settings
+	stored.Reminder.ExcludeUserIDs = append([]string(nil), settings.Reminder.ExcludeUserIDs...)
+	s.settings = &stored
+	return nil
+}
+
+// AppendAuditEntry stores a copy of the entry
+func (s *MemoryStore) AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+	for _, stored := range s.auditLog {
+		if stored.ID == entry.ID {
+			return fmt.Errorf("audit entry %s already exists", entry.ID)
+		}
+	}
+	s.auditLog = append(s.auditLog, *entry)
+	return nil
+}
+
+// ListAuditEntries returns copies of the entries matching the query
+func (s *MemoryStore) ListAuditEntries(ctx context.Context, query AuditQuery) ([]*models.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*models.AuditEntry
+	for _, entry := range s.auditLog {
+		if !query.matches(&entry) {
+			continue
+		}
+		found := entry
+		result = append(result, &found)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
@@ -0,0 +1,601 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vyper/my-matter/internal/models"
+)
+
+func TestMemoryStore_SaveKudo(t *testing.T) {
+	store := NewMemoryStore()
+	kudo := &models.Kudo{
+		SenderID:     "U111111",
+		RecipientIDs: []string{"U222222"},
+		CreatedAt:    time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	if err := store.SaveKudo(context.Background(), kudo); err != nil {
+		t.Fatalf("SaveKudo() unexpected error = %v", err)
+	}
+
+	if kudo.ID == "" {
+		t.Error("SaveKudo() should assign an ID")
+	}
+
+	// Mutating the original must not affect the stored copy
+	kudo.RecipientIDs[0] = "U999999"
+
+	kudos, err := store.ListKudos(context.Background(), KudoQuery{})
+	if err != nil {
+		t.Fatalf("ListKudos() unexpected error = %v", err)
+	}
+	if len(kudos) != 1 {
+		t.Fatalf("expected 1 kudo, got %d", len(kudos))
+	}
+	if kudos[0].RecipientIDs[0] != "U222222" {
+		t.Errorf("stored recipient = %s, want U222222", kudos[0].RecipientIDs[0])
+	}
+
+	// Saving the same ID replaces the stored kudo
+	kudo.MessageTS = "1234567890.123456"
+	store.SaveKudo(context.Background(), kudo)
+	kudos, _ = store.ListKudos(context.Background(), KudoQuery{})
+	if len(kudos) != 1 || kudos[0].MessageTS != "1234567890.123456" {
+		t.Errorf("kudos = %+v, want the kudo replaced", kudos)
+	}
+
+	if err := store.DeleteKudo(context.Background(), kudo.ID); err != nil {
+		t.Fatalf("DeleteKudo() unexpected error = %v", err)
+	}
+	kudos, _ = store.ListKudos(context.Background(), KudoQuery{})
+	if len(kudos) != 0 {
+		t.Errorf("expected no kudos after DeleteKudo(), got %d", len(kudos))
+	}
+}
+
+func TestMemoryStore_SaveKudoWithinAllowance(t *testing.T) {
+	march := KudoQuery{SenderID: "U111111", From: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name      string
+		points    int
+		wantSaved bool
+	}{
+		{name: "fits the allowance", points: 20, wantSaved: true},
+		{name: "spends the whole allowance", points: 30, wantSaved: true},
+		{name: "exceeds the allowance", points: 31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewMemoryStore()
+			// 40 points spent in March, other senders and months don't count
+			store.SaveKudo(ctx, &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, Points: 20, CreatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)})
+			store.SaveKudo(ctx, &models.Kudo{SenderID: "U444444", RecipientIDs: []string{"U222222"}, Points: 50, CreatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)})
+			store.SaveKudo(ctx, &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222"}, Points: 50, CreatedAt: time.Date(2026, 2, 27, 9, 0, 0, 0, time.UTC)})
+
+			kudo := &models.Kudo{SenderID: "U111111", RecipientIDs: []string{"U222222", "U333333"}, Points: tt.points, CreatedAt: time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)}
+			saved, err := store.SaveKudoWithinAllowance(ctx, kudo, march, 100)
+			if err != nil {
+				t.Fatalf("SaveKudoWithinAllowance() unexpected error = %v", err)
+			}
+			if saved != tt.wantSaved {
+				t.Errorf("saved = %v, want %v", saved, tt.wantSaved)
+			}
+
+			kudos, _ := store.ListKudos(ctx, march)
+			if wantCount := map[bool]int{true: 2, false: 1}[tt.wantSaved]; len(kudos) != wantCount {
+				t.Errorf("got %d kudos in March, want %d", len(kudos), wantCount)
+			}
+		})
+	}
+}
+
+func TestMemoryStore_ListKudos(t *testing.T) {
+	march := func(day int) time.Time {
+		return time.Date(2026, 3, day, 12, 0, 0, 0, time.UTC)
+	}
+
+	store := NewMemoryStore()
+	fixtures := []*models.Kudo{
+		{ID: "k3", SenderID: "U111111", RecipientIDs: []string{"U333333"}, CreatedAt: march(20)},
+		{ID: "k1", SenderID: "U111111", RecipientIDs: []string{"U222222"}, CreatedAt: march(1)},
+		{ID: "k2", SenderID: "U222222", RecipientIDs: []string{"U111111", "U333333"}, CreatedAt: march(10)},
+	}
+	for _, kudo := range fixtures {
+		if err := store.SaveKudo(context.Background(), kudo); err != nil {
+			t.Fatalf("SaveKudo() unexpected error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    KudoQuery
+		expected []string
+	}{
+		{
+			name:     "no filters returns all ordered by creation",
+			query:    KudoQuery{},
+			expected: []string{"k1", "k2", "k3"},
+		},
+		{
+			name:     "filter by sender",
+			query:    KudoQuery{SenderID: "U111111"},
+			expected: []string{"k1", "k3"},
+		},
+		{
+			name:     "filter by recipient",
+			query:    KudoQuery{RecipientID: "U333333"},
+			expected: []string{"k2", "k3"},
+		},
+		{
+			name:     "from is inclusive",
+			query:    KudoQuery{From: march(10)},
+			expected: []string{"k2", "k3"},
+		},
+		{
+			name:     "to is exclusive",
+			query:    KudoQuery{To: march(10)},
+			expected: []string{"k1"},
+		},
+		{
+			name:     "combined filters",
+			query:    KudoQuery{SenderID: "U111111", From: march(2), To: march(31)},
+			expected: []string{"k3"},
+		},
+		{
+			name:     "limit keeps the oldest",
+			query:    KudoQuery{Limit: 2},
+			expected: []string{"k1", "k2"},
+		},
+		{
+			name:     "no matches",
+			query:    KudoQuery{SenderID: "U444444"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kudos, err := store.ListKudos(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("ListKudos() unexpected error = %v", err)
+			}
+
+			if len(kudos) != len(tt.expected) {
+				t.Fatalf("ListKudos() returned %d kudos, want %d", len(kudos), len(tt.expected))
+			}
+			for i, id := range tt.expected {
+				if kudos[i].ID != id {
+					t.Errorf("kudo %d: ID = %s, want %s", i, kudos[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStore_ReminderPreferences(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	until := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+
+	saves := []*models.ReminderPreference{
+		{UserID: "U222222", OptedOut: true},
+		{UserID: "U111111", SnoozedUntil: until},
+		// Replaces the opt-out
+		{UserID: "U222222", SnoozedUntil: until},
+	}
+	for _, preference := range saves {
+		if err := store.SaveReminderPreference(ctx, preference); err != nil {
+			t.Fatalf("SaveReminderPreference() unexpected error = %v", err)
+		}
+	}
+
+	// Mutating the original must not affect the stored copy
+	saves[2].OptedOut = true
+
+	preferences, err := store.ListReminderPreferences(ctx)
+	if err != nil {
+		t.Fatalf("ListReminderPreferences() unexpected error = %v", err)
+	}
+	if len(preferences) != 2 {
+		t.Fatalf("expected 2 preferences, got %d", len(preferences))
+	}
+	for i, userID := range []string{"U111111", "U222222"} {
+		if preferences[i].UserID != userID || preferences[i].OptedOut || !preferences[i].SnoozedUntil.Equal(until) {
+			t.Errorf("preference %d = %+v, want %s snoozed until %v", i, preferences[i], userID, until)
+		}
+	}
+}
+
+func TestMemoryStore_ReminderDeliveries(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for _, delivery := range []*models.ReminderDelivery{
+		{Period: "2026-W11", UserID: "U222222", EventID: "e1"},
+		{Period: "2026-W11", UserID: "U111111", EventID: "e1"},
+		{Period: "2026-W12", UserID: "U111111", EventID: "e2"},
+		// A redelivered run overwrites the record
+		{Period: "2026-W11", UserID: "U222222", EventID: "e1-retry"},
+	} {
+		if err := store.SaveReminderDelivery(ctx, delivery); err != nil {
+			t.Fatalf("SaveReminderDelivery() unexpected error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		period   string
+		expected []string
+	}{
+		{period: "2026-W11", expected: []string{"U111111/e1", "U222222/e1-retry"}},
+		{period: "2026-W12", expected: []string{"U111111/e2"}},
+		{period: "2026-W13", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			deliveries, err := store.ListReminderDeliveries(ctx, tt.period)
+			if err != nil {
+				t.Fatalf("ListReminderDeliveries() unexpected error = %v", err)
+			}
+
+			var got []string
+			for _, delivery := range deliveries {
+				got = append(got, delivery.UserID+"/"+delivery.EventID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ListReminderDeliveries() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckDocumentID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{id: reminderDeliveryID("2026-W11", "U111111"), valid: true},
+		{id: reminderDeliveryID("2026-W11:month-end", "U111111"), valid: true},
+		{id: "digest:2026-03:C123456", valid: true},
+		{id: reminderDeliveryID("2026-W11/month-end", "U111111")},
+		{id: ""},
+		{id: ".."},
+		{id: "__reserved__"},
+		{id: strings.Repeat("a", 1501)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if err := checkDocumentID(tt.id); (err == nil) != tt.valid {
+				t.Errorf("checkDocumentID(%q) error = %v, want valid = %v", tt.id, err, tt.valid)
+			}
+		})
+	}
+
+	// The memory store fails like Firestore would
+	store := NewMemoryStore()
+	if err := store.SaveReminderDelivery(context.Background(), &models.ReminderDelivery{Period: "2026-W11/month-end", UserID: "U111111"}); err == nil {
+		t.Error("SaveReminderDelivery() expected error for a period with /, got nil")
+	}
+	if _, err := store.ClaimKey(context.Background(), "digest/2026-03", time.Now(), time.Now().Add(time.Hour)); err == nil {
+		t.Error("ClaimKey() expected error for a key with /, got nil")
+	}
+}
+
+func TestMemoryStore_ClaimKey(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name    string
+		key     string
+		at      time.Time
+		release bool
+		want    bool
+	}{
+		{name: "first claim", key: "view:V1", at: now, want: true},
+		{name: "claimed again", key: "view:V1", at: now.Add(time.Minute), want: false},
+		{name: "other key", key: "view:V2", at: now.Add(time.Minute), want: true},
+		{name: "after expiry", key: "view:V1", at: now.Add(2 * time.Minute), want: true},
+		{name: "released", key: "view:V2", at: now.Add(time.Minute), release: true, want: true},
+	}
+
+	for _, step := range steps {
+		if step.release {
+			if err := store.ReleaseKey(ctx, step.key); err != nil {
+				t.Fatalf("%s: ReleaseKey() unexpected error = %v", step.name, err)
+			}
+		}
+
+		got, err := store.ClaimKey(ctx, step.key, step.at, step.at.Add(2*time.Minute))
+		if err != nil {
+			t.Fatalf("%s: ClaimKey() unexpected error = %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: ClaimKey() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestMemoryStore_ModerationRules(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	rules, err := store.GetModerationRules(ctx)
+	if err != nil || rules != nil {
+		t.Fatalf("GetModerationRules() = %v, %v, want nil before saving", rules, err)
+	}
+
+	saved := &models.ModerationRules{MaxLength: 500, BlockedWords: []string{"idiota"}}
+	if err := store.SaveModerationRules(ctx, saved); err != nil {
+		t.Fatalf("SaveModerationRules() unexpected error = %v", err)
+	}
+
+	// Mutating the original must not affect the stored copy
+	saved.BlockedWords[0] = "outra"
+
+	rules, err = store.GetModerationRules(ctx)
+	if err != nil {
+		t.Fatalf("GetModerationRules() unexpected error = %v", err)
+	}
+	if rules.MaxLength != 500 || len(rules.BlockedWords) != 1 || rules.BlockedWords[0] != "idiota" {
+		t.Errorf("rules = %+v, want the saved rules", rules)
+	}
+}
+
+func TestMemoryStore_CustomKudoTypes(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	found, err := store.GetCustomKudoType(ctx, "T1_rock")
+	if err != nil || found != nil {
+		t.Fatalf("GetCustomKudoType() = %v, %v, want nil before saving", found, err)
+	}
+
+	for _, kudoType := range []*models.CustomKudoType{
+		{ID: "T1_rock", TeamID: "T1", Name: "Rockstar", LastUsedAt: now.Add(-time.Hour)},
+		{ID: "T1_hero", TeamID: "T1", Name: "Herói", LastUsedAt: now},
+		{ID: "T2_rock", TeamID: "T2", Name: "Rockstar", LastUsedAt: now},
+	} {
+		if err := store.SaveCustomKudoType(ctx, kudoType); err != nil {
+			t.Fatalf("SaveCustomKudoType() unexpected error = %v", err)
+		}
+	}
+
+	found, err = store.GetCustomKudoType(ctx, "T1_rock")
+	if err != nil || found == nil || found.Name != "Rockstar" {
+		t.Fatalf("GetCustomKudoType() = %v, %v, want Rockstar", found, err)
+	}
+
+	types, err := store.ListCustomKudoTypes(ctx, "T1")
+	if err != nil {
+		t.Fatalf("ListCustomKudoTypes() unexpected error = %v", err)
+	}
+	if len(types) != 2 || types[0].ID != "T1_hero" || types[1].ID != "T1_rock" {
+		t.Errorf("ListCustomKudoTypes() = %+v, want T1's types, most recently used first", types)
+	}
+}
+
+func TestMemoryStore_RecordCustomKudoTypeUse(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	const uses = 50
+	counts := make(chan int, uses)
+	var wg sync.WaitGroup
+	for i := range uses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			kudoType, err := store.RecordCustomKudoTypeUse(ctx, &models.CustomKudoType{
+				ID:         "T1_rock",
+				TeamID:     "T1",
+				Emoji:      ":guitar:",
+				Name:       "Rockstar",
+				CreatedBy:  "U111111",
+				CreatedAt:  now,
+				LastUsedAt: now.Add(time.Duration(i) * time.Minute),
+			})
+			if err != nil {
+				t.Errorf("RecordCustomKudoTypeUse() unexpected error = %v", err)
+				return
+			}
+			counts <- kudoType.UsageCount
+		}()
+	}
+	wg.Wait()
+	close(counts)
+
+	// Each use gets its own count, so exactly one of them sees any given threshold
+	var seen []int
+	for count := range counts {
+		seen = append(seen, count)
+	}
+	slices.Sort(seen)
+	for i, count := range seen {
+		if count != i+1 {
+			t.Fatalf("counts = %v, want 1 to %d once each", seen, uses)
+		}
+	}
+
+	found, _ := store.GetCustomKudoType(ctx, "T1_rock")
+	if found.UsageCount != uses || !found.LastUsedAt.Equal(now.Add((uses-1)*time.Minute)) {
+		t.Errorf("saved type = %+v, want %d uses and the latest use", found, uses)
+	}
+
+	// An older use is counted without moving the emoji and the last use back
+	older, err := store.RecordCustomKudoTypeUse(ctx, &models.CustomKudoType{ID: "T1_rock", TeamID: "T1", Emoji: ":star:", Name: "rockstar", LastUsedAt: now})
+	if err != nil {
+		t.Fatalf("RecordCustomKudoTypeUse() unexpected error = %v", err)
+	}
+	if older.UsageCount != uses+1 || older.Emoji != ":guitar:" || older.Name != "Rockstar" || older.CreatedBy != "U111111" {
+		t.Errorf("saved type = %+v, want the use counted and the rest kept", older)
+	}
+}
+
+func TestMemoryStore_UpdateCustomKudoTypeStatus(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	store.SaveCustomKudoType(ctx, &models.CustomKudoType{ID: "T1_rock", TeamID: "T1", Name: "Rockstar", UsageCount: 1})
+
+	steps := []struct {
+		name     string
+		id       string
+		decision CustomKudoTypeDecision
+		want     string // status of the returned type, empty when nothing changed
+	}{
+		{name: "missing", id: "T1_none", decision: CustomKudoTypeDecision{To: models.CustomKudoTypeSuggested}},
+		{name: "suggested", id: "T1_rock", decision: CustomKudoTypeDecision{To: models.CustomKudoTypeSuggested}, want: models.CustomKudoTypeSuggested},
+		{name: "suggested again", id: "T1_rock", decision: CustomKudoTypeDecision{To: models.CustomKudoTypeSuggested}},
+		{name: "promoted", id: "T1_rock", decision: CustomKudoTypeDecision{From: models.CustomKudoTypeSuggested, To: models.CustomKudoTypePromoted, ResolvedTypeID: "rockstar", ResolvedBy: "UADMIN1", ResolvedAt: now}, want: models.CustomKudoTypePromoted},
+		{name: "dismissed after the promotion", id: "T1_rock", decision: CustomKudoTypeDecision{From: models.CustomKudoTypeSuggested, To: models.CustomKudoTypeDismissed, ResolvedBy: "UADMIN2", ResolvedAt: now}},
+	}
+
+	for _, step := range steps {
+		// Uses recorded between decisions are kept
+		store.RecordCustomKudoTypeUse(ctx, &models.CustomKudoType{ID: "T1_rock", LastUsedAt: now})
+
+		updated, err := store.UpdateCustomKudoTypeStatus(ctx, step.id, step.decision)
+		if err != nil {
+			t.Fatalf("%s: UpdateCustomKudoTypeStatus() unexpected error = %v", step.name, err)
+		}
+		if (updated != nil) != (step.want != "") || (updated != nil && updated.Status != step.want) {
+			t.Errorf("%s: UpdateCustomKudoTypeStatus() = %+v, want status %q", step.name, updated, step.want)
+		}
+	}
+
+	saved, _ := store.GetCustomKudoType(ctx, "T1_rock")
+	if saved.Status != models.CustomKudoTypePromoted || saved.ResolvedBy != "UADMIN1" || saved.ResolvedTypeID != "rockstar" || saved.UsageCount != 1+len(steps) {
+		t.Errorf("saved type = %+v, want the first decision and every use", saved)
+	}
+}
+
+func TestMemoryStore_KudoTypes(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	found, err := store.GetKudoType(ctx, "rockstar")
+	if err != nil || found != nil {
+		t.Fatalf("GetKudoType() = %v, %v, want nil before saving", found, err)
+	}
+
+	for _, kudoType := range []*models.KudoType{
+		{ID: "rockstar", Name: "Rockstar", CreatedAt: now},
+		{ID: "heroi", Name: "Herói", CreatedAt: now.Add(-time.Hour)},
+	} {
+		if err := store.SaveKudoType(ctx, kudoType); err != nil {
+			t.Fatalf("SaveKudoType() unexpected error = %v", err)
+		}
+	}
+
+	found, err = store.GetKudoType(ctx, "rockstar")
+	if err != nil || found == nil || found.Name != "Rockstar" {
+		t.Fatalf("GetKudoType() = %v, %v, want Rockstar", found, err)
+	}
+
+	types, err := store.ListKudoTypes(ctx)
+	if err != nil {
+		t.Fatalf("ListKudoTypes() unexpected error = %v", err)
+	}
+	if len(types) != 2 || types[0].ID != "heroi" || types[1].ID != "rockstar" {
+		t.Errorf("ListKudoTypes() = %+v, want the official types, oldest first", types)
+	}
+
+	if err := store.DeleteKudoType(ctx, "heroi"); err != nil {
+		t.Fatalf("DeleteKudoType() unexpected error = %v", err)
+	}
+	types, _ = store.ListKudoTypes(ctx)
+	if len(types) != 1 || types[0].ID != "rockstar" {
+		t.Errorf("ListKudoTypes() after delete = %+v, want only rockstar", types)
+	}
+}
+
+func TestMemoryStore_Settings(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	settings, err := store.GetSettings(ctx)
+	if err != nil || settings != nil {
+		t.Fatalf("GetSettings() = %v, %v, want nil before saving", settings, err)
+	}
+
+	saved := &models.Settings{
+		KudosChannelID: "C111111",
+		Reminder:       models.ReminderSettings{Weekday: 1, Hour: 10, ExcludeUserIDs: []string{"U111111"}},
+	}
+	if err := store.SaveSettings(ctx, saved); err != nil {
+		t.Fatalf("SaveSettings() unexpected error = %v", err)
+	}
+	saved.Reminder.ExcludeUserIDs[0] = "U999999"
+
+	settings, err = store.GetSettings(ctx)
+	if err != nil {
+		t.Fatalf("GetSettings() unexpected error = %v", err)
+	}
+	if settings.KudosChannelID != "C111111" || settings.Reminder.Weekday != 1 || settings.Reminder.ExcludeUserIDs[0] != "U111111" {
+		t.Errorf("settings = %+v, want a copy of the saved settings", settings)
+	}
+}
+
+func TestMemoryStore_AuditLog(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	entries := []*models.AuditEntry{
+		{Action: models.AuditSettingsUpdated, ActorID: "UADMIN1", CreatedAt: base.Add(2 * time.Hour)},
+		{Action: models.AuditKudoSubmitted, ActorID: "U111111", TargetID: "kudo-1", CreatedAt: base},
+		{Action: models.AuditKudoSubmitted, ActorID: "U222222", TargetID: "kudo-2", CreatedAt: base.Add(time.Hour)},
+	}
+	for _, entry := range entries {
+		if err := store.AppendAuditEntry(ctx, entry); err != nil {
+			t.Fatalf("AppendAuditEntry() unexpected error = %v", err)
+		}
+		if entry.ID == "" {
+			t.Fatal("AppendAuditEntry() did not assign an ID")
+		}
+	}
+	if err := store.AppendAuditEntry(ctx, &models.AuditEntry{ID: entries[0].ID, Action: models.AuditKudoRejected}); err == nil {
+		t.Error("AppendAuditEntry() with an existing ID expected error, got nil")
+	}
+
+	tests := []struct {
+		name     string
+		query    AuditQuery
+		expected []string
+	}{
+		{name: "every entry, oldest first", expected: []string{"U111111", "U222222", "UADMIN1"}},
+		{name: "by actor", query: AuditQuery{ActorID: "U222222"}, expected: []string{"U222222"}},
+		{name: "by action", query: AuditQuery{Action: models.AuditKudoSubmitted}, expected: []string{"U111111", "U222222"}},
+		{name: "by date", query: AuditQuery{From: base.Add(time.Hour), To: base.Add(2 * time.Hour)}, expected: []string{"U222222"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := store.ListAuditEntries(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("ListAuditEntries() unexpected error = %v", err)
+			}
+			var actors []string
+			for _, entry := range found {
+				actors = append(actors, entry.ActorID)
+			}
+			if !slices.Equal(actors, tt.expected) {
+				t.Errorf("actors = %v, want %v", actors, tt.expected)
+			}
+		})
+	}
+}
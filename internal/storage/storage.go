@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vyper/my-matter/internal/models"
+)
+
+// KudoQuery filters the kudos returned by ListKudos
+// Zero-valued fields are ignored
+type KudoQuery struct {
+	SenderID    string
+	RecipientID string
+	From        time.Time // inclusive
+	To          time.Time // exclusive
+	Limit       int       // 0 means no limit
+}
+
+// CustomKudoTypeDecision changes the review state of a custom kudo type, see UpdateCustomKudoTypeStatus
+type CustomKudoTypeDecision struct {
+	From           string // the state the type must still be in, e.g. "" before admins are asked
+	To             string
+	ResolvedTypeID string
+	ResolvedBy     string // empty when admins are only asked, the resolution fields are then left as they are
+	ResolvedAt     time.Time
+}
+
+// AuditQuery filters the entries returned by ListAuditEntries
+// Zero-valued fields are ignored
+type AuditQuery struct {
+	ActorID string
+	Action  string
+	From    time.Time // inclusive
+	To      time.Time // exclusive
+}
+
+// Store interface for persisting kudos and related data
+type Store interface {
+	// SaveKudo stores a kudo, assigning an ID if it doesn't have one yet and replacing the one with the same ID
+	SaveKudo(ctx context.Context, kudo *models.Kudo) error
+	// SaveKudoWithinAllowance atomically stores the kudo only if the points spent on the kudos matching the query
+	// plus its own points fit the allowance, it returns false without saving otherwise
+	SaveKudoWithinAllowance(ctx context.Context, kudo *models.Kudo, query KudoQuery, allowance int) (bool, error)
+	// DeleteKudo removes a kudo, missing kudos are ignored
+	DeleteKudo(ctx context.Context, id string) error
+	// ListKudos returns the kudos matching the query ordered by creation time
+	ListKudos(ctx context.Context, query KudoQuery) ([]*models.Kudo, error)
+	// SaveReminderPreference stores the user's reminder preference, replacing the previous one
+	SaveReminderPreference(ctx context.Context, preference *models.ReminderPreference) error
+	// ListReminderPreferences returns the reminder preferences of every user who set one
+	ListReminderPreferences(ctx context.Context) ([]*models.ReminderPreference, error)
+	// SaveReminderDelivery records a reminder sent to a user, once per user and period
+	SaveReminderDelivery(ctx context.Context, delivery *models.ReminderDelivery) error
+	// ListReminderDeliveries returns the reminders delivered in the period
+	ListReminderDeliveries(ctx context.Context, period string) ([]*models.ReminderDelivery, error)
+	// ClaimKey atomically reserves an idempotency key until expiresAt
+	// It returns false when the key is already reserved and hasn't expired at now
+	ClaimKey(ctx context.Context, key string, now, expiresAt time.Time) (bool, error)
+	// ReleaseKey frees a reserved key so the operation can be retried
+	ReleaseKey(ctx context.Context, key string) error
+	// GetModerationRules returns the saved moderation rules, or nil when none were saved
+	GetModerationRules(ctx context.Context) (*models.ModerationRules, error)
+	// SaveModerationRules replaces the moderation rules
+	SaveModerationRules(ctx context.Context, rules *models.ModerationRules) error
+	// GetCustomKudoType returns a saved custom kudo type, or nil when it doesn't exist
+	GetCustomKudoType(ctx context.Context, id string) (*models.CustomKudoType, error)
+	// SaveCustomKudoType stores a custom kudo type, replacing the one with the same ID
+	SaveCustomKudoType(ctx context.Context, kudoType *models.CustomKudoType) error
+	// RecordCustomKudoTypeUse counts a kudo posted with a custom type in a single transaction, creating the type on
+	// its first use. The emoji and last use follow the given type, and the saved type is returned
+	RecordCustomKudoTypeUse(ctx context.Context, use *models.CustomKudoType) (*models.CustomKudoType, error)
+	// UpdateCustomKudoTypeStatus moves a custom kudo type to decision.To in a transaction when it's still in
+	// decision.From, writing only the review fields so concurrent uses keep their counts
+	// It returns the updated type, or nil when it's missing or already left decision.From
+	UpdateCustomKudoTypeStatus(ctx context.Context, id string, decision CustomKudoTypeDecision) (*models.CustomKudoType, error)
+	// ListCustomKudoTypes returns the workspace's custom kudo types, most recently used first
+	ListCustomKudoTypes(ctx context.Context, teamID string) ([]*models.CustomKudoType, error)
+	// GetKudoType returns an official kudo type added by admins, or nil when it doesn't exist
+	GetKudoType(ctx context.Context, id string) (*models.KudoType, error)
+	// SaveKudoType stores an official kudo type, replacing the one with the same ID
+	SaveKudoType(ctx context.Context, kudoType *models.KudoType) error
+	// ListKudoTypes returns the official kudo types added by admins, oldest first
+	ListKudoTypes(ctx context.Context) ([]*models.KudoType, error)
+	// DeleteKudoType removes an official kudo type added by admins, missing types are ignored
+	DeleteKudoType(ctx context.Context, id string) error
+	// GetSettings returns the settings saved by admins, or nil when none were saved
+	GetSettings(ctx context.Context) (*models.Settings, error)
+	// SaveSettings replaces the settings
+	SaveSettings(ctx context.Context, settings *models.Settings) error
+	// AppendAuditEntry adds an entry to the audit log, assigning an ID if it doesn't have one yet
+	// Entries are never replaced, appending an existing ID fails
+	AppendAuditEntry(ctx context.Context, entry *models.AuditEntry) error
+	// ListAuditEntries returns the audit entries matching the query, oldest first
+	ListAuditEntries(ctx context.Context, query AuditQuery) ([]*models.AuditEntry, error)
+}
+
+// reminderDeliveryID identifies the delivery of a period's reminder to a user
+func reminderDeliveryID(period, userID string) string {
+	return period + "_" + userID
+}
+
+// checkDocumentID rejects IDs Firestore can't use as document IDs, so the memory store fails like Firestore would
+func checkDocumentID(id string) error {
+	if id == "" || id == "." || id == ".." || strings.Contains(id, "/") || len(id) > 1500 ||
+		(strings.HasPrefix(id, "__") && strings.HasSuffix(id, "__")) {
+		return fmt.Errorf("invalid document ID %q", id)
+	}
+	return nil
+}
+
+// sortByLastUse orders custom kudo types from the most to the least recently used
+func sortByLastUse(kudoTypes []*models.CustomKudoType) {
+	sort.SliceStable(kudoTypes, func(i, j int) bool {
+		return kudoTypes[i].LastUsedAt.After(kudoTypes[j].LastUsedAt)
+	})
+}
+
+// applyCustomKudoTypeUse counts a use of the type, whose emoji and last use follow the latest kudo
+func applyCustomKudoTypeUse(kudoType, use *models.CustomKudoType) {
+	if !use.LastUsedAt.Before(kudoType.LastUsedAt) {
+		kudoType.Emoji = use.Emoji
+		kudoType.LastUsedAt = use.LastUsedAt
+	}
+	kudoType.UsageCount++
+}
+
+// applyCustomKudoTypeDecision sets the review fields of the decision
+func applyCustomKudoTypeDecision(kudoType *models.CustomKudoType, decision CustomKudoTypeDecision) {
+	kudoType.Status = decision.To
+	if decision.ResolvedBy != "" {
+		kudoType.ResolvedTypeID = decision.ResolvedTypeID
+		kudoType.ResolvedBy = decision.ResolvedBy
+		kudoType.ResolvedAt = decision.ResolvedAt
+	}
+}
+
+// sortByCreation orders official kudo types from the oldest to the newest
+func sortByCreation(kudoTypes []*models.KudoType) {
+	sort.SliceStable(kudoTypes, func(i, j int) bool {
+		return kudoTypes[i].CreatedAt.Before(kudoTypes[j].CreatedAt)
+	})
+}
+
+// matches reports whether a kudo satisfies the query filters
+func (q KudoQuery) matches(kudo *models.Kudo) bool {
+	if q.SenderID != "" && kudo.SenderID != q.SenderID {
+		return false
+	}
+	if q.RecipientID != "" && !slices.Contains(kudo.RecipientIDs, q.RecipientID) {
+		return false
+	}
+	if !q.From.IsZero() && kudo.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !kudo.CreatedAt.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+// matches reports whether an audit entry satisfies the query filters
+func (q AuditQuery) matches(entry *models.AuditEntry) bool {
+	if q.ActorID != "" && entry.ActorID != q.ActorID {
+		return false
+	}
+	if q.Action != "" && entry.Action != q.Action {
+		return false
+	}
+	if !q.From.IsZero() && entry.CreatedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !entry.CreatedAt.Before(q.To) {
+		return false
+	}
+	return true
+}
@@ -1,8 +1,50 @@
 package templates
 
 import (
-	_ "embed"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
 )
 
 //go:embed give-kudos.json
 var GiveKudosViewTemplate string
+
+//go:embed kudo-*.json.tmpl
+var kudoTemplates embed.FS
+
+// DefaultKudoVariant is the built-in kudos message used when KUDOS_MESSAGE_TEMPLATE is not set
+const DefaultKudoVariant = "classic"
+
+// KudoVariants are the built-in kudos message templates
+var KudoVariants = []string{"classic", "compact", "avatars"}
+
+// kudoFuncs are the functions available to kudos message templates
+var kudoFuncs = template.FuncMap{
+	// json encodes a value as a JSON literal, so text can be placed in the Block Kit JSON safely
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+// KudoTemplate returns a built-in kudos message template
+func KudoTemplate(variant string) (*template.Template, error) {
+	if !slices.Contains(KudoVariants, variant) {
+		return nil, fmt.Errorf("unknown kudos message template %q, want one of %s", variant, strings.Join(KudoVariants, ", "))
+	}
+	text, err := kudoTemplates.ReadFile("kudo-" + variant + ".json.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	return ParseKudoTemplate(variant, string(text))
+}
+
+// ParseKudoTemplate parses a kudos message template
+// The template renders a JSON array of Block Kit blocks from a services.KudoMessage
+func ParseKudoTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(kudoFuncs).Option("missingkey=error").Parse(text)
+}
@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported values of TRACES_EXPORTER
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// DefaultServiceName is reported when K_SERVICE is not set
+const DefaultServiceName = "my-matter"
+
+// tracer creates spans from the global provider, a no-op until Setup installs one
+var tracer = otel.Tracer("github.com/vyper/my-matter")
+
+// propagator reads the W3C traceparent header of incoming requests
+var propagator = propagation.TraceContext{}
+
+// flusher is the installed provider, nil when tracing is disabled
+var flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Setup installs the tracer provider selected by TRACES_EXPORTER
+// The OTLP exporter is configured with the standard OTEL_EXPORTER_OTLP_* variables
+func Setup(ctx context.Context, getenv func(string) string) error {
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch name := getenv("TRACES_EXPORTER"); name {
+	case "", ExporterNone:
+		return nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return fmt.Errorf("unknown TRACES_EXPORTER %q", name)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating trace exporter: %w", err)
+	}
+
+	serviceName := getenv("K_SERVICE")
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	flusher = provider
+	return nil
+}
+
+// Flush exports pending spans, used before event-driven functions return
+func Flush(ctx context.Context) error {
+	if flusher == nil {
+		return nil
+	}
+	return flusher.ForceFlush(ctx)
+}
+
+// Start starts a span as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartClient starts a span for an outbound call
+func StartClient(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindClient))
+}
+
+// End records err on the span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract returns the request context with the caller's span, so our spans join its trace
+// Supports the W3C traceparent header and Google's X-Cloud-Trace-Context
+func Extract(r *http.Request) context.Context {
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	if spanContext, ok := parseCloudTraceContext(r.Header.Get("X-Cloud-Trace-Context")); ok {
+		return trace.ContextWithRemoteSpanContext(ctx, spanContext)
+	}
+	return ctx
+}
+
+// parseCloudTraceContext parses the TRACE_ID/SPAN_ID;o=OPTIONS header
+// The span ID is a decimal number
+func parseCloudTraceContext(header string) (trace.SpanContext, bool) {
+	traceHex, rest, found := strings.Cut(header, "/")
+	if !found {
+		return trace.SpanContext{}, false
+	}
+	spanDecimal, options, _ := strings.Cut(rest, ";")
+
+	traceID, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+
+	spanNumber, err := strconv.ParseUint(spanDecimal, 10, 64)
+	if err != nil || spanNumber == 0 {
+		return trace.SpanContext{}, false
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], spanNumber)
+
+	var flags trace.TraceFlags
+	if options == "o=1" {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}), true
+}
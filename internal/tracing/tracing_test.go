@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{name: "unset"},
+		{name: "none", exporter: ExporterNone},
+		{name: "unknown", exporter: "zipkin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				if key == "TRACES_EXPORTER" {
+					return tt.exporter
+				}
+				return ""
+			}
+
+			err := Setup(context.Background(), getenv)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		expectedTrace string
+		expectedSpan  string
+		sampled       bool
+	}{
+		{
+			name:          "traceparent",
+			headers:       map[string]string{"traceparent": "00-105445aa7843bc8bf206b12000100000-00f067aa0ba902b7-01"},
+			expectedTrace: "105445aa7843bc8bf206b12000100000",
+			expectedSpan:  "00f067aa0ba902b7",
+			sampled:       true,
+		},
+		{
+			name:          "cloud trace context",
+			headers:       map[string]string{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/1;o=1"},
+			expectedTrace: "105445aa7843bc8bf206b12000100000",
+			expectedSpan:  "0000000000000001",
+			sampled:       true,
+		},
+		{
+			name:          "cloud trace context not sampled",
+			headers:       map[string]string{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/255;o=0"},
+			expectedTrace: "105445aa7843bc8bf206b12000100000",
+			expectedSpan:  "00000000000000ff",
+		},
+		{
+			name: "traceparent wins",
+			headers: map[string]string{
+				"traceparent":           "00-105445aa7843bc8bf206b12000100000-00f067aa0ba902b7-00",
+				"X-Cloud-Trace-Context": "205445aa7843bc8bf206b12000100000/1;o=1",
+			},
+			expectedTrace: "105445aa7843bc8bf206b12000100000",
+			expectedSpan:  "00f067aa0ba902b7",
+		},
+		{name: "trace without span", headers: map[string]string{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000"}},
+		{name: "invalid trace", headers: map[string]string{"X-Cloud-Trace-Context": "not-a-trace/1;o=1"}},
+		{name: "invalid span", headers: map[string]string{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/abc"}},
+		{name: "no headers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", nil)
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			spanContext := trace.SpanContextFromContext(Extract(r))
+
+			if tt.expectedTrace == "" {
+				if spanContext.IsValid() {
+					t.Errorf("Extract() span context = %v, want none", spanContext)
+				}
+				return
+			}
+			if got := spanContext.TraceID().String(); got != tt.expectedTrace {
+				t.Errorf("trace ID = %s, want %s", got, tt.expectedTrace)
+			}
+			if got := spanContext.SpanID().String(); got != tt.expectedSpan {
+				t.Errorf("span ID = %s, want %s", got, tt.expectedSpan)
+			}
+			if spanContext.IsSampled() != tt.sampled {
+				t.Errorf("sampled = %v, want %v", spanContext.IsSampled(), tt.sampled)
+			}
+			if !spanContext.IsRemote() {
+				t.Error("span context should be remote")
+			}
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "success", wantStatus: codes.Unset},
+		{name: "error", err: errors.New("channel_not_found"), wantStatus: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := tracer.Start(context.Background(), tt.name)
+			End(span, tt.err)
+
+			ended := recorder.Ended()
+			got := ended[len(ended)-1]
+			if got.Name() != tt.name {
+				t.Fatalf("last ended span = %s, want %s", got.Name(), tt.name)
+			}
+			if got.Status().Code != tt.wantStatus {
+				t.Errorf("status = %v, want %v", got.Status().Code, tt.wantStatus)
+			}
+			if tt.err != nil && len(got.Events()) == 0 {
+				t.Error("error should be recorded as a span event")
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package function
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/vyper/my-matter/functions/managernotify"
+)
+
+// ManagerNotify is the Cloud Function entry point for the Pub/Sub triggered daily manager notifications
+func ManagerNotify(ctx context.Context, e event.Event) error {
+	return managernotify.HandleManagerNotify(ctx, e)
+}